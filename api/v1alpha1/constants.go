@@ -8,7 +8,76 @@ const (
 	DefaultIPXEScriptKey      = "ipxe-script"             // Key for accessing iPXE script data within the iPXE-specific Secret object.
 	SystemUUIDIndexKey        = "spec.systemUUID"         // Field to index resources by their system UUID.
 	SystemIPIndexKey          = "spec.systemIPs"          // Field to index resources by their system IP addresses.
+	SystemMACIndexKey         = "spec.systemMACs"         // Field to index resources by their system MAC addresses.
 	NetworkIdentifierIndexKey = "spec.networkIdentifiers" // Field to index resources by their network identifiers (IP addresses and MAC addresses).
-	DefaultFormatKey          = "format"                  // Key for determining the format of the data stored in a Secret, such as fcos or plain-ignition.
+	ImagePullSecretIndexKey   = "imagePullSecret"         // Field to index ServerBootConfigurations by the pull secret named by their image pull secret annotation.
+	LayerDigestIndexKey       = "layerDigests"            // Field to index IPXEBootConfigs by the digests of the image layers their artifact URLs point at.
+	DefaultFormatKey          = "format"                  // Key for determining the format of the data stored in a Secret, such as fcos or cloud-init.
 	FCOSFormat                = "fcos"                    // Specifies the format value used for Fedora CoreOS specific configurations.
+	IgnitionFormat            = "ignition"                // Specifies the format value used for Ignition configs in JSON, which is the default.
+	FlatcarFormat             = "flatcar"                 // Specifies the format value used for Butane configs of the Flatcar variant.
+	CloudInitFormat           = "cloud-init"              // Specifies the format value used for cloud-init user-data.
+	AutoinstallFormat         = "autoinstall"             // Specifies the format value used for Ubuntu autoinstall configs.
+	DefaultMetaDataKey        = "meta-data"               // Key for accessing the NoCloud meta-data within the ignition Secret object.
+	DefaultVendorDataKey      = "vendor-data"             // Key for accessing the NoCloud vendor-data within the ignition Secret object.
+	DefaultTemplateKey        = "template"                // Key for enabling the rendering of the data stored in the ignition or iPXE script Secret as a Go template.
+	DefaultIPXETemplateKey    = "ipxe-template"           // Key for accessing the iPXE script template within the iPXE template ConfigMap object.
+)
+
+const (
+	// ImagePullSecretAnnotation names a kubernetes.io/dockerconfigjson Secret in the namespace of a
+	// ServerBootConfiguration, overriding the globally configured registry pull secret for its image.
+	ImagePullSecretAnnotation = "boot.ironcore.dev/image-pull-secret"
+
+	// IgnitionPolicyAnnotation sets the IgnitionPolicy of the boot config created for a
+	// ServerBootConfiguration.
+	IgnitionPolicyAnnotation = "boot.ironcore.dev/ignition-policy"
+
+	// IgnitionLayersAnnotation lists the names of ignition Secrets, separated by commas, that are set
+	// as the ignition layers of the boot config created for a ServerBootConfiguration.
+	IgnitionLayersAnnotation = "boot.ironcore.dev/ignition-layers"
+
+	// IPXETemplateAnnotation names a ConfigMap in the namespace of a ServerBootConfiguration that is
+	// set as the IPXETemplateRef of the IPXEBootConfig created for it.
+	IPXETemplateAnnotation = "boot.ironcore.dev/ipxe-template"
+
+	// KernelArgsAnnotation lists kernel arguments, separated by whitespace, that are set as the
+	// KernelArgs of the IPXEBootConfig created for a ServerBootConfiguration.
+	KernelArgsAnnotation = "boot.ironcore.dev/kernel-args"
+
+	// RearmIgnitionAnnotation on an IPXEBootConfig or HTTPBootConfig allows its ignition data to be
+	// delivered again, e.g. to reprovision a server. The annotation is removed once processed.
+	RearmIgnitionAnnotation = "boot.ironcore.dev/rearm-ignition"
+)
+
+const (
+	// SystemUUIDAmbiguousCondition is set on boot configs whose SystemUUID does not identify a single
+	// server, because it is invalid, a known vendor placeholder, or shared with a boot config of another
+	// Server. The boot-server matches such boot configs by the address of the client instead.
+	SystemUUIDAmbiguousCondition = "SystemUUIDAmbiguous"
+
+	InvalidSystemUUIDReason     = "InvalidSystemUUID"     // The SystemUUID is not a valid SMBIOS UUID.
+	PlaceholderSystemUUIDReason = "PlaceholderSystemUUID" // The SystemUUID is a placeholder shipped by the vendor.
+	DuplicateSystemUUIDReason   = "DuplicateSystemUUID"   // The SystemUUID is shared with a boot config of another Server.
+)
+
+const (
+	// IgnitionValidCondition reports whether the ignition data referenced by a boot config can be
+	// served as an Ignition config. Warnings of the parser are reported in its message.
+	IgnitionValidCondition = "IgnitionValid"
+
+	ValidIgnitionReason    = "ValidIgnition"    // The ignition data is valid.
+	IgnitionWarningsReason = "IgnitionWarnings" // The ignition data is valid, but the parser reported warnings.
+	InvalidIgnitionReason  = "InvalidIgnition"  // The ignition data cannot be parsed according to its format.
+	MissingIgnitionReason  = "MissingIgnition"  // The ignition Secret or its ignition key does not exist.
+)
+
+const (
+	// IPXETemplateValidCondition reports whether the iPXE template referenced by an IPXEBootConfig, or
+	// its custom iPXE script if that is templated, renders into an iPXE script.
+	IPXETemplateValidCondition = "IPXETemplateValid"
+
+	ValidIPXETemplateReason   = "ValidIPXETemplate"   // The iPXE template renders into an iPXE script.
+	InvalidIPXETemplateReason = "InvalidIPXETemplate" // The iPXE template cannot be parsed or rendered.
+	MissingIPXETemplateReason = "MissingIPXETemplate" // The iPXE template ConfigMap or its ipxe-template key, or the iPXE script Secret or its ipxe-script key, does not exist.
 )
@@ -17,6 +17,15 @@ type HTTPBootConfigSpec struct {
 	// IgnitionSecretRef is a reference to the secret containing Ignition configuration.
 	IgnitionSecretRef *corev1.LocalObjectReference `json:"ignitionSecretRef,omitempty"`
 
+	// IgnitionLayers is an ordered list of ignition data that the ignition data of the
+	// IgnitionSecretRef is merged onto, e.g. OS hardening and site configuration that is shared by
+	// many servers. Each layer is merged onto the layers before it.
+	IgnitionLayers []IgnitionLayer `json:"ignitionLayers,omitempty"`
+
+	// IgnitionPolicy controls whether the ignition data may be fetched again after it has been
+	// delivered. Defaults to Always.
+	IgnitionPolicy IgnitionPolicy `json:"ignitionPolicy,omitempty"`
+
 	// NetworkIdentifiers is a list of IP addresses and MAC Addresses assigned to the server.
 	NetworkIdentifiers []string `json:"networkIdentifiers,omitempty"`
 
@@ -30,6 +39,9 @@ type HTTPBootConfigStatus struct {
 
 	// Conditions represent the latest available observations of the IPXEBootConfig's state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// IgnitionToken authenticates requests for the ignition data of the current boot.
+	IgnitionToken *IgnitionToken `json:"ignitionToken,omitempty"`
 }
 
 type HTTPBootConfigState string
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IgnitionLayer is a layer of ignition data that the boot-server merges with the other layers of a
+// boot config into the Ignition config it serves.
+type IgnitionLayer struct {
+	// SecretRef is a reference to the secret containing the ignition data of the layer. The data
+	// may be in any format that renders into an Ignition config, and may be templated.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+
+	// Strategy controls how the layer is combined with the layers before it. Defaults to Merge.
+	// +optional
+	Strategy IgnitionLayerStrategy `json:"strategy,omitempty"`
+}
+
+// IgnitionLayerStrategy controls how an IgnitionLayer is combined with the layers before it.
+// +kubebuilder:validation:Enum=Merge;Replace
+type IgnitionLayerStrategy string
+
+const (
+	// IgnitionLayerStrategyMerge merges the layer into the layers before it with the merge semantics
+	// of Ignition, i.e. its fields take precedence. This is the default.
+	IgnitionLayerStrategyMerge IgnitionLayerStrategy = "Merge"
+
+	// IgnitionLayerStrategyReplace discards the layers before it, like the replace directive of
+	// Ignition.
+	IgnitionLayerStrategyReplace IgnitionLayerStrategy = "Replace"
+)
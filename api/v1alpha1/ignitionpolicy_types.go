@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+// IgnitionPolicy controls how often the boot-server delivers the ignition data of a boot config.
+// +kubebuilder:validation:Enum=Always;Once;UntilReady
+type IgnitionPolicy string
+
+const (
+	// IgnitionPolicyAlways serves the ignition data on every request. This is the default.
+	IgnitionPolicyAlways IgnitionPolicy = "Always"
+
+	// IgnitionPolicyOnce serves the ignition data a single time per generation of the boot config.
+	IgnitionPolicyOnce IgnitionPolicy = "Once"
+
+	// IgnitionPolicyUntilReady serves the ignition data until the booted OS has reported readiness
+	// through the OSReady condition.
+	IgnitionPolicyUntilReady IgnitionPolicy = "UntilReady"
+)
+
+const (
+	// IgnitionDataFetchedCondition is set by the boot-server once the ignition data has been delivered.
+	IgnitionDataFetchedCondition = "IgnitionDataFetched"
+
+	// OSReadyCondition is set once the booted OS has reported readiness to the boot-server.
+	OSReadyCondition = "OSReady"
+)
@@ -0,0 +1,21 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IgnitionToken is a short-lived credential a server presents when fetching its ignition data.
+type IgnitionToken struct {
+	// Token is passed in the token query parameter of ignition requests.
+	Token string `json:"token"`
+
+	// ExpirationTime is the time after which the token is no longer accepted.
+	ExpirationTime metav1.Time `json:"expirationTime"`
+
+	// ObservedGeneration is the generation of the boot config the token was minted for.
+	// A new token is minted for every new generation, i.e. for every new boot.
+	ObservedGeneration int64 `json:"observedGeneration"`
+}
@@ -18,7 +18,10 @@ type IPXEBootConfigSpec struct {
 	SystemUUID string `json:"systemUUID,omitempty"`
 
 	// SystemIPs is a list of IP addresses assigned to the server.
-	SystemIPs []string `json:"systemIPs,omitempty"` // TODO: Implement custom serialization. Currently, validation should occur at the controller.
+	SystemIPs []string `json:"systemIPs,omitempty"`
+
+	// SystemMACs is a list of MAC addresses of the network interfaces of the server.
+	SystemMACs []string `json:"systemMACs,omitempty"`
 
 	// Image is deprecated and will be removed.
 	Image string `json:"image,omitempty"`
@@ -36,14 +39,35 @@ type IPXEBootConfigSpec struct {
 	// SquashfsURL is the URL where the Squashfs of the OS is hosted, eg.  the URL to the Squashfs layer of the OS OCI image.
 	SquashfsURL string `json:"squashfsURL,omitempty"`
 
+	// KernelArgs are added to the kernel command line of the IPXE script, replacing the default
+	// arguments with the same key, e.g. console=ttyS1,115200 replaces all default console arguments.
+	// Arguments prefixed with "-" remove the default arguments with their key, e.g. -earlyprintk.
+	KernelArgs []string `json:"kernelArgs,omitempty"`
+
 	// IPXEServerURL is deprecated and will be removed.
 	IPXEServerURL string `json:"ipxeServerURL,omitempty"`
 
 	// IgnitionSecretRef is a reference to the secret containing the Ignition configuration.
 	IgnitionSecretRef *corev1.LocalObjectReference `json:"ignitionSecretRef,omitempty"`
 
+	// IgnitionLayers is an ordered list of ignition data that the ignition data of the
+	// IgnitionSecretRef is merged onto, e.g. OS hardening and site configuration that is shared by
+	// many servers. Each layer is merged onto the layers before it.
+	IgnitionLayers []IgnitionLayer `json:"ignitionLayers,omitempty"`
+
+	// IgnitionPolicy controls whether the ignition data may be fetched again after it has been
+	// delivered. Defaults to Always.
+	IgnitionPolicy IgnitionPolicy `json:"ignitionPolicy,omitempty"`
+
 	// IPXEScriptSecretRef is a reference to the secret containing the custom IPXE script.
+	// The script is rendered as a template with the same data as the built-in template if the
+	// template key of the Secret is true.
 	IPXEScriptSecretRef *corev1.LocalObjectReference `json:"ipxeScriptSecretRef,omitempty"`
+
+	// IPXETemplateRef is a reference to the ConfigMap containing the template that the IPXE script is
+	// rendered from, e.g. to customize the kernel command line. Defaults to the built-in template.
+	// It is ignored if IPXEScriptSecretRef is set.
+	IPXETemplateRef *corev1.LocalObjectReference `json:"ipxeTemplateRef,omitempty"`
 }
 
 type IPXEBootConfigState string
@@ -66,6 +90,9 @@ type IPXEBootConfigStatus struct {
 
 	// Conditions represent the latest available observations of the IPXEBootConfig's state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// IgnitionToken authenticates requests for the ignition data of the current boot.
+	IgnitionToken *IgnitionToken `json:"ignitionToken,omitempty"`
 }
 
 //+kubebuilder:object:root=true
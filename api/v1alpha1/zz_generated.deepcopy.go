@@ -80,6 +80,11 @@ func (in *HTTPBootConfigSpec) DeepCopyInto(out *HTTPBootConfigSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.IgnitionLayers != nil {
+		in, out := &in.IgnitionLayers, &out.IgnitionLayers
+		*out = make([]IgnitionLayer, len(*in))
+		copy(*out, *in)
+	}
 	if in.NetworkIdentifiers != nil {
 		in, out := &in.NetworkIdentifiers, &out.NetworkIdentifiers
 		*out = make([]string, len(*in))
@@ -107,6 +112,11 @@ func (in *HTTPBootConfigStatus) DeepCopyInto(out *HTTPBootConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IgnitionToken != nil {
+		in, out := &in.IgnitionToken, &out.IgnitionToken
+		*out = new(IgnitionToken)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPBootConfigStatus.
@@ -186,16 +196,36 @@ func (in *IPXEBootConfigSpec) DeepCopyInto(out *IPXEBootConfigSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SystemMACs != nil {
+		in, out := &in.SystemMACs, &out.SystemMACs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KernelArgs != nil {
+		in, out := &in.KernelArgs, &out.KernelArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.IgnitionSecretRef != nil {
 		in, out := &in.IgnitionSecretRef, &out.IgnitionSecretRef
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.IgnitionLayers != nil {
+		in, out := &in.IgnitionLayers, &out.IgnitionLayers
+		*out = make([]IgnitionLayer, len(*in))
+		copy(*out, *in)
+	}
 	if in.IPXEScriptSecretRef != nil {
 		in, out := &in.IPXEScriptSecretRef, &out.IPXEScriptSecretRef
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.IPXETemplateRef != nil {
+		in, out := &in.IPXETemplateRef, &out.IPXETemplateRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPXEBootConfigSpec.
@@ -218,6 +248,11 @@ func (in *IPXEBootConfigStatus) DeepCopyInto(out *IPXEBootConfigStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.IgnitionToken != nil {
+		in, out := &in.IgnitionToken, &out.IgnitionToken
+		*out = new(IgnitionToken)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPXEBootConfigStatus.
@@ -229,3 +264,35 @@ func (in *IPXEBootConfigStatus) DeepCopy() *IPXEBootConfigStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IgnitionLayer) DeepCopyInto(out *IgnitionLayer) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IgnitionLayer.
+func (in *IgnitionLayer) DeepCopy() *IgnitionLayer {
+	if in == nil {
+		return nil
+	}
+	out := new(IgnitionLayer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IgnitionToken) DeepCopyInto(out *IgnitionToken) {
+	*out = *in
+	in.ExpirationTime.DeepCopyInto(&out.ExpirationTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IgnitionToken.
+func (in *IgnitionToken) DeepCopy() *IgnitionToken {
+	if in == nil {
+		return nil
+	}
+	out := new(IgnitionToken)
+	in.DeepCopyInto(out)
+	return out
+}
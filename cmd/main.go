@@ -8,8 +8,12 @@ import (
 	"crypto/tls"
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/ironcore-dev/controller-utils/cmdutils/switches"
 	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
@@ -19,6 +23,7 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -33,8 +38,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/blobcache"
 	"github.com/ironcore-dev/boot-operator/internal/controller"
+	"github.com/ironcore-dev/boot-operator/internal/ipxe"
+	"github.com/ironcore-dev/boot-operator/internal/netid"
+	"github.com/ironcore-dev/boot-operator/internal/prefetch"
 	"github.com/ironcore-dev/boot-operator/internal/registry"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	webhookv1alpha1 "github.com/ironcore-dev/boot-operator/internal/webhook/v1alpha1"
 	bootserver "github.com/ironcore-dev/boot-operator/server"
 	//+kubebuilder:scaffold:imports
 )
@@ -68,6 +79,8 @@ func main() {
 
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
+	var webhookCertPath, webhookCertName, webhookCertKey string
+	var serverCertPath, serverCertName, serverCertKey string
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
@@ -75,6 +88,7 @@ func main() {
 	var bootserverAddr string
 	var imageProxyServerAddr string
 	var ipxeServiceURL string
+	var ipxeTemplateDir string
 	var ipxeServiceProtocol string
 	var ipxeServicePort int
 	var imageServerURL string
@@ -82,11 +96,29 @@ func main() {
 	var allowedRegistries string
 	var defaultHTTPBootOCIImage string
 	var defaultHTTPBootUKIURL string
+	var registryPullSecret string
+	var imageCacheDir string
+	var imageCacheSize string
+	var prefetchBootArtifacts bool
+	var tftpServerAddr string
+	var tftpIPXEDir string
+	var proxyDHCPServerIP string
+	var ipxeSigningSecret string
+	var bootserverTLSAddr string
+	var imageProxyServerTLSAddr string
+	var caBundlePath string
+	var ignitionTokenTTL time.Duration
+	var ignitionTokenBindSourceIP bool
+	var verifySourceIP bool
+	var trustedProxies string
 
 	flag.StringVar(&architecture, "architecture", "amd64", "Target system architecture (e.g., amd64, arm64)")
 	flag.IntVar(&ipxeServicePort, "ipxe-service-port", 5000, "IPXE Service port to listen on.")
 	flag.StringVar(&ipxeServiceProtocol, "ipxe-service-protocol", "http", "IPXE Service Protocol.")
 	flag.StringVar(&ipxeServiceURL, "ipxe-service-url", "", "IPXE Service URL.")
+	flag.StringVar(&ipxeTemplateDir, "ipxe-template-dir", "",
+		"Directory containing iPXE script templates that override the built-in ones of the same name. Changes are "+
+			"reloaded without a restart.")
 	flag.StringVar(&imageServerURL, "image-server-url", "", "OS Image Server URL.")
 	flag.StringVar(&defaultHTTPBootOCIImage, "default-httpboot-oci-image", "", "Default OCI image reference for http boot")
 	flag.StringVar(&defaultHTTPBootUKIURL, "default-httpboot-uki-url", "", "Deprecated: use --default-httpboot-oci-image")
@@ -94,16 +126,62 @@ func main() {
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.StringVar(&bootserverAddr, "boot-server-address", ":8082", "The address the boot-server binds to.")
 	flag.StringVar(&imageProxyServerAddr, "image-proxy-server-address", ":8083", "The address the image-proxy-server binds to.")
+	flag.StringVar(&bootserverTLSAddr, "boot-server-tls-address", "",
+		"The address the HTTPS listener of the boot-server binds to, e.g. :8443. Requires --server-cert-path.")
+	flag.StringVar(&imageProxyServerTLSAddr, "image-proxy-server-tls-address", "",
+		"The address the HTTPS listener of the image-proxy-server binds to, e.g. :8444. Requires --server-cert-path.")
+	flag.StringVar(&serverCertPath, "server-cert-path", "",
+		"The directory that contains the certificate of the boot-server and image-proxy-server HTTPS listeners.")
+	flag.StringVar(&serverCertName, "server-cert-name", "tls.crt", "The name of the boot-server certificate file.")
+	flag.StringVar(&serverCertKey, "server-cert-key", "tls.key", "The name of the boot-server key file.")
+	flag.StringVar(&caBundlePath, "ca-bundle-path", "",
+		"Path to a PEM encoded CA bundle the boot-server serves at "+bootserver.CABundlePEMPath+" and "+
+			bootserver.CABundleDERPath+" for firmware and iPXE enrollment. Not served if not set.")
+	flag.DurationVar(&ignitionTokenTTL, "ignition-token-ttl", 0,
+		"Lifetime of the per-boot tokens minted for IPXEBootConfigs and HTTPBootConfigs. If set, the boot-server only "+
+			"serves ignition data to requests carrying the current token, and iPXE scripts only to the system IPs of their "+
+			"IPXEBootConfig. Disabled if zero.")
+	flag.BoolVar(&ignitionTokenBindSourceIP, "ignition-token-bind-source-ip", false,
+		"Additionally require ignition requests to come from a known IP address of the server. Requires --ignition-token-ttl.")
+	flag.BoolVar(&verifySourceIP, "verify-source-ip", false,
+		"Only serve iPXE scripts and ignition data to requests coming from a known IP address of the server.")
+	flag.StringVar(&trustedProxies, "trusted-proxies", "",
+		"Comma-separated list of CIDRs of reverse proxies whose Forwarded and X-Forwarded-For headers are trusted to carry "+
+			"the client address.")
+	flag.StringVar(&tftpServerAddr, "tftp-server-address", "",
+		"The UDP address the TFTP server serving iPXE binaries to PXE firmware binds to, e.g. :69. Disabled if not set.")
+	flag.StringVar(&tftpIPXEDir, "tftp-ipxe-dir", "ipxe",
+		"Directory containing the iPXE binaries served by the TFTP server, in one subdirectory per architecture.")
+	flag.StringVar(&proxyDHCPServerIP, "proxy-dhcp-server-ip", "",
+		"IPv4 address of this host announced by the ProxyDHCP responder, which answers PXE and UEFI HTTP boot "+
+			"clients on UDP ports 67 and 4011 with their boot file. Disabled if not set.")
+	flag.StringVar(&ipxeSigningSecret, "ipxe-signing-secret", "",
+		"Namespace/name of a kubernetes.io/tls Secret holding a code signing certificate and key. If set, iPXE scripts "+
+			"and proxied images get detached signatures under a .sig suffix and the default iPXE scripts verify them "+
+			"with imgverify.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&metricsCertPath, "metrics-cert-path", "", "The directory that contains the metrics server certificate.")
 	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
+	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
+	flag.StringVar(&webhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
+	flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
 	flag.BoolVar(&secureMetrics, "metrics-secure", true, "If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
-		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+		"If set, HTTP/2 will be enabled for the metrics, webhook, boot and image proxy servers")
 	flag.StringVar(&allowedRegistries, "allowed-registries", "", "Comma-separated list of allowed OCI registries. Defaults to ghcr.io if not set.")
+	flag.StringVar(&registryPullSecret, "registry-pull-secret", "",
+		"Namespace/name of a kubernetes.io/dockerconfigjson Secret used to pull OS images from private registries. "+
+			"Can be overridden per ServerBootConfiguration with the "+bootv1alpha1.ImagePullSecretAnnotation+" annotation.")
+	flag.StringVar(&imageCacheDir, "image-cache-dir", "",
+		"Directory in which the image-proxy-server caches boot artifacts by digest. Caching is disabled if not set.")
+	flag.StringVar(&imageCacheSize, "image-cache-size", "10Gi",
+		"Maximum size of the image-proxy-server cache. Least recently used artifacts are evicted beyond this size.")
+	flag.BoolVar(&prefetchBootArtifacts, "prefetch-boot-artifacts", false,
+		"Prefetch the boot artifacts of a ServerBootConfiguration into the image cache and only report it Ready "+
+			"once they are cached. Requires --image-cache-dir.")
 
 	controllers := switches.New(
 		// core controllers
@@ -164,10 +242,32 @@ func main() {
 	}
 
 	// Create watchers for metrics and webhooks certificates
-	var metricsCertWatcher *certwatcher.CertWatcher
+	var metricsCertWatcher, webhookCertWatcher *certwatcher.CertWatcher
+
+	// Initial webhook TLS options
+	webhookTLSOpts := tlsOpts
+
+	if len(webhookCertPath) > 0 {
+		setupLog.Info("Initializing webhook certificate watcher using provided certificates",
+			"webhook-cert-path", webhookCertPath, "webhook-cert-name", webhookCertName, "webhook-cert-key", webhookCertKey)
+
+		var err error
+		webhookCertWatcher, err = certwatcher.New(
+			filepath.Join(webhookCertPath, webhookCertName),
+			filepath.Join(webhookCertPath, webhookCertKey),
+		)
+		if err != nil {
+			setupLog.Error(err, "Failed to initialize webhook certificate watcher")
+			os.Exit(1)
+		}
+
+		webhookTLSOpts = append(slices.Clone(webhookTLSOpts), func(config *tls.Config) {
+			config.GetCertificate = webhookCertWatcher.GetCertificate
+		})
+	}
 
 	webhookServer := webhook.NewServer(webhook.Options{
-		TLSOpts: tlsOpts,
+		TLSOpts: webhookTLSOpts,
 	})
 	// Metrics endpoint is enabled in 'config/default/kustomization.yaml'. The Metrics options configure the server.
 	// More info:
@@ -214,6 +314,38 @@ func main() {
 		})
 	}
 
+	// The boot-server and image-proxy-server share one certificate for their HTTPS listeners
+	var serverCertWatcher *certwatcher.CertWatcher
+	var bootserverTLS, imageProxyServerTLS *bootserver.TLSOptions
+	if bootserverTLSAddr != "" || imageProxyServerTLSAddr != "" {
+		if len(serverCertPath) == 0 {
+			setupLog.Error(nil, "--boot-server-tls-address and --image-proxy-server-tls-address require --server-cert-path")
+			os.Exit(1)
+		}
+		setupLog.Info("Initializing server certificate watcher using provided certificates",
+			"server-cert-path", serverCertPath, "server-cert-name", serverCertName, "server-cert-key", serverCertKey)
+
+		var err error
+		serverCertWatcher, err = certwatcher.New(
+			filepath.Join(serverCertPath, serverCertName),
+			filepath.Join(serverCertPath, serverCertKey),
+		)
+		if err != nil {
+			setupLog.Error(err, "to initialize server certificate watcher", "error", err)
+			os.Exit(1)
+		}
+
+		serverTLSOpts := append(slices.Clone(tlsOpts), func(config *tls.Config) {
+			config.GetCertificate = serverCertWatcher.GetCertificate
+		})
+		if bootserverTLSAddr != "" {
+			bootserverTLS = &bootserver.TLSOptions{Address: bootserverTLSAddr, TLSOpts: serverTLSOpts}
+		}
+		if imageProxyServerTLSAddr != "" {
+			imageProxyServerTLS = &bootserver.TLSOptions{Address: imageProxyServerTLSAddr, TLSOpts: serverTLSOpts}
+		}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:        scheme,
 		Metrics:       metricsServerOptions,
@@ -249,10 +381,73 @@ func main() {
 		setupLog.Info("Initialized registry validator", "allowedRegistries", allowedRegistries)
 	}
 
+	// Initialize the credential store for private registry access
+	var defaultPullSecret *client.ObjectKey
+	if registryPullSecret != "" {
+		namespace, name, ok := strings.Cut(registryPullSecret, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(nil, "invalid --registry-pull-secret, expected namespace/name", "registryPullSecret", registryPullSecret)
+			os.Exit(1)
+		}
+		defaultPullSecret = &client.ObjectKey{Namespace: namespace, Name: name}
+		setupLog.Info("Using registry pull secret", "secret", defaultPullSecret)
+	}
+	credentialStore := registry.NewCredentialStore(mgr.GetClient(), defaultPullSecret)
+
+	// Initialize the on-disk blob cache of the image proxy server
+	var imageCache *blobcache.Cache
+	if imageCacheDir != "" {
+		cacheSize, err := resource.ParseQuantity(imageCacheSize)
+		if err != nil {
+			setupLog.Error(err, "invalid --image-cache-size", "imageCacheSize", imageCacheSize)
+			os.Exit(1)
+		}
+		imageCache, err = blobcache.New(imageCacheDir, cacheSize.Value())
+		if err != nil {
+			setupLog.Error(err, "unable to initialize image cache", "imageCacheDir", imageCacheDir)
+			os.Exit(1)
+		}
+		setupLog.Info("Initialized image cache", "imageCacheDir", imageCacheDir, "imageCacheSize", cacheSize.String(),
+			"cachedBytes", imageCache.Size())
+	}
+
+	if ignitionTokenBindSourceIP && ignitionTokenTTL <= 0 {
+		setupLog.Error(nil, "--ignition-token-bind-source-ip requires --ignition-token-ttl")
+		os.Exit(1)
+	}
+
+	trustedProxyPrefixes, err := bootserver.ParseTrustedProxies(trustedProxies)
+	if err != nil {
+		setupLog.Error(err, "invalid --trusted-proxies")
+		os.Exit(1)
+	}
+
+	// Initialize the signer of iPXE scripts and boot artifacts
+	var signerSource *bootserver.SignerSource
+	if ipxeSigningSecret != "" {
+		namespace, name, ok := strings.Cut(ipxeSigningSecret, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(nil, "invalid --ipxe-signing-secret, expected namespace/name", "ipxeSigningSecret", ipxeSigningSecret)
+			os.Exit(1)
+		}
+		signerSource = bootserver.NewSignerSource(mgr.GetClient(), client.ObjectKey{Namespace: namespace, Name: name})
+		setupLog.Info("Signing iPXE scripts and boot artifacts", "secret", ipxeSigningSecret)
+	}
+
+	var prefetcher *prefetch.Prefetcher
+	if prefetchBootArtifacts {
+		if imageCache == nil {
+			setupLog.Error(nil, "--prefetch-boot-artifacts requires --image-cache-dir")
+			os.Exit(1)
+		}
+		prefetcher = prefetch.NewPrefetcher(imageCache, credentialStore, ctrl.Log.WithName("prefetch"))
+	}
+
 	if controllers.Enabled(ipxeBootConfigController) {
 		if err = (&controller.IPXEBootConfigReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			IgnitionTokenTTL: ignitionTokenTTL,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "IPXEBootConfig")
 			os.Exit(1)
@@ -266,6 +461,8 @@ func main() {
 			IPXEServiceURL:    ipxeServiceURL,
 			Architecture:      architecture,
 			RegistryValidator: registryValidator,
+			CredentialStore:   credentialStore,
+			Prefetcher:        prefetcher,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "ServerBootConfigPxe")
 			os.Exit(1)
@@ -279,6 +476,8 @@ func main() {
 			ImageServerURL:    imageServerURL,
 			Architecture:      architecture,
 			RegistryValidator: registryValidator,
+			CredentialStore:   credentialStore,
+			Prefetcher:        prefetcher,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "ServerBootConfigHttp")
 			os.Exit(1)
@@ -287,16 +486,52 @@ func main() {
 
 	if controllers.Enabled(httpBootConfigController) {
 		if err = (&controller.HTTPBootConfigReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			IgnitionTokenTTL: ignitionTokenTTL,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "HTTPBootConfig")
 			os.Exit(1)
 		}
 	}
 
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhookv1alpha1.SetupIPXEBootConfigWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "IPXEBootConfig")
+			os.Exit(1)
+		}
+		if err = webhookv1alpha1.SetupHTTPBootConfigWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "HTTPBootConfig")
+			os.Exit(1)
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
+	if metricsCertWatcher != nil {
+		setupLog.Info("Adding metrics certificate watcher to manager")
+		if err := mgr.Add(metricsCertWatcher); err != nil {
+			setupLog.Error(err, "unable to add metrics certificate watcher to manager")
+			os.Exit(1)
+		}
+	}
+
+	if webhookCertWatcher != nil {
+		setupLog.Info("Adding webhook certificate watcher to manager")
+		if err := mgr.Add(webhookCertWatcher); err != nil {
+			setupLog.Error(err, "unable to add webhook certificate watcher to manager")
+			os.Exit(1)
+		}
+	}
+
+	if serverCertWatcher != nil {
+		setupLog.Info("Adding server certificate watcher to manager")
+		if err := mgr.Add(serverCertWatcher); err != nil {
+			setupLog.Error(err, "unable to add server certificate watcher to manager")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -321,31 +556,112 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := IndexIPXEBootConfigBySystemMACs(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to set up indexer for IPXEBootConfig SystemMACs")
+		os.Exit(1)
+	}
+
 	if err := IndexHTTPBootConfigByNetworkIDs(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to set up indexer for HTTPBootConfig NetworkIdentifiers")
 		os.Exit(1)
 	}
 
+	if err := IndexIPXEBootConfigByLayerDigests(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to set up indexer for IPXEBootConfig layer digests")
+		os.Exit(1)
+	}
+
+	if err := IndexServerBootConfigurationByImagePullSecret(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to set up indexer for ServerBootConfiguration image pull secret")
+		os.Exit(1)
+	}
+
+	ipxeTemplates, err := ipxe.NewTemplates(ipxeTemplateDir, serverLog.WithName("ipxe-templates"))
+	if err != nil {
+		setupLog.Error(err, "unable to load iPXE templates")
+		os.Exit(1)
+	}
+	go func() {
+		if err := ipxeTemplates.Watch(ctx); err != nil {
+			setupLog.Error(err, "unable to watch iPXE templates", "ipxeTemplateDir", ipxeTemplateDir)
+		}
+	}()
+
 	setupLog.Info("starting boot-server")
 	go func() {
 		if err := bootserver.RunBootServer(
 			bootserverAddr,
 			ipxeServiceURL,
+			ipxeTemplates,
 			mgr.GetClient(),
 			serverLog.WithName("bootserver"),
 			registryValidator,
+			credentialStore,
 			defaultHTTPBootOCIImage,
 			defaultHTTPBootUKIURL,
 			imageServerURL,
 			architecture,
+			signerSource,
+			bootserverTLS,
+			caBundlePath,
+			bootserver.IgnitionAuthOptions{
+				RequireToken:        ignitionTokenTTL > 0,
+				TokenTTL:            ignitionTokenTTL,
+				BindTokenToSourceIP: ignitionTokenBindSourceIP,
+			},
+			bootserver.SourceIPOptions{
+				TrustedProxies: trustedProxyPrefixes,
+				Verify:         verifySourceIP,
+				Recorder:       mgr.GetEventRecorder("boot-server"),
+			},
 		); err != nil {
 			setupLog.Error(err, "boot-server exited")
 			panic(err)
 		}
 	}()
 
+	if tftpServerAddr != "" {
+		if ipxeServiceURL == "" {
+			setupLog.Error(nil, "--tftp-server-address requires --ipxe-service-url")
+			os.Exit(1)
+		}
+		setupLog.Info("starting tftp-server")
+		go func() {
+			if err := bootserver.RunTFTPServer(tftpServerAddr, tftpIPXEDir, ipxeServiceURL, ipxeTemplates, architecture,
+				signerSource != nil, serverLog.WithName("tftpserver")); err != nil {
+				setupLog.Error(err, "tftp-server exited")
+				panic(err)
+			}
+		}()
+	}
+
+	if proxyDHCPServerIP != "" {
+		serverIP := net.ParseIP(proxyDHCPServerIP)
+		if serverIP == nil || serverIP.To4() == nil {
+			setupLog.Error(nil, "invalid --proxy-dhcp-server-ip", "proxyDHCPServerIP", proxyDHCPServerIP)
+			os.Exit(1)
+		}
+		setupLog.Info("starting proxy-dhcp-server")
+		go func() {
+			if err := bootserver.RunProxyDHCPServer(mgr.GetClient(), serverLog.WithName("proxydhcp"), bootserver.ProxyDHCPOptions{
+				ServerIP:          serverIP,
+				IPXEServiceURL:    ipxeServiceURL,
+				RegistryValidator: registryValidator,
+				CredentialStore:   credentialStore,
+				DefaultOCIImage:   defaultHTTPBootOCIImage,
+				DefaultUKIURL:     defaultHTTPBootUKIURL,
+				ImageServerURL:    imageServerURL,
+				Architecture:      architecture,
+			}); err != nil {
+				setupLog.Error(err, "proxy-dhcp-server exited")
+				panic(err)
+			}
+		}()
+	}
+
 	setupLog.Info("starting image-proxy-server")
-	go bootserver.RunImageProxyServer(imageProxyServerAddr, mgr.GetClient(), registryValidator, serverLog.WithName("imageproxyserver"))
+	go bootserver.RunImageProxyServer(imageProxyServerAddr, mgr.GetClient(), registryValidator, credentialStore, imageCache,
+		signerSource, imageProxyServerTLS, serverLog.WithName("imageproxyserver"))
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctx); err != nil {
@@ -361,7 +677,7 @@ func IndexIPXEBootConfigBySystemUUID(ctx context.Context, mgr ctrl.Manager) erro
 		bootv1alpha1.SystemUUIDIndexKey,
 		func(Obj client.Object) []string {
 			ipxeBootConfig := Obj.(*bootv1alpha1.IPXEBootConfig)
-			return []string{ipxeBootConfig.Spec.SystemUUID}
+			return []string{systemuuid.Canonical(ipxeBootConfig.Spec.SystemUUID)}
 		},
 	)
 }
@@ -372,7 +688,28 @@ func IndexIPXEBootConfigBySystemIPs(ctx context.Context, mgr ctrl.Manager) error
 		bootv1alpha1.SystemIPIndexKey,
 		func(Obj client.Object) []string {
 			ipxeBootConfig := Obj.(*bootv1alpha1.IPXEBootConfig)
-			return ipxeBootConfig.Spec.SystemIPs
+			return netid.CanonicalAll(ipxeBootConfig.Spec.SystemIPs)
+		},
+	)
+}
+
+func IndexIPXEBootConfigBySystemMACs(ctx context.Context, mgr ctrl.Manager) error {
+	return mgr.GetFieldIndexer().IndexField(
+		ctx, &bootv1alpha1.IPXEBootConfig{},
+		bootv1alpha1.SystemMACIndexKey,
+		func(Obj client.Object) []string {
+			ipxeBootConfig := Obj.(*bootv1alpha1.IPXEBootConfig)
+			return netid.CanonicalAll(ipxeBootConfig.Spec.SystemMACs)
+		},
+	)
+}
+
+func IndexIPXEBootConfigByLayerDigests(ctx context.Context, mgr ctrl.Manager) error {
+	return mgr.GetFieldIndexer().IndexField(
+		ctx, &bootv1alpha1.IPXEBootConfig{},
+		bootv1alpha1.LayerDigestIndexKey,
+		func(Obj client.Object) []string {
+			return bootserver.ArtifactLayerDigests(Obj.(*bootv1alpha1.IPXEBootConfig))
 		},
 	)
 }
@@ -384,7 +721,7 @@ func IndexHTTPBootConfigBySystemUUID(ctx context.Context, mgr ctrl.Manager) erro
 		bootv1alpha1.SystemUUIDIndexKey,
 		func(Obj client.Object) []string {
 			HTTPBootConfig := Obj.(*bootv1alpha1.HTTPBootConfig)
-			return []string{HTTPBootConfig.Spec.SystemUUID}
+			return []string{systemuuid.Canonical(HTTPBootConfig.Spec.SystemUUID)}
 		},
 	)
 }
@@ -396,7 +733,22 @@ func IndexHTTPBootConfigByNetworkIDs(ctx context.Context, mgr ctrl.Manager) erro
 		bootv1alpha1.NetworkIdentifierIndexKey,
 		func(Obj client.Object) []string {
 			HTTPBootConfig := Obj.(*bootv1alpha1.HTTPBootConfig)
-			return HTTPBootConfig.Spec.NetworkIdentifiers
+			return netid.CanonicalAll(HTTPBootConfig.Spec.NetworkIdentifiers)
+		},
+	)
+}
+
+func IndexServerBootConfigurationByImagePullSecret(ctx context.Context, mgr ctrl.Manager) error {
+	return mgr.GetFieldIndexer().IndexField(
+		ctx,
+		&metalv1alpha1.ServerBootConfiguration{},
+		bootv1alpha1.ImagePullSecretIndexKey,
+		func(Obj client.Object) []string {
+			pullSecret := controller.ImagePullSecretOverride(Obj.(*metalv1alpha1.ServerBootConfiguration))
+			if pullSecret == nil {
+				return nil
+			}
+			return []string{pullSecret.Name}
 		},
 	)
 }
@@ -0,0 +1,407 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package blobcache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+)
+
+// ErrTooLarge is returned by Fill when a blob exceeds the configured cache size.
+var ErrTooLarge = errors.New("blob exceeds cache size limit")
+
+// tmpSuffix marks partially downloaded blobs.
+const tmpSuffix = ".tmp"
+
+// FetchFunc opens the upstream content of a blob. size is the expected length
+// of the blob, or -1 if it is unknown.
+type FetchFunc func(ctx context.Context) (body io.ReadCloser, size int64, err error)
+
+// Cache is an on-disk, content-addressed blob cache with a size limit and
+// least-recently-used eviction. Blobs are stored as <dir>/<algorithm>/<hex>.
+//
+// Besides the content, the cache records the scopes (e.g. repository and
+// credentials) a blob has been authorized for. Scopes are kept in memory only.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List // front is most recently used
+	entries map[digest.Digest]*list.Element
+
+	fillsMu sync.Mutex
+	fills   map[digest.Digest]*fill
+}
+
+type entry struct {
+	digest digest.Digest
+	size   int64
+	// verified is false for blobs found on disk at startup until their
+	// content has been checked against the digest.
+	verified bool
+	scopes   map[string]struct{}
+}
+
+// New creates a cache rooted at dir, indexing blobs already present on disk
+// in the order of their modification time. Indexed blobs are verified against
+// their digest the first time they are opened.
+func New(dir string, maxSize int64) (*Cache, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("cache size limit must be positive, got %d", maxSize)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c := &Cache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[digest.Digest]*list.Element),
+		fills:   make(map[digest.Digest]*fill),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Cache) load() error {
+	type found struct {
+		dgst    digest.Digest
+		size    int64
+		modTime time.Time
+	}
+	var blobs []found
+
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), tmpSuffix) {
+			// Leftover temporary file from an interrupted fill.
+			return os.Remove(path)
+		}
+		rel, err := filepath.Rel(c.dir, path)
+		if err != nil {
+			return err
+		}
+		algorithm, encoded := filepath.Split(rel)
+		dgst := digest.NewDigestFromEncoded(digest.Algorithm(filepath.Clean(algorithm)), encoded)
+		if dgst.Validate() != nil {
+			// Not ours; leave it alone.
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, found{dgst: dgst, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to index cache directory: %w", err)
+	}
+
+	sort.Slice(blobs, func(i, j int) bool { return blobs[i].modTime.After(blobs[j].modTime) })
+	for _, b := range blobs {
+		c.entries[b.dgst] = c.lru.PushBack(&entry{digest: b.dgst, size: b.size})
+		c.size += b.size
+	}
+	c.evict()
+	return nil
+}
+
+func (c *Cache) path(dgst digest.Digest) string {
+	return filepath.Join(c.dir, dgst.Algorithm().String(), dgst.Encoded())
+}
+
+// Has reports whether the blob is present in the cache.
+func (c *Cache) Has(dgst digest.Digest) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.entries[dgst]
+	return ok
+}
+
+// Size returns the total size of all cached blobs.
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Grant records that the blob may be served to requests of the given scope.
+// It is a no-op if the blob is not cached.
+func (c *Cache) Grant(dgst digest.Digest, scope string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[dgst]; ok {
+		e := elem.Value.(*entry)
+		if e.scopes == nil {
+			e.scopes = make(map[string]struct{})
+		}
+		e.scopes[scope] = struct{}{}
+	}
+}
+
+// Granted reports whether the blob is cached and has been granted to the scope.
+func (c *Cache) Granted(dgst digest.Digest, scope string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[dgst]
+	if !ok {
+		return false
+	}
+	_, ok = elem.Value.(*entry).scopes[scope]
+	return ok
+}
+
+// Open returns the cached blob and marks it as recently used. The caller must
+// close the returned file. ok is false on a cache miss. Blobs that fail their
+// deferred verification are removed and reported as a miss.
+func (c *Cache) Open(dgst digest.Digest) (file *os.File, ok bool, err error) {
+	c.mu.Lock()
+	elem, found := c.entries[dgst]
+	var verified bool
+	if found {
+		c.lru.MoveToFront(elem)
+		verified = elem.Value.(*entry).verified
+	}
+	c.mu.Unlock()
+	if !found {
+		return nil, false, nil
+	}
+
+	file, err = os.Open(c.path(dgst))
+	if errors.Is(err, fs.ErrNotExist) {
+		// Removed behind our back; forget about it.
+		c.remove(dgst)
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !verified {
+		if err := c.verify(file, dgst); err != nil {
+			_ = file.Close()
+			c.remove(dgst)
+			_ = os.Remove(c.path(dgst))
+			return nil, false, nil
+		}
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(file.Name(), now, now)
+	return file, true, nil
+}
+
+// verify checks the content of a blob found on disk at startup and rewinds the file.
+func (c *Cache) verify(file *os.File, dgst digest.Digest) error {
+	verifier := dgst.Verifier()
+	if _, err := io.Copy(verifier, file); err != nil {
+		return err
+	}
+	if !verifier.Verified() {
+		return fmt.Errorf("digest mismatch for blob %s", dgst)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[dgst]; ok {
+		elem.Value.(*entry).verified = true
+	}
+	return nil
+}
+
+// Fill downloads the blob via fetch unless it is already cached. The content is
+// verified against the digest before it becomes visible. Concurrent calls for the
+// same digest share a single download.
+func (c *Cache) Fill(ctx context.Context, dgst digest.Digest, fetch FetchFunc) error {
+	if err := dgst.Validate(); err != nil {
+		return fmt.Errorf("invalid digest %q: %w", dgst, err)
+	}
+	f := c.startFill(ctx, dgst, fetch)
+	if f == nil {
+		return nil
+	}
+	select {
+	case <-f.finished:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stream returns the content of the blob. On a cache miss it starts or joins the
+// download and returns the data as it arrives, so callers don't have to wait for
+// the whole blob. Streamed data is only verified once the download completes; if
+// verification fails, reading returns an error instead of io.EOF. size is -1 if
+// the length of the blob is not known yet.
+func (c *Cache) Stream(ctx context.Context, dgst digest.Digest, fetch FetchFunc) (body io.ReadCloser, size int64, err error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, 0, fmt.Errorf("invalid digest %q: %w", dgst, err)
+	}
+	for {
+		if body, size, ok, err := c.openCached(dgst); err != nil || ok {
+			return body, size, err
+		}
+
+		f := c.startFill(ctx, dgst, fetch)
+		if f == nil {
+			// Completed in the meantime.
+			continue
+		}
+		select {
+		case <-f.started:
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		}
+		return f.reader(ctx)
+	}
+}
+
+func (c *Cache) openCached(dgst digest.Digest) (io.ReadCloser, int64, bool, error) {
+	file, ok, err := c.Open(dgst)
+	if err != nil || !ok {
+		return nil, 0, false, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, 0, false, err
+	}
+	return file, info.Size(), true, nil
+}
+
+// startFill returns the in-flight fill for the digest, starting one if needed.
+// It returns nil if the blob is already cached.
+func (c *Cache) startFill(ctx context.Context, dgst digest.Digest, fetch FetchFunc) *fill {
+	c.fillsMu.Lock()
+	defer c.fillsMu.Unlock()
+	// Completed fills are indexed before they are removed from c.fills, so
+	// checking while holding fillsMu cannot miss them.
+	if c.Has(dgst) {
+		return nil
+	}
+	if f, ok := c.fills[dgst]; ok {
+		return f
+	}
+	f := newFill()
+	c.fills[dgst] = f
+
+	// The download is shared, so it must not be cancelled when the first
+	// requester goes away.
+	go func() {
+		err := c.download(context.WithoutCancel(ctx), dgst, fetch, f)
+		c.fillsMu.Lock()
+		delete(c.fills, dgst)
+		c.fillsMu.Unlock()
+		f.finish(err)
+	}()
+	return f
+}
+
+func (c *Cache) download(ctx context.Context, dgst digest.Digest, fetch FetchFunc, f *fill) error {
+	body, size, err := fetch(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	if size > c.maxSize {
+		return ErrTooLarge
+	}
+
+	target := c.path(dgst)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), dgst.Encoded()+".*"+tmpSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	f.start(tmp.Name(), size)
+
+	verifier := dgst.Verifier()
+	written, err := io.Copy(io.MultiWriter(&progressWriter{file: tmp, fill: f}, verifier), io.LimitReader(body, c.maxSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download blob %s: %w", dgst, err)
+	}
+	if written > c.maxSize {
+		return ErrTooLarge
+	}
+	if size >= 0 && written != size {
+		return fmt.Errorf("size mismatch for blob %s: expected %d, got %d", dgst, size, written)
+	}
+	if !verifier.Verified() {
+		return fmt.Errorf("digest mismatch for blob %s", dgst)
+	}
+
+	// Readers open the file by path, so move it while they are locked out.
+	f.mu.Lock()
+	err = os.Rename(tmp.Name(), target)
+	if err == nil {
+		f.path = target
+	}
+	f.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to store blob %s: %w", dgst, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[dgst] = c.lru.PushFront(&entry{digest: dgst, size: written, verified: true})
+	c.size += written
+	c.evict()
+	return nil
+}
+
+// evict removes least recently used blobs until the cache fits its size limit.
+// The most recently used blob is never evicted. c.mu must be held.
+func (c *Cache) evict() {
+	for c.size > c.maxSize && c.lru.Len() > 1 {
+		oldest := c.lru.Back()
+		e := oldest.Value.(*entry)
+		c.lru.Remove(oldest)
+		delete(c.entries, e.digest)
+		c.size -= e.size
+		_ = os.Remove(c.path(e.digest))
+	}
+}
+
+func (c *Cache) remove(dgst digest.Digest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[dgst]; ok {
+		c.size -= elem.Value.(*entry).size
+		c.lru.Remove(elem)
+		delete(c.entries, dgst)
+	}
+}
@@ -0,0 +1,329 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package blobcache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+)
+
+func fetchBytes(data []byte) FetchFunc {
+	return func(context.Context) (io.ReadCloser, int64, error) {
+		return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+	}
+}
+
+func readBlob(t *testing.T, c *Cache, dgst digest.Digest) (string, bool) {
+	t.Helper()
+	file, ok, err := c.Open(dgst)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if !ok {
+		return "", false
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("failed to read blob: %v", err)
+	}
+	return string(data), true
+}
+
+func TestFillAndOpen(t *testing.T) {
+	c, err := New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data := []byte("kernel")
+	dgst := digest.FromBytes(data)
+	if _, ok := readBlob(t, c, dgst); ok {
+		t.Fatal("expected cache miss before fill")
+	}
+
+	if err := c.Fill(context.Background(), dgst, fetchBytes(data)); err != nil {
+		t.Fatalf("Fill() error = %v", err)
+	}
+	got, ok := readBlob(t, c, dgst)
+	if !ok || got != "kernel" {
+		t.Fatalf("Open() = %q, %v; want %q, true", got, ok, "kernel")
+	}
+	if c.Size() != int64(len(data)) {
+		t.Errorf("Size() = %d, want %d", c.Size(), len(data))
+	}
+}
+
+func TestFillRejectsDigestMismatch(t *testing.T) {
+	c, err := New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	dgst := digest.FromString("expected")
+	if err := c.Fill(context.Background(), dgst, fetchBytes([]byte("tampered"))); err == nil {
+		t.Fatal("expected digest mismatch error")
+	}
+	if c.Has(dgst) {
+		t.Error("blob with mismatching digest must not be cached")
+	}
+}
+
+func TestFillRejectsTooLarge(t *testing.T) {
+	c, err := New(t.TempDir(), 4)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data := []byte("too large")
+	unknownSize := func(context.Context) (io.ReadCloser, int64, error) {
+		return io.NopCloser(bytes.NewReader(data)), -1, nil
+	}
+	if err := c.Fill(context.Background(), digest.FromBytes(data), unknownSize); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Fill() error = %v, want %v", err, ErrTooLarge)
+	}
+}
+
+func TestFillDeduplicatesConcurrentRequests(t *testing.T) {
+	c, err := New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	data := []byte("initrd")
+	dgst := digest.FromBytes(data)
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	fetch := func(context.Context) (io.ReadCloser, int64, error) {
+		fetches.Add(1)
+		<-release
+		return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- c.Fill(context.Background(), dgst, fetch)
+		}()
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Fill() error = %v", err)
+		}
+	}
+	// Late callers may find the blob already cached, but nobody may download it twice.
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("upstream fetched %d times, want 1", n)
+	}
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c, err := New(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	first, second, third := []byte("aaaa"), []byte("bbbb"), []byte("cccc")
+	for _, data := range [][]byte{first, second} {
+		if err := c.Fill(context.Background(), digest.FromBytes(data), fetchBytes(data)); err != nil {
+			t.Fatalf("Fill() error = %v", err)
+		}
+	}
+	// Touch the first blob so the second becomes the eviction candidate.
+	if _, ok := readBlob(t, c, digest.FromBytes(first)); !ok {
+		t.Fatal("expected first blob to be cached")
+	}
+	if err := c.Fill(context.Background(), digest.FromBytes(third), fetchBytes(third)); err != nil {
+		t.Fatalf("Fill() error = %v", err)
+	}
+
+	if !c.Has(digest.FromBytes(first)) || !c.Has(digest.FromBytes(third)) {
+		t.Error("expected recently used blobs to remain cached")
+	}
+	if c.Has(digest.FromBytes(second)) {
+		t.Error("expected least recently used blob to be evicted")
+	}
+	if c.Size() != 8 {
+		t.Errorf("Size() = %d, want 8", c.Size())
+	}
+}
+
+func TestNewIndexesExistingBlobs(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 1024)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	data := []byte("squashfs")
+	dgst := digest.FromBytes(data)
+	if err := c.Fill(context.Background(), dgst, fetchBytes(data)); err != nil {
+		t.Fatalf("Fill() error = %v", err)
+	}
+	leftover := c.path(dgst) + ".123.tmp"
+	if err := os.WriteFile(leftover, []byte("partial"), 0o644); err != nil {
+		t.Fatalf("failed to write leftover file: %v", err)
+	}
+
+	reopened, err := New(dir, 1024)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got, ok := readBlob(t, reopened, dgst); !ok || got != "squashfs" {
+		t.Fatalf("Open() = %q, %v; want %q, true", got, ok, "squashfs")
+	}
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Error("expected leftover temporary file to be removed")
+	}
+}
+
+func TestNewKeepsUnrelatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	unrelated := filepath.Join(dir, "README")
+	if err := os.WriteFile(unrelated, []byte("keep me"), 0o644); err != nil {
+		t.Fatalf("failed to write unrelated file: %v", err)
+	}
+
+	if _, err := New(dir, 1024); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("expected unrelated file to be kept: %v", err)
+	}
+}
+
+func TestOpenVerifiesIndexedBlobs(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 1024)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	data := []byte("kernel")
+	dgst := digest.FromBytes(data)
+	if err := c.Fill(context.Background(), dgst, fetchBytes(data)); err != nil {
+		t.Fatalf("Fill() error = %v", err)
+	}
+	if err := os.WriteFile(c.path(dgst), []byte("corrupt"), 0o644); err != nil {
+		t.Fatalf("failed to corrupt blob: %v", err)
+	}
+
+	reopened, err := New(dir, 1024)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, ok := readBlob(t, reopened, dgst); ok {
+		t.Fatal("expected corrupted blob to be reported as a miss")
+	}
+	if reopened.Has(dgst) {
+		t.Error("expected corrupted blob to be dropped from the index")
+	}
+}
+
+func TestGrant(t *testing.T) {
+	c, err := New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	data := []byte("uki")
+	dgst := digest.FromBytes(data)
+
+	c.Grant(dgst, "ghcr.io/foo")
+	if c.Granted(dgst, "ghcr.io/foo") {
+		t.Error("grants must not apply to blobs that are not cached")
+	}
+	if err := c.Fill(context.Background(), dgst, fetchBytes(data)); err != nil {
+		t.Fatalf("Fill() error = %v", err)
+	}
+	c.Grant(dgst, "ghcr.io/foo")
+	if !c.Granted(dgst, "ghcr.io/foo") {
+		t.Error("expected blob to be granted to ghcr.io/foo")
+	}
+	if c.Granted(dgst, "ghcr.io/bar") {
+		t.Error("expected blob not to be granted to ghcr.io/bar")
+	}
+}
+
+func TestStreamServesDataWhileFilling(t *testing.T) {
+	c, err := New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	first, rest := []byte("squash"), []byte("fs")
+	dgst := digest.FromBytes(append(append([]byte{}, first...), rest...))
+	upstream, upstreamWriter := io.Pipe()
+	fetch := func(context.Context) (io.ReadCloser, int64, error) {
+		return upstream, int64(len(first) + len(rest)), nil
+	}
+
+	body, size, err := c.Stream(context.Background(), dgst, fetch)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	defer func() { _ = body.Close() }()
+	if size != 8 {
+		t.Errorf("Stream() size = %d, want 8", size)
+	}
+
+	if _, err := upstreamWriter.Write(first); err != nil {
+		t.Fatalf("failed to write upstream data: %v", err)
+	}
+	buf := make([]byte, len(first))
+	if _, err := io.ReadFull(body, buf); err != nil || string(buf) != "squash" {
+		t.Fatalf("read %q, %v before the fill completed; want %q", buf, err, "squash")
+	}
+
+	// A concurrent requester joins the same download.
+	joined, _, err := c.Stream(context.Background(), dgst, fetch)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	defer func() { _ = joined.Close() }()
+
+	if _, err := upstreamWriter.Write(rest); err != nil {
+		t.Fatalf("failed to write upstream data: %v", err)
+	}
+	_ = upstreamWriter.Close()
+
+	for _, r := range []io.Reader{body, joined} {
+		if _, err := io.ReadAll(r); err != nil {
+			t.Errorf("ReadAll() error = %v", err)
+		}
+	}
+	if !c.Has(dgst) {
+		t.Error("expected streamed blob to be cached")
+	}
+}
+
+func TestStreamFailsOnDigestMismatch(t *testing.T) {
+	c, err := New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	body, _, err := c.Stream(context.Background(), digest.FromString("expected"), fetchBytes([]byte("tampered")))
+	if err != nil {
+		// The fill may already have failed before the stream was opened.
+		return
+	}
+	defer func() { _ = body.Close() }()
+	if _, err := io.ReadAll(body); err == nil {
+		t.Error("expected reading a tampered blob to fail")
+	}
+}
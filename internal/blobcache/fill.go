@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package blobcache
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+)
+
+// fill tracks an in-flight download. Concurrent requests for the same digest
+// share it, either waiting for it to finish or reading the data downloaded so far.
+type fill struct {
+	started  chan struct{} // closed once data can be read or the fill failed early
+	finished chan struct{} // closed once err is set
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	path    string // temporary file, or the cached blob once stored
+	size    int64
+	written int64
+	done    bool
+	err     error
+}
+
+func newFill() *fill {
+	f := &fill{
+		started:  make(chan struct{}),
+		finished: make(chan struct{}),
+		size:     -1,
+	}
+	f.cond = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *fill) start(path string, size int64) {
+	f.mu.Lock()
+	f.path = path
+	f.size = size
+	f.mu.Unlock()
+	close(f.started)
+}
+
+func (f *fill) progress(n int) {
+	f.mu.Lock()
+	f.written += int64(n)
+	f.mu.Unlock()
+	f.cond.Broadcast()
+}
+
+func (f *fill) finish(err error) {
+	f.mu.Lock()
+	alreadyStarted := f.path != ""
+	f.done = true
+	f.err = err
+	f.mu.Unlock()
+	f.cond.Broadcast()
+	if !alreadyStarted {
+		close(f.started)
+	}
+	close(f.finished)
+}
+
+// reader returns a reader following the download. It fails right away if the
+// fill failed before any data was written.
+func (f *fill) reader(ctx context.Context) (io.ReadCloser, int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.done && f.err != nil {
+		return nil, 0, f.err
+	}
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, 0, err
+	}
+	r := &fillReader{fill: f, file: file}
+	// Wake up a blocked Read when the requester goes away.
+	r.stop = context.AfterFunc(ctx, func() {
+		f.mu.Lock()
+		r.cancelled = ctx.Err()
+		f.mu.Unlock()
+		f.cond.Broadcast()
+	})
+	return r, f.size, nil
+}
+
+// progressWriter writes downloaded data to the temporary file and wakes up readers.
+type progressWriter struct {
+	file *os.File
+	fill *fill
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.file.Write(p)
+	w.fill.progress(n)
+	return n, err
+}
+
+// fillReader reads the temporary file of a fill up to the amount written so far,
+// blocking until more data arrives or the fill finishes.
+type fillReader struct {
+	fill      *fill
+	file      *os.File
+	offset    int64
+	cancelled error
+	stop      func() bool
+}
+
+func (r *fillReader) Read(p []byte) (int, error) {
+	f := r.fill
+	f.mu.Lock()
+	for r.offset >= f.written && !f.done && r.cancelled == nil {
+		f.cond.Wait()
+	}
+	available, done, err, cancelled := f.written-r.offset, f.done, f.err, r.cancelled
+	f.mu.Unlock()
+
+	switch {
+	case cancelled != nil:
+		return 0, cancelled
+	case available > 0:
+		if int64(len(p)) > available {
+			p = p[:available]
+		}
+		n, err := r.file.ReadAt(p, r.offset)
+		r.offset += int64(n)
+		if err == io.EOF && n > 0 {
+			err = nil
+		}
+		return n, err
+	case done && err != nil:
+		return 0, err
+	default:
+		return 0, io.EOF
+	}
+}
+
+func (r *fillReader) Close() error {
+	r.stop()
+	return r.file.Close()
+}
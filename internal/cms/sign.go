@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cms creates detached CMS (RFC 5652) signatures in the form verified by
+// iPXE's imgverify command: SignedData without signed attributes, using SHA-256.
+package cms
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+var (
+	oidData          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidSHA256        = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidRSAEncryption = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+)
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapsulatedContentInfo
+	Certificates     asn1.RawValue
+	SignerInfos      []signerInfo `asn1:"set"`
+}
+
+// encapsulatedContentInfo omits the content, making the signature detached.
+type encapsulatedContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+}
+
+type signerInfo struct {
+	Version            int
+	SID                issuerAndSerialNumber
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// Signer signs content with a certificate and its private key.
+type Signer struct {
+	certificate *x509.Certificate
+	// chain holds the signing certificate followed by intermediates, all embedded into
+	// signatures so that verifiers only need the root certificate.
+	chain []*x509.Certificate
+	key   *rsa.PrivateKey
+}
+
+// NewSigner creates a Signer from a PEM encoded certificate chain, starting with the
+// signing certificate, and the PEM encoded private key of that certificate. The key must
+// be an RSA key, as imgverify does not verify other signature algorithms.
+func NewSigner(certPEM, keyPEM []byte) (*Signer, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing certificate or key: %w", err)
+	}
+	key, ok := pair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key type %T: imgverify only verifies RSA signatures", pair.PrivateKey)
+	}
+
+	chain := make([]*x509.Certificate, 0, len(pair.Certificate))
+	for _, der := range pair.Certificate {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in signing chain: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+	return &Signer{certificate: chain[0], chain: chain, key: key}, nil
+}
+
+// Certificate returns the signing certificate.
+func (s *Signer) Certificate() *x509.Certificate {
+	return s.certificate
+}
+
+// Sign reads content from r and returns a DER encoded detached signature of it.
+func (s *Signer) Sign(r io.Reader) ([]byte, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+	return s.SignDigest(h.Sum(nil))
+}
+
+// SignDigest returns a DER encoded detached signature of the content with the given
+// SHA-256 digest. Without signed attributes, the signature covers the content digest
+// directly, so content that is addressed by its digest can be signed without reading it.
+func (s *Signer) SignDigest(sha256Digest []byte) ([]byte, error) {
+	if len(sha256Digest) != sha256.Size {
+		return nil, fmt.Errorf("invalid SHA-256 digest length %d", len(sha256Digest))
+	}
+	signature, err := s.key.Sign(rand.Reader, sha256Digest, crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign digest: %w", err)
+	}
+
+	signatureAlgorithm := pkix.AlgorithmIdentifier{Algorithm: oidRSAEncryption, Parameters: asn1.NullRawValue}
+	digestAlgorithm := pkix.AlgorithmIdentifier{Algorithm: oidSHA256}
+
+	var certificates []byte
+	for _, cert := range s.chain {
+		certificates = append(certificates, cert.Raw...)
+	}
+	sd, err := asn1.Marshal(signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAlgorithm},
+		EncapContentInfo: encapsulatedContentInfo{ContentType: oidData},
+		// [0] IMPLICIT SET OF Certificate
+		Certificates: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certificates},
+		SignerInfos: []signerInfo{{
+			Version: 1,
+			SID: issuerAndSerialNumber{
+				Issuer:       asn1.RawValue{FullBytes: s.certificate.RawIssuer},
+				SerialNumber: s.certificate.SerialNumber,
+			},
+			DigestAlgorithm:    digestAlgorithm,
+			SignatureAlgorithm: signatureAlgorithm,
+			Signature:          signature,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SignedData: %w", err)
+	}
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		// [0] EXPLICIT SignedData
+		Content: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: sd},
+	})
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cms
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newCertificate returns a PEM encoded self-signed code signing certificate for key and the
+// PEM encoded key.
+func newCertificate(t *testing.T, key crypto.Signer) (certPEM, keyPEM []byte) {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "boot-operator code signing"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func newSigner(t *testing.T, key crypto.Signer) *Signer {
+	t.Helper()
+	signer, err := NewSigner(newCertificate(t, key))
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+	return signer
+}
+
+// parseSignature decodes a detached signature produced by Sign.
+func parseSignature(t *testing.T, der []byte) signedData {
+	t.Helper()
+	var ci contentInfo
+	if rest, err := asn1.Unmarshal(der, &ci); err != nil || len(rest) > 0 {
+		t.Fatalf("failed to parse ContentInfo: %v", err)
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		t.Fatalf("content type = %v, want %v", ci.ContentType, oidSignedData)
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		t.Fatalf("failed to parse SignedData: %v", err)
+	}
+	return sd
+}
+
+func TestSignRSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	signer := newSigner(t, key)
+	content := []byte("#!ipxe\nboot\n")
+
+	der, err := signer.Sign(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	sd := parseSignature(t, der)
+
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil || len(certs) != 1 || !certs[0].Equal(signer.Certificate()) {
+		t.Fatalf("embedded certificates = %v, %v; want the signing certificate", certs, err)
+	}
+	if len(sd.SignerInfos) != 1 {
+		t.Fatalf("got %d signer infos, want 1", len(sd.SignerInfos))
+	}
+	si := sd.SignerInfos[0]
+	if si.SID.SerialNumber.Cmp(big.NewInt(42)) != 0 || !bytes.Equal(si.SID.Issuer.FullBytes, signer.Certificate().RawIssuer) {
+		t.Error("signer info does not identify the signing certificate")
+	}
+	digest := sha256.Sum256(content)
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], si.Signature); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+
+	if _, err := signer.SignDigest([]byte("short")); err == nil {
+		t.Error("expected a digest of the wrong length to be rejected")
+	}
+}
+
+func TestNewSignerRejectsECDSAKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	if _, err := NewSigner(newCertificate(t, key)); err == nil || !strings.Contains(err.Error(), "only verifies RSA signatures") {
+		t.Errorf("NewSigner() error = %v, want ECDSA keys to be rejected", err)
+	}
+}
+
+func TestNewSignerRejectsMismatchedKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	certPEM, _ := newCertificate(t, key)
+	_, otherPEM := newCertificate(t, other)
+	if _, err := NewSigner(certPEM, otherPEM); err == nil {
+		t.Error("expected a key not matching the certificate to be rejected")
+	}
+}
@@ -16,8 +16,32 @@ limitations under the License.
 
 package controller
 
-import "sigs.k8s.io/controller-runtime/pkg/client"
+import (
+	"context"
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
 
 const (
 	fieldOwner = client.FieldOwner("boot.ironcore.dev/controller-manager")
 )
+
+// updateCondition sets a condition of a boot config, or removes the condition of the given type if
+// condition is nil. conditions must point to the conditions in the status of config.
+func updateCondition(ctx context.Context, c client.Client, config client.Object, conditions *[]metav1.Condition, conditionType string, condition *metav1.Condition) error {
+	base := config.DeepCopyObject().(client.Object)
+	if condition == nil {
+		if !apimeta.RemoveStatusCondition(conditions, conditionType) {
+			return nil
+		}
+	} else if !apimeta.SetStatusCondition(conditions, *condition) {
+		return nil
+	}
+	if err := c.Status().Patch(ctx, config, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("failed to update %s condition: %w", conditionType, err)
+	}
+	return nil
+}
@@ -5,9 +5,13 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -23,6 +27,9 @@ import (
 type HTTPBootConfigReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// IgnitionTokenTTL is the lifetime of the ignition tokens minted for each boot. Tokens are
+	// disabled if it is zero.
+	IgnitionTokenTTL time.Duration
 }
 
 //+kubebuilder:rbac:groups=boot.ironcore.dev,resources=httpbootconfigs,verbs=get;list;watch;create;update;patch;delete
@@ -49,10 +56,19 @@ func (r *HTTPBootConfigReconciler) reconcileExists(ctx context.Context, log logr
 func (r *HTTPBootConfigReconciler) reconcile(ctx context.Context, log logr.Logger, config *bootv1alpha1.HTTPBootConfig) (ctrl.Result, error) {
 	log.V(1).Info("Reconciling HTTPBootConfig")
 
+	if err := rearmIgnition(ctx, r.Client, config, &config.Status.Conditions); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.V(1).Info("Checking SystemUUID")
+	if err := r.ensureSystemUUIDCondition(ctx, config); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	log.V(1).Info("Ensuring Ignition")
 	state, err := r.ensureIgnition(ctx, log, config)
 	if err != nil {
-		if err := r.patchStatus(ctx, config, state); err != nil {
+		if err := r.patchStatus(ctx, config, state, config.Status.IgnitionToken); err != nil {
 			return ctrl.Result{}, err
 		}
 		log.V(1).Info("Failed to Ensure Ignition", "Error", err)
@@ -60,31 +76,52 @@ func (r *HTTPBootConfigReconciler) reconcile(ctx context.Context, log logr.Logge
 	}
 	log.V(1).Info("Ensured Ignition")
 
-	if err := r.patchStatus(ctx, config, state); err != nil {
+	token, renewAfter, err := ensureIgnitionToken(config.Status.IgnitionToken, config.Generation, r.IgnitionTokenTTL, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.patchStatus(ctx, config, state, token); err != nil {
 		return ctrl.Result{}, err
 	}
 
 	log.V(1).Info("Reconciled HTTPBootConfig")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: renewAfter}, nil
 }
 
 func (r *HTTPBootConfigReconciler) ensureIgnition(ctx context.Context, _ logr.Logger, config *bootv1alpha1.HTTPBootConfig) (bootv1alpha1.HTTPBootConfigState, error) {
-	// Verify if the IgnitionRef is set, and it has the intended data key.
-	if config.Spec.IgnitionSecretRef != nil {
-		ignitionSecret := &corev1.Secret{}
-		if err := r.Get(ctx, client.ObjectKey{Name: config.Spec.IgnitionSecretRef.Name, Namespace: config.Namespace}, ignitionSecret); err != nil {
-			return bootv1alpha1.HTTPBootConfigStateError, err
-			// TODO: Add some validation steps to ensure that the IgnitionData is compliant, if necessary.
-			// Assume for now, that it's going to json format.
-		}
-		if ignitionSecret.Data[bootv1alpha1.DefaultIgnitionKey] == nil {
-			return bootv1alpha1.HTTPBootConfigStateError, fmt.Errorf("ignition data is missing")
-		}
+	// Verify that the ignition data referenced by the IgnitionRef, if any, can be served.
+	condition, err := ignitionCondition(ctx, r.Client, config, config.Spec.IgnitionSecretRef, config.Spec.IgnitionLayers)
+	if err != nil {
+		return bootv1alpha1.HTTPBootConfigStateError, err
+	}
+	if err := updateCondition(ctx, r.Client, config, &config.Status.Conditions, bootv1alpha1.IgnitionValidCondition, condition); err != nil {
+		return bootv1alpha1.HTTPBootConfigStateError, err
+	}
+	if condition != nil && condition.Status == metav1.ConditionFalse {
+		return bootv1alpha1.HTTPBootConfigStateError, errors.New(condition.Message)
 	}
 
 	return bootv1alpha1.HTTPBootConfigStateReady, nil
 }
 
+// ensureSystemUUIDCondition flags the config if its SystemUUID is invalid or shared with a
+// HTTPBootConfig of another Server.
+func (r *HTTPBootConfigReconciler) ensureSystemUUIDCondition(ctx context.Context, config *bootv1alpha1.HTTPBootConfig) error {
+	sharing, err := listSharingSystemUUID(ctx, r.Client, config, config.Spec.SystemUUID, func() client.ObjectList {
+		return &bootv1alpha1.HTTPBootConfigList{}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list HTTPBootConfigs sharing SystemUUID: %w", err)
+	}
+
+	condition, err := systemUUIDCondition(ctx, r.Client, config, config.Spec.SystemUUID, sharing)
+	if err != nil {
+		return err
+	}
+	return updateCondition(ctx, r.Client, config, &config.Status.Conditions, bootv1alpha1.SystemUUIDAmbiguousCondition, condition)
+}
+
 func (r *HTTPBootConfigReconciler) delete(_ context.Context, log logr.Logger, _ *bootv1alpha1.HTTPBootConfig) (ctrl.Result, error) {
 	log.V(1).Info("Deleting HTTPBootConfig")
 
@@ -94,13 +131,19 @@ func (r *HTTPBootConfigReconciler) delete(_ context.Context, log logr.Logger, _
 	return ctrl.Result{}, nil
 }
 
-func (r *HTTPBootConfigReconciler) patchStatus(ctx context.Context, config *bootv1alpha1.HTTPBootConfig, state bootv1alpha1.HTTPBootConfigState) error {
-	if config.Status.State == state {
+func (r *HTTPBootConfigReconciler) patchStatus(
+	ctx context.Context,
+	config *bootv1alpha1.HTTPBootConfig,
+	state bootv1alpha1.HTTPBootConfigState,
+	token *bootv1alpha1.IgnitionToken,
+) error {
+	if config.Status.State == state && equality.Semantic.DeepEqual(config.Status.IgnitionToken, token) {
 		return nil
 	}
 
 	base := config.DeepCopy()
 	config.Status.State = state
+	config.Status.IgnitionToken = token
 
 	if err := r.Status().Patch(ctx, config, client.MergeFrom(base)); err != nil {
 		return err
@@ -124,8 +167,7 @@ func (r *HTTPBootConfigReconciler) enqueueHTTPBootConfigReferencingIgnitionSecre
 
 	var requests []reconcile.Request
 	for _, config := range configList.Items {
-		if config.Spec.IgnitionSecretRef != nil &&
-			config.Spec.IgnitionSecretRef.Name == secretObj.Name {
+		if referencesIgnitionSecret(config.Spec.IgnitionSecretRef, config.Spec.IgnitionLayers, secretObj.Name) {
 			requests = append(requests, reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Name:      config.Name,
@@ -137,6 +179,28 @@ func (r *HTTPBootConfigReconciler) enqueueHTTPBootConfigReferencingIgnitionSecre
 	return requests
 }
 
+func (r *HTTPBootConfigReconciler) enqueueHTTPBootConfigsSharingSystemUUID(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := ctrl.LoggerFrom(ctx)
+	config, ok := obj.(*bootv1alpha1.HTTPBootConfig)
+	if !ok || config.Spec.SystemUUID == "" {
+		return nil
+	}
+
+	sharing, err := listSharingSystemUUID(ctx, r.Client, config, config.Spec.SystemUUID, func() client.ObjectList {
+		return &bootv1alpha1.HTTPBootConfigList{}
+	})
+	if err != nil {
+		log.Error(err, "failed to list HTTPBootConfig sharing SystemUUID", "SystemUUID", config.Spec.SystemUUID)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, other := range sharing {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(other)})
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *HTTPBootConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -145,5 +209,9 @@ func (r *HTTPBootConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.enqueueHTTPBootConfigReferencingIgnitionSecret),
 		).
+		Watches(
+			&bootv1alpha1.HTTPBootConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueHTTPBootConfigsSharingSystemUUID),
+		).
 		Complete(r)
 }
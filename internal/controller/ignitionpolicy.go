@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rearmIgnition processes the re-arm annotation of a boot config: it removes the conditions that
+// record the delivery of its ignition data and the readiness of the booted OS, so that the data is
+// served again regardless of the IgnitionPolicy, and then removes the annotation. conditions must
+// point to the conditions in the status of config.
+func rearmIgnition(ctx context.Context, c client.Client, config client.Object, conditions *[]metav1.Condition) error {
+	if _, ok := config.GetAnnotations()[bootv1alpha1.RearmIgnitionAnnotation]; !ok {
+		return nil
+	}
+
+	base := config.DeepCopyObject().(client.Object)
+	apimeta.RemoveStatusCondition(conditions, bootv1alpha1.IgnitionDataFetchedCondition)
+	apimeta.RemoveStatusCondition(conditions, bootv1alpha1.OSReadyCondition)
+	if err := c.Status().Patch(ctx, config, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("failed to reset ignition delivery conditions: %w", err)
+	}
+
+	base = config.DeepCopyObject().(client.Object)
+	annotations := config.GetAnnotations()
+	delete(annotations, bootv1alpha1.RearmIgnitionAnnotation)
+	config.SetAnnotations(annotations)
+	if err := c.Patch(ctx, config, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("failed to remove annotation %s: %w", bootv1alpha1.RearmIgnitionAnnotation, err)
+	}
+	return nil
+}
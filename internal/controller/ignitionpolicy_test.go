@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIgnitionPolicyOverride(t *testing.T) {
+	for annotation, want := range map[string]bootv1alpha1.IgnitionPolicy{
+		"":             "",
+		"Once":         bootv1alpha1.IgnitionPolicyOnce,
+		" UntilReady ": bootv1alpha1.IgnitionPolicyUntilReady,
+	} {
+		config := &metalv1alpha1.ServerBootConfiguration{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{bootv1alpha1.IgnitionPolicyAnnotation: annotation},
+		}}
+		got, err := IgnitionPolicyOverride(config)
+		if err != nil || got != want {
+			t.Errorf("IgnitionPolicyOverride(%q) = %q, %v; want %q, nil", annotation, got, err, want)
+		}
+	}
+
+	config := &metalv1alpha1.ServerBootConfiguration{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{bootv1alpha1.IgnitionPolicyAnnotation: "Twice"},
+	}}
+	if _, err := IgnitionPolicyOverride(config); err == nil {
+		t.Errorf("expected an error for an invalid ignition policy")
+	}
+}
+
+func TestRearmIgnition(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := bootv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &bootv1alpha1.IPXEBootConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "config",
+			Namespace:   "default",
+			Annotations: map[string]string{bootv1alpha1.RearmIgnitionAnnotation: ""},
+		},
+		Status: bootv1alpha1.IPXEBootConfigStatus{
+			Conditions: []metav1.Condition{
+				{Type: bootv1alpha1.IgnitionDataFetchedCondition, Status: metav1.ConditionTrue, Reason: "IgnitionDataDelivered"},
+				{Type: bootv1alpha1.OSReadyCondition, Status: metav1.ConditionTrue, Reason: "ReadyReported"},
+				{Type: "IPXEScriptFetched", Status: metav1.ConditionTrue, Reason: "IPXEScriptDelivered"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(config).WithStatusSubresource(config).Build()
+
+	if err := rearmIgnition(ctx, c, config, &config.Status.Conditions); err != nil {
+		t.Fatalf("rearmIgnition() error = %v", err)
+	}
+
+	stored := &bootv1alpha1.IPXEBootConfig{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(config), stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stored.Annotations[bootv1alpha1.RearmIgnitionAnnotation]; ok {
+		t.Errorf("expected the re-arm annotation to be removed")
+	}
+	for _, conditionType := range []string{bootv1alpha1.IgnitionDataFetchedCondition, bootv1alpha1.OSReadyCondition} {
+		if apimeta.FindStatusCondition(stored.Status.Conditions, conditionType) != nil {
+			t.Errorf("expected condition %s to be removed", conditionType)
+		}
+	}
+	if apimeta.FindStatusCondition(stored.Status.Conditions, "IPXEScriptFetched") == nil {
+		t.Errorf("expected unrelated conditions to be kept")
+	}
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"time"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/ignition"
+)
+
+// ensureIgnitionToken returns the ignition token of a boot config at the given generation and the
+// time until it must be renewed. The current token is kept while it is valid and was minted for
+// that generation; otherwise a new one is minted. If ttl is zero, tokens are disabled and nil is
+// returned.
+//
+// The boot-server additionally rotates the token of an IPXEBootConfig whenever it serves its iPXE
+// script, so that a token handed out for one boot is not accepted for the next one.
+func ensureIgnitionToken(
+	current *bootv1alpha1.IgnitionToken,
+	generation int64,
+	ttl time.Duration,
+	now time.Time,
+) (*bootv1alpha1.IgnitionToken, time.Duration, error) {
+	if ttl <= 0 {
+		return nil, 0, nil
+	}
+	if current != nil && current.ObservedGeneration == generation && now.Before(current.ExpirationTime.Time) {
+		return current, current.ExpirationTime.Sub(now), nil
+	}
+
+	token, err := ignition.NewToken(generation, ttl, now)
+	if err != nil {
+		return nil, 0, err
+	}
+	return token, ttl, nil
+}
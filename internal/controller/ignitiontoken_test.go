@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnsureIgnitionToken(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ttl := time.Hour
+
+	minted, renewAfter, err := ensureIgnitionToken(nil, 1, ttl, now)
+	if err != nil {
+		t.Fatalf("ensureIgnitionToken() error = %v", err)
+	}
+	if len(minted.Token) < 40 || minted.ObservedGeneration != 1 || !minted.ExpirationTime.Time.Equal(now.Add(ttl)) {
+		t.Fatalf("minted token = %+v, want a random token for generation 1 expiring in %s", minted, ttl)
+	}
+	if renewAfter != ttl {
+		t.Errorf("renewAfter = %s, want %s", renewAfter, ttl)
+	}
+
+	kept, renewAfter, err := ensureIgnitionToken(minted, 1, ttl, now.Add(10*time.Minute))
+	if err != nil {
+		t.Fatalf("ensureIgnitionToken() error = %v", err)
+	}
+	if kept != minted {
+		t.Errorf("expected a valid token of the same generation to be kept")
+	}
+	if renewAfter != 50*time.Minute {
+		t.Errorf("renewAfter = %s, want 50m", renewAfter)
+	}
+
+	for name, tc := range map[string]struct {
+		generation int64
+		now        time.Time
+	}{
+		"new generation": {generation: 2, now: now.Add(time.Minute)},
+		"expired":        {generation: 1, now: now.Add(ttl)},
+	} {
+		renewed, _, err := ensureIgnitionToken(minted, tc.generation, ttl, tc.now)
+		if err != nil {
+			t.Fatalf("%s: ensureIgnitionToken() error = %v", name, err)
+		}
+		if renewed.Token == minted.Token || renewed.ObservedGeneration != tc.generation {
+			t.Errorf("%s: expected a new token for generation %d, got %+v", name, tc.generation, renewed)
+		}
+	}
+
+	disabled, renewAfter, err := ensureIgnitionToken(minted, 1, 0, now)
+	if err != nil || disabled != nil || renewAfter != 0 {
+		t.Errorf("ensureIgnitionToken() with tokens disabled = %v, %s, %v; want nil, 0, nil", disabled, renewAfter, err)
+	}
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/ignition"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ignitionCondition returns the IgnitionValid condition of a boot config for the ignition Secret and
+// the ignition layers it references, or nil if it references none. The ignition data is parsed
+// according to the format key of each Secret, after rendering it with the TemplateData of the boot
+// config if it is templated. Ignition layers must also merge into a single Ignition config.
+func ignitionCondition(ctx context.Context, c client.Client, config client.Object, secretRef *corev1.LocalObjectReference, layers []bootv1alpha1.IgnitionLayer) (*metav1.Condition, error) {
+	if secretRef == nil && len(layers) == 0 {
+		return nil, nil
+	}
+	condition := &metav1.Condition{
+		Type:               bootv1alpha1.IgnitionValidCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: config.GetGeneration(),
+	}
+
+	var sources []ignition.Layer
+	for _, layer := range layers {
+		sources = append(sources, ignition.Layer{
+			Name:    layer.SecretRef.Name,
+			Replace: layer.Strategy == bootv1alpha1.IgnitionLayerStrategyReplace,
+		})
+	}
+	if secretRef != nil {
+		sources = append(sources, ignition.Layer{Name: secretRef.Name})
+	}
+
+	var names, warnings []string
+	for i := range sources {
+		source := &sources[i]
+		names = append(names, source.Name)
+
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: config.GetNamespace(), Name: source.Name}, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get ignition Secret %s: %w", source.Name, err)
+			}
+			condition.Reason = bootv1alpha1.MissingIgnitionReason
+			condition.Message = fmt.Sprintf("Secret %s not found.", source.Name)
+			return condition, nil
+		}
+		data, ok := secret.Data[bootv1alpha1.DefaultIgnitionKey]
+		if !ok {
+			condition.Reason = bootv1alpha1.MissingIgnitionReason
+			condition.Message = fmt.Sprintf("Secret %s has no %s key.", source.Name, bootv1alpha1.DefaultIgnitionKey)
+			return condition, nil
+		}
+
+		if ignition.IsTemplate(secret) {
+			values, err := ignition.LoadTemplateData(ctx, c, config)
+			if err != nil {
+				return nil, err
+			}
+			if data, err = ignition.RenderTemplate(data, values); err != nil {
+				condition.Reason = bootv1alpha1.InvalidIgnitionReason
+				condition.Message = fmt.Sprintf("Ignition template of Secret %s is invalid: %v", source.Name, err)
+				return condition, nil
+			}
+		}
+		source.Data = data
+		source.Format = string(secret.Data[bootv1alpha1.DefaultFormatKey])
+
+		sourceWarnings, err := ignition.Validate(source.Data, source.Format)
+		if err != nil {
+			condition.Reason = bootv1alpha1.InvalidIgnitionReason
+			condition.Message = fmt.Sprintf("Ignition data of Secret %s is invalid: %v", source.Name, err)
+			return condition, nil
+		}
+		warnings = append(warnings, sourceWarnings...)
+	}
+
+	if len(layers) > 0 {
+		if _, err := ignition.MergeLayers(sources); err != nil {
+			condition.Reason = bootv1alpha1.InvalidIgnitionReason
+			condition.Message = fmt.Sprintf("Ignition layers cannot be merged: %v", err)
+			return condition, nil
+		}
+	}
+
+	condition.Status = metav1.ConditionTrue
+	subject := fmt.Sprintf("Ignition data of Secret %s is", names[0])
+	if len(names) > 1 {
+		subject = fmt.Sprintf("Ignition data of Secrets %s are", strings.Join(names, ", "))
+	}
+	if len(warnings) > 0 {
+		condition.Reason = bootv1alpha1.IgnitionWarningsReason
+		condition.Message = fmt.Sprintf("%s valid with warnings: %s", subject, strings.Join(warnings, "; "))
+		return condition, nil
+	}
+	condition.Reason = bootv1alpha1.ValidIgnitionReason
+	condition.Message = subject + " valid."
+	return condition, nil
+}
+
+// referencesIgnitionSecret reports whether the ignition Secret or one of the ignition layers of a boot
+// config is the Secret with the given name.
+func referencesIgnitionSecret(secretRef *corev1.LocalObjectReference, layers []bootv1alpha1.IgnitionLayer, name string) bool {
+	if secretRef != nil && secretRef.Name == name {
+		return true
+	}
+	for _, layer := range layers {
+		if layer.SecretRef.Name == name {
+			return true
+		}
+	}
+	return false
+}
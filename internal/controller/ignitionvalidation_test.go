@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHTTPBootConfigIgnitionCondition(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := bootv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := func(name string, data map[string]string) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Data: map[string][]byte{}}
+		for key, value := range data {
+			s.Data[key] = []byte(value)
+		}
+		return s
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			secret("valid", map[string]string{bootv1alpha1.DefaultIgnitionKey: `{"ignition":{"version":"3.4.0"}}`}),
+			secret("butane", map[string]string{
+				bootv1alpha1.DefaultIgnitionKey: "variant: fcos\nversion: 1.5.0\nunknown: true\n",
+				bootv1alpha1.DefaultFormatKey:   bootv1alpha1.FCOSFormat,
+			}),
+			secret("invalid", map[string]string{bootv1alpha1.DefaultIgnitionKey: `{"ignition":{}}`}),
+			secret("no-key", map[string]string{"user-data": "#cloud-config"}),
+			secret("template", map[string]string{
+				bootv1alpha1.DefaultIgnitionKey: `{"ignition":{"version":"3.4.0"},"storage":{"files":[{"path":"/etc/hostname","contents":{"source":"data:,{{.SystemUUID}}"}}]}}`,
+				bootv1alpha1.DefaultTemplateKey: "true",
+			}),
+			secret("cloud-init", map[string]string{
+				bootv1alpha1.DefaultIgnitionKey: "#cloud-config\nhostname: node-1\n",
+				bootv1alpha1.DefaultFormatKey:   bootv1alpha1.CloudInitFormat,
+			}),
+			secret("bad-template", map[string]string{
+				bootv1alpha1.DefaultIgnitionKey: `{"ignition":{"version":"3.4.0"},"x":"{{.Labels.missing}}"}`,
+				bootv1alpha1.DefaultTemplateKey: "true",
+			}),
+		).
+		WithStatusSubresource(&bootv1alpha1.HTTPBootConfig{}).
+		Build()
+	r := &HTTPBootConfigReconciler{Client: c, Scheme: scheme}
+
+	tests := []struct {
+		secret     string
+		layers     []string
+		wantState  bootv1alpha1.HTTPBootConfigState
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{secret: "", wantState: bootv1alpha1.HTTPBootConfigStateReady},
+		{secret: "valid", wantState: bootv1alpha1.HTTPBootConfigStateReady, wantStatus: metav1.ConditionTrue, wantReason: bootv1alpha1.ValidIgnitionReason},
+		{secret: "butane", wantState: bootv1alpha1.HTTPBootConfigStateReady, wantStatus: metav1.ConditionTrue, wantReason: bootv1alpha1.IgnitionWarningsReason},
+		{secret: "invalid", wantState: bootv1alpha1.HTTPBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.InvalidIgnitionReason},
+		{secret: "no-key", wantState: bootv1alpha1.HTTPBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.MissingIgnitionReason},
+		{secret: "template", wantState: bootv1alpha1.HTTPBootConfigStateReady, wantStatus: metav1.ConditionTrue, wantReason: bootv1alpha1.ValidIgnitionReason},
+		{secret: "bad-template", wantState: bootv1alpha1.HTTPBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.InvalidIgnitionReason},
+		{secret: "missing", wantState: bootv1alpha1.HTTPBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.MissingIgnitionReason},
+		{secret: "valid", layers: []string{"butane"}, wantState: bootv1alpha1.HTTPBootConfigStateReady, wantStatus: metav1.ConditionTrue, wantReason: bootv1alpha1.IgnitionWarningsReason},
+		{layers: []string{"valid", "template"}, wantState: bootv1alpha1.HTTPBootConfigStateReady, wantStatus: metav1.ConditionTrue, wantReason: bootv1alpha1.ValidIgnitionReason},
+		{secret: "cloud-init", layers: []string{"valid"}, wantState: bootv1alpha1.HTTPBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.InvalidIgnitionReason},
+		{secret: "valid", layers: []string{"missing"}, wantState: bootv1alpha1.HTTPBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.MissingIgnitionReason},
+	}
+	for _, tt := range tests {
+		name := strings.TrimSuffix(strings.Join(append(slices.Clone(tt.layers), tt.secret), "-"), "-")
+		t.Run(name, func(t *testing.T) {
+			config := &bootv1alpha1.HTTPBootConfig{ObjectMeta: metav1.ObjectMeta{Name: "config-" + name, Namespace: "default"}}
+			if tt.secret != "" {
+				config.Spec.IgnitionSecretRef = &corev1.LocalObjectReference{Name: tt.secret}
+			}
+			for _, layer := range tt.layers {
+				config.Spec.IgnitionLayers = append(config.Spec.IgnitionLayers, bootv1alpha1.IgnitionLayer{SecretRef: corev1.LocalObjectReference{Name: layer}})
+			}
+			if err := c.Create(ctx, config); err != nil {
+				t.Fatal(err)
+			}
+
+			state, err := r.ensureIgnition(ctx, logr.Discard(), config)
+			if state != tt.wantState || (err != nil) != (tt.wantState == bootv1alpha1.HTTPBootConfigStateError) {
+				t.Fatalf("ensureIgnition() = %s, %v, want state %s", state, err, tt.wantState)
+			}
+
+			stored := &bootv1alpha1.HTTPBootConfig{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(config), stored); err != nil {
+				t.Fatal(err)
+			}
+			condition := apimeta.FindStatusCondition(stored.Status.Conditions, bootv1alpha1.IgnitionValidCondition)
+			switch {
+			case tt.wantReason == "" && condition != nil:
+				t.Errorf("unexpected condition %+v", condition)
+			case tt.wantReason != "" && (condition == nil || condition.Status != tt.wantStatus || condition.Reason != tt.wantReason):
+				t.Errorf("condition = %+v, want status %s and reason %s", condition, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestIgnitionLayersOverride(t *testing.T) {
+	for annotation, want := range map[string][]string{
+		"":                     nil,
+		"hardening":            {"hardening"},
+		" hardening, site ,, ": {"hardening", "site"},
+	} {
+		config := &metalv1alpha1.ServerBootConfiguration{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{bootv1alpha1.IgnitionLayersAnnotation: annotation},
+		}}
+		var got []string
+		for _, layer := range IgnitionLayersOverride(config) {
+			got = append(got, layer.SecretRef.Name)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("IgnitionLayersOverride(%q) = %v, want %v", annotation, got, want)
+		}
+	}
+}
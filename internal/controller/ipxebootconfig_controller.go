@@ -5,8 +5,11 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -23,12 +26,16 @@ import (
 type IPXEBootConfigReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// IgnitionTokenTTL is the lifetime of the ignition tokens minted for each boot. Tokens are
+	// disabled if it is zero.
+	IgnitionTokenTTL time.Duration
 }
 
 //+kubebuilder:rbac:groups=boot.ironcore.dev,resources=ipxebootconfigs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=boot.ironcore.dev,resources=ipxebootconfigs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=boot.ironcore.dev,resources=ipxebootconfigs/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
 func (r *IPXEBootConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -49,41 +56,97 @@ func (r *IPXEBootConfigReconciler) reconcileExists(ctx context.Context, log logr
 func (r *IPXEBootConfigReconciler) reconcile(ctx context.Context, log logr.Logger, config *bootv1alpha1.IPXEBootConfig) (ctrl.Result, error) {
 	log.V(1).Info("Reconciling IPXEBootConfig")
 
+	if err := rearmIgnition(ctx, r.Client, config, &config.Status.Conditions); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.V(1).Info("Checking SystemUUID")
+	if err := r.ensureSystemUUIDCondition(ctx, config); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	log.V(1).Info("Ensuring Ignition")
 	state, err := r.ensureIgnition(ctx, log, config)
 	if err != nil {
-		if err := r.patchStatus(ctx, config, state); err != nil {
+		if err := r.patchStatus(ctx, config, state, config.Status.IgnitionToken); err != nil {
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, fmt.Errorf("failed to ensure Ignition: %w", err)
 	}
 	log.V(1).Info("Ensured Ignition")
 
-	if err := r.patchStatus(ctx, config, state); err != nil {
+	log.V(1).Info("Ensuring iPXE template")
+	if state, err = r.ensureIPXETemplate(ctx, config); err != nil {
+		if err := r.patchStatus(ctx, config, state, config.Status.IgnitionToken); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to ensure iPXE template: %w", err)
+	}
+	log.V(1).Info("Ensured iPXE template")
+
+	token, renewAfter, err := ensureIgnitionToken(config.Status.IgnitionToken, config.Generation, r.IgnitionTokenTTL, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.patchStatus(ctx, config, state, token); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to patch status %w", err)
 	}
 
 	log.V(1).Info("Reconciled IPXEBootConfig")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: renewAfter}, nil
 }
 
 func (r *IPXEBootConfigReconciler) ensureIgnition(ctx context.Context, _ logr.Logger, config *bootv1alpha1.IPXEBootConfig) (bootv1alpha1.IPXEBootConfigState, error) {
-	// Verify if the IgnitionRef is set, and it has the intended data key.
-	if config.Spec.IgnitionSecretRef != nil {
-		IgnitionSecret := &corev1.Secret{}
-		if err := r.Get(ctx, client.ObjectKey{Name: config.Spec.IgnitionSecretRef.Name, Namespace: config.Namespace}, IgnitionSecret); err != nil {
-			return bootv1alpha1.IPXEBootConfigStateError, err
-			// TODO: Add some validation steps to ensure that the IgntionData is compliant, if necessary.
-			// Assume for now, that it's going to json format.
-		}
-		if IgnitionSecret.Data[bootv1alpha1.DefaultIgnitionKey] == nil {
-			return bootv1alpha1.IPXEBootConfigStateError, fmt.Errorf("ignition data is missing")
-		}
+	// Verify that the ignition data referenced by the IgnitionRef, if any, can be served.
+	condition, err := ignitionCondition(ctx, r.Client, config, config.Spec.IgnitionSecretRef, config.Spec.IgnitionLayers)
+	if err != nil {
+		return bootv1alpha1.IPXEBootConfigStateError, err
+	}
+	if err := updateCondition(ctx, r.Client, config, &config.Status.Conditions, bootv1alpha1.IgnitionValidCondition, condition); err != nil {
+		return bootv1alpha1.IPXEBootConfigStateError, err
+	}
+	if condition != nil && condition.Status == metav1.ConditionFalse {
+		return bootv1alpha1.IPXEBootConfigStateError, errors.New(condition.Message)
 	}
 
 	return bootv1alpha1.IPXEBootConfigStateReady, nil
 }
 
+// ensureIPXETemplate verifies that the iPXE template referenced by the config, or its custom iPXE
+// script if that is templated, renders into an iPXE script.
+func (r *IPXEBootConfigReconciler) ensureIPXETemplate(ctx context.Context, config *bootv1alpha1.IPXEBootConfig) (bootv1alpha1.IPXEBootConfigState, error) {
+	condition, err := ipxeTemplateCondition(ctx, r.Client, config)
+	if err != nil {
+		return bootv1alpha1.IPXEBootConfigStateError, err
+	}
+	if err := updateCondition(ctx, r.Client, config, &config.Status.Conditions, bootv1alpha1.IPXETemplateValidCondition, condition); err != nil {
+		return bootv1alpha1.IPXEBootConfigStateError, err
+	}
+	if condition != nil && condition.Status == metav1.ConditionFalse {
+		return bootv1alpha1.IPXEBootConfigStateError, errors.New(condition.Message)
+	}
+
+	return bootv1alpha1.IPXEBootConfigStateReady, nil
+}
+
+// ensureSystemUUIDCondition flags the config if its SystemUUID is invalid or shared with an
+// IPXEBootConfig of another Server.
+func (r *IPXEBootConfigReconciler) ensureSystemUUIDCondition(ctx context.Context, config *bootv1alpha1.IPXEBootConfig) error {
+	sharing, err := listSharingSystemUUID(ctx, r.Client, config, config.Spec.SystemUUID, func() client.ObjectList {
+		return &bootv1alpha1.IPXEBootConfigList{}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list IPXEBootConfigs sharing SystemUUID: %w", err)
+	}
+
+	condition, err := systemUUIDCondition(ctx, r.Client, config, config.Spec.SystemUUID, sharing)
+	if err != nil {
+		return err
+	}
+	return updateCondition(ctx, r.Client, config, &config.Status.Conditions, bootv1alpha1.SystemUUIDAmbiguousCondition, condition)
+}
+
 func (r *IPXEBootConfigReconciler) delete(_ context.Context, log logr.Logger, _ *bootv1alpha1.IPXEBootConfig) (ctrl.Result, error) {
 	log.V(1).Info("Deleting IPXEBootConfig")
 
@@ -97,9 +160,11 @@ func (r *IPXEBootConfigReconciler) patchStatus(
 	ctx context.Context,
 	ipxeBootConfig *bootv1alpha1.IPXEBootConfig,
 	state bootv1alpha1.IPXEBootConfigState,
+	token *bootv1alpha1.IgnitionToken,
 ) error {
 	base := ipxeBootConfig.DeepCopy()
 	ipxeBootConfig.Status.State = state
+	ipxeBootConfig.Status.IgnitionToken = token
 
 	if err := r.Status().Patch(ctx, ipxeBootConfig, client.MergeFrom(base)); err != nil {
 		return fmt.Errorf("error patching ipxeBootConfig: %w", err)
@@ -107,7 +172,7 @@ func (r *IPXEBootConfigReconciler) patchStatus(
 	return nil
 }
 
-func (r *IPXEBootConfigReconciler) enqueueIPXEBootConfigReferencingIgnitionSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+func (r *IPXEBootConfigReconciler) enqueueIPXEBootConfigReferencingSecret(ctx context.Context, secret client.Object) []reconcile.Request {
 	log := ctrl.LoggerFrom(ctx)
 	secretObj, ok := secret.(*corev1.Secret)
 	if !ok {
@@ -123,7 +188,8 @@ func (r *IPXEBootConfigReconciler) enqueueIPXEBootConfigReferencingIgnitionSecre
 
 	var requests []reconcile.Request
 	for _, config := range configList.Items {
-		if config.Spec.IgnitionSecretRef != nil && config.Spec.IgnitionSecretRef.Name == secretObj.Name {
+		if referencesIgnitionSecret(config.Spec.IgnitionSecretRef, config.Spec.IgnitionLayers, secretObj.Name) ||
+			(config.Spec.IPXEScriptSecretRef != nil && config.Spec.IPXEScriptSecretRef.Name == secretObj.Name) {
 			requests = append(requests, reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Name:      config.Name,
@@ -135,13 +201,66 @@ func (r *IPXEBootConfigReconciler) enqueueIPXEBootConfigReferencingIgnitionSecre
 	return requests
 }
 
+func (r *IPXEBootConfigReconciler) enqueueIPXEBootConfigReferencingIPXETemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := ctrl.LoggerFrom(ctx)
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok {
+		log.Error(nil, "cant decode object into ConfigMap", obj)
+		return nil
+	}
+
+	configList := &bootv1alpha1.IPXEBootConfigList{}
+	if err := r.List(ctx, configList, client.InNamespace(configMap.Namespace)); err != nil {
+		log.Error(err, "failed to list IPXEBootConfig for ConfigMap", "ConfigMap", client.ObjectKeyFromObject(configMap))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, config := range configList.Items {
+		if config.Spec.IPXETemplateRef != nil && config.Spec.IPXETemplateRef.Name == configMap.Name {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&config)})
+		}
+	}
+	return requests
+}
+
+func (r *IPXEBootConfigReconciler) enqueueIPXEBootConfigsSharingSystemUUID(ctx context.Context, obj client.Object) []reconcile.Request {
+	log := ctrl.LoggerFrom(ctx)
+	config, ok := obj.(*bootv1alpha1.IPXEBootConfig)
+	if !ok || config.Spec.SystemUUID == "" {
+		return nil
+	}
+
+	sharing, err := listSharingSystemUUID(ctx, r.Client, config, config.Spec.SystemUUID, func() client.ObjectList {
+		return &bootv1alpha1.IPXEBootConfigList{}
+	})
+	if err != nil {
+		log.Error(err, "failed to list IPXEBootConfig sharing SystemUUID", "SystemUUID", config.Spec.SystemUUID)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, other := range sharing {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(other)})
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *IPXEBootConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&bootv1alpha1.IPXEBootConfig{}).
 		Watches(
 			&corev1.Secret{},
-			handler.EnqueueRequestsFromMapFunc(r.enqueueIPXEBootConfigReferencingIgnitionSecret),
+			handler.EnqueueRequestsFromMapFunc(r.enqueueIPXEBootConfigReferencingSecret),
+		).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueIPXEBootConfigReferencingIPXETemplate),
+		).
+		Watches(
+			&bootv1alpha1.IPXEBootConfig{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueIPXEBootConfigsSharingSystemUUID),
 		).
 		Complete(r)
 }
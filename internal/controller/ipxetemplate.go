@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/ipxe"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ipxeTemplateCondition returns the IPXETemplateValid condition of an IPXEBootConfig for the iPXE
+// template it references or its templated custom iPXE script, or nil if the iPXE script is rendered
+// from neither.
+func ipxeTemplateCondition(ctx context.Context, c client.Client, config *bootv1alpha1.IPXEBootConfig) (*metav1.Condition, error) {
+	if secretRef := config.Spec.IPXEScriptSecretRef; secretRef != nil {
+		return ipxeScriptTemplateCondition(ctx, c, config, secretRef.Name)
+	}
+	templateRef := config.Spec.IPXETemplateRef
+	if templateRef == nil {
+		return nil, nil
+	}
+	condition := &metav1.Condition{
+		Type:               bootv1alpha1.IPXETemplateValidCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: config.Generation,
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: config.Namespace, Name: templateRef.Name}, configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get iPXE template ConfigMap %s: %w", templateRef.Name, err)
+		}
+		condition.Reason = bootv1alpha1.MissingIPXETemplateReason
+		condition.Message = fmt.Sprintf("ConfigMap %s not found.", templateRef.Name)
+		return condition, nil
+	}
+	text, ok := configMap.Data[bootv1alpha1.DefaultIPXETemplateKey]
+	if !ok {
+		condition.Reason = bootv1alpha1.MissingIPXETemplateReason
+		condition.Message = fmt.Sprintf("ConfigMap %s has no %s key.", templateRef.Name, bootv1alpha1.DefaultIPXETemplateKey)
+		return condition, nil
+	}
+
+	if err := ipxe.Validate(text); err != nil {
+		condition.Reason = bootv1alpha1.InvalidIPXETemplateReason
+		condition.Message = fmt.Sprintf("iPXE template of ConfigMap %s is invalid: %v", templateRef.Name, err)
+		return condition, nil
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = bootv1alpha1.ValidIPXETemplateReason
+	condition.Message = fmt.Sprintf("iPXE template of ConfigMap %s is valid.", templateRef.Name)
+	return condition, nil
+}
+
+// ipxeScriptTemplateCondition returns the IPXETemplateValid condition of an IPXEBootConfig for the
+// custom iPXE script stored in the Secret with the given name, or nil if the script is served
+// verbatim. As the Secret tells whether the script is templated, nil is returned if it does not
+// exist too.
+func ipxeScriptTemplateCondition(ctx context.Context, c client.Client, config *bootv1alpha1.IPXEBootConfig, name string) (*metav1.Condition, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: config.Namespace, Name: name}, secret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if !ipxe.IsTemplate(secret) {
+		return nil, nil
+	}
+	condition := &metav1.Condition{
+		Type:               bootv1alpha1.IPXETemplateValidCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: config.Generation,
+	}
+
+	script, ok := secret.Data[bootv1alpha1.DefaultIPXEScriptKey]
+	if !ok {
+		condition.Reason = bootv1alpha1.MissingIPXETemplateReason
+		condition.Message = fmt.Sprintf("Secret %s has no %s key.", name, bootv1alpha1.DefaultIPXEScriptKey)
+		return condition, nil
+	}
+	if err := ipxe.Validate(string(script)); err != nil {
+		condition.Reason = bootv1alpha1.InvalidIPXETemplateReason
+		condition.Message = fmt.Sprintf("iPXE script template of Secret %s is invalid: %v", name, err)
+		return condition, nil
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = bootv1alpha1.ValidIPXETemplateReason
+	condition.Message = fmt.Sprintf("iPXE script template of Secret %s is valid.", name)
+	return condition, nil
+}
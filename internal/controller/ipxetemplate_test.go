@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIPXEBootConfigIPXETemplateCondition(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := bootv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	configMap := func(name string, data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Data: data}
+	}
+	scriptSecret := func(name string, data map[string]string) *corev1.Secret {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Data: map[string][]byte{}}
+		for key, value := range data {
+			secret.Data[key] = []byte(value)
+		}
+		return secret
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			configMap("valid", map[string]string{
+				bootv1alpha1.DefaultIPXETemplateKey: "#!ipxe\nkernel {{.KernelURL}} console=ttyS1,9600\ninitrd {{.InitrdURL}}\nboot\n",
+			}),
+			configMap("invalid", map[string]string{bootv1alpha1.DefaultIPXETemplateKey: "#!ipxe\nkernel {{.Kernel}}\n"}),
+			configMap("no-key", map[string]string{"script": "#!ipxe\n"}),
+			scriptSecret("verbatim-script", map[string]string{bootv1alpha1.DefaultIPXEScriptKey: "#!ipxe\nkernel {{.Kernel}}\n"}),
+			scriptSecret("templated-script", map[string]string{
+				bootv1alpha1.DefaultTemplateKey:   "true",
+				bootv1alpha1.DefaultIPXEScriptKey: "#!ipxe\nchain {{.IPXEServerURL}}/custom/{{.SystemUUID}}\n",
+			}),
+			scriptSecret("invalid-script", map[string]string{
+				bootv1alpha1.DefaultTemplateKey:   "true",
+				bootv1alpha1.DefaultIPXEScriptKey: "#!ipxe\nkernel {{.Kernel}}\n",
+			}),
+			scriptSecret("no-key-script", map[string]string{bootv1alpha1.DefaultTemplateKey: "true"}),
+		).
+		WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}).
+		Build()
+	r := &IPXEBootConfigReconciler{Client: c, Scheme: scheme}
+
+	tests := []struct {
+		name         string
+		template     string
+		scriptSecret string
+		wantState    bootv1alpha1.IPXEBootConfigState
+		wantStatus   metav1.ConditionStatus
+		wantReason   string
+	}{
+		{name: "default", wantState: bootv1alpha1.IPXEBootConfigStateReady},
+		{name: "valid", template: "valid", wantState: bootv1alpha1.IPXEBootConfigStateReady, wantStatus: metav1.ConditionTrue, wantReason: bootv1alpha1.ValidIPXETemplateReason},
+		{name: "invalid", template: "invalid", wantState: bootv1alpha1.IPXEBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.InvalidIPXETemplateReason},
+		{name: "no-key", template: "no-key", wantState: bootv1alpha1.IPXEBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.MissingIPXETemplateReason},
+		{name: "missing", template: "missing", wantState: bootv1alpha1.IPXEBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.MissingIPXETemplateReason},
+		{name: "custom-script", template: "missing", scriptSecret: "script", wantState: bootv1alpha1.IPXEBootConfigStateReady},
+		{name: "verbatim-script", scriptSecret: "verbatim-script", wantState: bootv1alpha1.IPXEBootConfigStateReady},
+		{name: "templated-script", template: "invalid", scriptSecret: "templated-script", wantState: bootv1alpha1.IPXEBootConfigStateReady, wantStatus: metav1.ConditionTrue, wantReason: bootv1alpha1.ValidIPXETemplateReason},
+		{name: "invalid-script", scriptSecret: "invalid-script", wantState: bootv1alpha1.IPXEBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.InvalidIPXETemplateReason},
+		{name: "no-key-script", scriptSecret: "no-key-script", wantState: bootv1alpha1.IPXEBootConfigStateError, wantStatus: metav1.ConditionFalse, wantReason: bootv1alpha1.MissingIPXETemplateReason},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &bootv1alpha1.IPXEBootConfig{ObjectMeta: metav1.ObjectMeta{Name: "config-" + tt.name, Namespace: "default"}}
+			if tt.template != "" {
+				config.Spec.IPXETemplateRef = &corev1.LocalObjectReference{Name: tt.template}
+			}
+			if tt.scriptSecret != "" {
+				config.Spec.IPXEScriptSecretRef = &corev1.LocalObjectReference{Name: tt.scriptSecret}
+			}
+			if err := c.Create(ctx, config); err != nil {
+				t.Fatal(err)
+			}
+
+			state, err := r.ensureIPXETemplate(ctx, config)
+			if state != tt.wantState || (err != nil) != (tt.wantState == bootv1alpha1.IPXEBootConfigStateError) {
+				t.Fatalf("ensureIPXETemplate() = %s, %v, want state %s", state, err, tt.wantState)
+			}
+
+			stored := &bootv1alpha1.IPXEBootConfig{}
+			if err := c.Get(ctx, client.ObjectKeyFromObject(config), stored); err != nil {
+				t.Fatal(err)
+			}
+			condition := apimeta.FindStatusCondition(stored.Status.Conditions, bootv1alpha1.IPXETemplateValidCondition)
+			switch {
+			case tt.wantReason == "" && condition != nil:
+				t.Errorf("unexpected condition %+v", condition)
+			case tt.wantReason != "" && (condition == nil || condition.Status != tt.wantStatus || condition.Reason != tt.wantReason):
+				t.Errorf("condition = %+v, want status %s and reason %s", condition, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestIPXETemplateOverride(t *testing.T) {
+	for annotation, want := range map[string]string{
+		"":                "",
+		"serial-console":  "serial-console",
+		" serial-console": "serial-console",
+	} {
+		config := &metalv1alpha1.ServerBootConfiguration{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{bootv1alpha1.IPXETemplateAnnotation: annotation},
+		}}
+		got := ""
+		if ref := IPXETemplateOverride(config); ref != nil {
+			got = ref.Name
+		}
+		if got != want {
+			t.Errorf("IPXETemplateOverride(%q) = %q, want %q", annotation, got, want)
+		}
+	}
+}
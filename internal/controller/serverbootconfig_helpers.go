@@ -19,10 +19,14 @@ package controller
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/distribution/reference"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/prefetch"
 	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
@@ -33,6 +37,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	// BootArtifactsPrefetchedCondition reports whether the boot artifacts of a
+	// ServerBootConfiguration are available in the image proxy's cache.
+	BootArtifactsPrefetchedCondition = "BootArtifactsPrefetched"
+
+	// prefetchPollInterval is how often a ServerBootConfiguration is requeued while its
+	// boot artifacts are being prefetched.
+	prefetchPollInterval = 10 * time.Second
+)
+
 // ParseImageReference parses an OCI image reference and returns the image name and version.
 // It handles tagged references, digest references, and untagged references (defaulting to "latest").
 func ParseImageReference(image string) (imageName, imageVersion string, err error) {
@@ -68,14 +82,68 @@ func BuildImageReference(imageName, imageVersion string) string {
 // buildImageURL constructs a percent-encoded image proxy URL.
 // url.Values.Encode ensures the colon in digest versions (e.g. sha256:<hash>) is encoded
 // as %3A so it is not misinterpreted as a delimiter by HTTP clients or kube-apiserver.
-func buildImageURL(serviceURL, imageName, imageVersion, layerDigest string) string {
+// If pullSecret is set, it is passed on so the proxy authenticates with the same Secret.
+func buildImageURL(serviceURL, imageName, imageVersion, layerDigest string, pullSecret *client.ObjectKey) string {
 	params := url.Values{}
 	params.Set("imageName", imageName)
 	params.Set("version", imageVersion)
 	params.Set("layerDigest", layerDigest)
+	if pullSecret != nil {
+		params.Set("pullSecret", pullSecret.String())
+	}
 	return serviceURL + "/image?" + params.Encode()
 }
 
+// ImagePullSecretOverride returns the pull secret referenced by the ServerBootConfiguration's
+// image pull secret annotation, or nil if the global pull secret should be used.
+func ImagePullSecretOverride(config *metalv1alpha1.ServerBootConfiguration) *client.ObjectKey {
+	name := strings.TrimSpace(config.Annotations[bootv1alpha1.ImagePullSecretAnnotation])
+	if name == "" {
+		return nil
+	}
+	return &client.ObjectKey{Namespace: config.Namespace, Name: name}
+}
+
+// IgnitionPolicyOverride returns the IgnitionPolicy requested by the ServerBootConfiguration's
+// ignition policy annotation, or an empty policy if the annotation is not set.
+func IgnitionPolicyOverride(config *metalv1alpha1.ServerBootConfiguration) (bootv1alpha1.IgnitionPolicy, error) {
+	policy := bootv1alpha1.IgnitionPolicy(strings.TrimSpace(config.Annotations[bootv1alpha1.IgnitionPolicyAnnotation]))
+	switch policy {
+	case "", bootv1alpha1.IgnitionPolicyAlways, bootv1alpha1.IgnitionPolicyOnce, bootv1alpha1.IgnitionPolicyUntilReady:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid ignition policy %q in annotation %s", policy, bootv1alpha1.IgnitionPolicyAnnotation)
+	}
+}
+
+// IgnitionLayersOverride returns the ignition layers listed by the ServerBootConfiguration's ignition
+// layers annotation, in order.
+func IgnitionLayersOverride(config *metalv1alpha1.ServerBootConfiguration) []bootv1alpha1.IgnitionLayer {
+	var layers []bootv1alpha1.IgnitionLayer
+	for name := range strings.SplitSeq(config.Annotations[bootv1alpha1.IgnitionLayersAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			layers = append(layers, bootv1alpha1.IgnitionLayer{SecretRef: corev1.LocalObjectReference{Name: name}})
+		}
+	}
+	return layers
+}
+
+// IPXETemplateOverride returns a reference to the iPXE template ConfigMap named by the
+// ServerBootConfiguration's iPXE template annotation, or nil if the annotation is not set.
+func IPXETemplateOverride(config *metalv1alpha1.ServerBootConfiguration) *corev1.LocalObjectReference {
+	name := strings.TrimSpace(config.Annotations[bootv1alpha1.IPXETemplateAnnotation])
+	if name == "" {
+		return nil
+	}
+	return &corev1.LocalObjectReference{Name: name}
+}
+
+// KernelArgsOverride returns the kernel arguments listed by the ServerBootConfiguration's kernel
+// arguments annotation, in order.
+func KernelArgsOverride(config *metalv1alpha1.ServerBootConfiguration) []string {
+	return strings.Fields(config.Annotations[bootv1alpha1.KernelArgsAnnotation])
+}
+
 // ExtractServerNetworkIDs extracts IP addresses (and optionally MAC addresses) from a Server's network interfaces.
 // Returns a slice of IP addresses as strings. If includeMACAddresses is true, MAC addresses are also included.
 func ExtractServerNetworkIDs(server *metalv1alpha1.Server, includeMACAddresses bool) []string {
@@ -100,6 +168,20 @@ func ExtractServerNetworkIDs(server *metalv1alpha1.Server, includeMACAddresses b
 	return ids
 }
 
+// ExtractServerMACAddresses returns the MAC addresses of a Server's network interfaces in their
+// canonical, lowercase colon-separated form. Invalid MAC addresses are skipped.
+func ExtractServerMACAddresses(server *metalv1alpha1.Server) []string {
+	macs := make([]string, 0, len(server.Status.NetworkInterfaces))
+	for _, nic := range server.Status.NetworkInterfaces {
+		mac, err := net.ParseMAC(nic.MACAddress)
+		if err != nil {
+			continue
+		}
+		macs = append(macs, mac.String())
+	}
+	return macs
+}
+
 // EnqueueServerBootConfigsReferencingSecret finds all ServerBootConfigurations in the same namespace
 // that reference the given Secret via IgnitionSecretRef and returns reconcile requests for them.
 func EnqueueServerBootConfigsReferencingSecret(ctx context.Context, c client.Client, secret client.Object) []reconcile.Request {
@@ -155,3 +237,52 @@ func PatchServerBootConfigWithError(
 
 	return c.Status().Patch(ctx, &cur, client.MergeFrom(base))
 }
+
+// PrefetchBootArtifacts starts prefetching the given layers of the ServerBootConfiguration's
+// image into the image proxy's cache and returns a BootArtifactsPrefetched condition
+// describing the progress. It returns nil if prefetcher is nil, i.e. prefetching is disabled.
+func PrefetchBootArtifacts(
+	ctx context.Context,
+	prefetcher *prefetch.Prefetcher,
+	config *metalv1alpha1.ServerBootConfiguration,
+	layerDigests []string,
+) (*metav1.Condition, error) {
+	if prefetcher == nil {
+		return nil, nil
+	}
+	imageName, imageVersion, err := ParseImageReference(config.Spec.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := prefetcher.Prefetch(ctx, BuildImageReference(imageName, imageVersion), ImagePullSecretOverride(config), layerDigests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prefetch boot artifacts: %w", err)
+	}
+
+	condition := &metav1.Condition{
+		Type:               BootArtifactsPrefetchedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Prefetching",
+		Message:            progress.String(),
+		ObservedGeneration: config.Generation,
+	}
+	switch {
+	case progress.Complete():
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Prefetched"
+	case progress.Err != nil:
+		condition.Reason = "PrefetchFailed"
+		condition.Message = fmt.Sprintf("%s, retrying: %v", progress, progress.Err)
+	}
+	return condition, nil
+}
+
+// prefetchResult requeues the ServerBootConfiguration while its boot artifacts are
+// still being prefetched.
+func prefetchResult(prefetched *metav1.Condition) ctrl.Result {
+	if prefetched != nil && prefetched.Status != metav1.ConditionTrue {
+		return ctrl.Result{RequeueAfter: prefetchPollInterval}
+	}
+	return ctrl.Result{}
+}
@@ -4,17 +4,29 @@
 package controller
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"slices"
 	"testing"
 
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/blobcache"
+	"github.com/ironcore-dev/boot-operator/internal/prefetch"
 	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
 	corev1 "k8s.io/api/core/v1"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	. "sigs.k8s.io/controller-runtime/pkg/envtest/komega"
 )
 
@@ -95,7 +107,7 @@ func TestImageURLFromSpecImage(t *testing.T) {
 		{"initrd", initrdDigest},
 		{"squashfs", squashDigest},
 	} {
-		rawURL := buildImageURL(serviceURL, imageName, imageVersion, tc.digest)
+		rawURL := buildImageURL(serviceURL, imageName, imageVersion, tc.digest, nil)
 		parsed, err := url.Parse(rawURL)
 		if err != nil {
 			t.Fatalf("%s: url.Parse(%q) error: %v", tc.label, rawURL, err)
@@ -113,6 +125,101 @@ func TestImageURLFromSpecImage(t *testing.T) {
 	}
 }
 
+func TestImageURLWithPullSecret(t *testing.T) {
+	pullSecret := &client.ObjectKey{Namespace: "tenant", Name: "harbor-credentials"}
+	rawURL := buildImageURL("http://boot.example.com", "harbor.example.com/os/gardenlinux", "1.0",
+		"sha256:f1b8b8dfd3b9f810662becdbcf508357fb71ad5c0c709a97350522d71e0592ad", pullSecret)
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error: %v", rawURL, err)
+	}
+	if got := parsed.Query().Get("pullSecret"); got != "tenant/harbor-credentials" {
+		t.Errorf("pullSecret = %q, want %q", got, "tenant/harbor-credentials")
+	}
+}
+
+func TestExtractServerMACAddresses(t *testing.T) {
+	server := &metalv1alpha1.Server{Status: metalv1alpha1.ServerStatus{
+		NetworkInterfaces: []metalv1alpha1.NetworkInterface{
+			{Name: "eth0", MACAddress: "52:54:00:AB:CD:EF"},
+			{Name: "eth1", MACAddress: "52-54-00-12-34-56"},
+			{Name: "eth2", MACAddress: "invalid"},
+			{Name: "eth3"},
+		},
+	}}
+	got := ExtractServerMACAddresses(server)
+	want := []string{"52:54:00:ab:cd:ef", "52:54:00:12:34:56"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ExtractServerMACAddresses() = %v, want %v", got, want)
+	}
+}
+
+func TestKernelArgsOverride(t *testing.T) {
+	for annotation, want := range map[string][]string{
+		"":                                     nil,
+		"console=ttyS1,115200":                 {"console=ttyS1,115200"},
+		" console=ttyS1,115200\n-earlyprintk ": {"console=ttyS1,115200", "-earlyprintk"},
+	} {
+		config := &metalv1alpha1.ServerBootConfiguration{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{bootv1alpha1.KernelArgsAnnotation: annotation},
+		}}
+		if got := KernelArgsOverride(config); !slices.Equal(got, want) {
+			t.Errorf("KernelArgsOverride(%q) = %v, want %v", annotation, got, want)
+		}
+	}
+}
+
+func TestPrefetchBootArtifacts(t *testing.T) {
+	registryServer := httptest.NewServer(http.NotFoundHandler())
+	defer registryServer.Close()
+	cache, err := blobcache.New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("blobcache.New() error = %v", err)
+	}
+	kernel := []byte("kernel")
+	kernelDigest := digest.FromBytes(kernel)
+	if err := cache.Fill(context.Background(), kernelDigest, func(context.Context) (io.ReadCloser, int64, error) {
+		return io.NopCloser(bytes.NewReader(kernel)), int64(len(kernel)), nil
+	}); err != nil {
+		t.Fatalf("Fill() error = %v", err)
+	}
+	prefetcher := prefetch.NewPrefetcher(cache, nil, logr.Discard())
+	config := &metalv1alpha1.ServerBootConfiguration{
+		Spec: metalv1alpha1.ServerBootConfigurationSpec{
+			Image: registryServer.Listener.Addr().String() + "/os/gardenlinux:1.0",
+		},
+	}
+
+	condition, err := PrefetchBootArtifacts(context.Background(), nil, config, []string{kernelDigest.String()})
+	if err != nil || condition != nil {
+		t.Fatalf("PrefetchBootArtifacts() without prefetcher = %v, %v; want nil, nil", condition, err)
+	}
+
+	condition, err = PrefetchBootArtifacts(context.Background(), prefetcher, config, []string{kernelDigest.String()})
+	if err != nil {
+		t.Fatalf("PrefetchBootArtifacts() error = %v", err)
+	}
+	if condition.Status != metav1.ConditionTrue {
+		t.Errorf("condition status = %s, want %s", condition.Status, metav1.ConditionTrue)
+	}
+	if result := prefetchResult(condition); result.RequeueAfter != 0 {
+		t.Errorf("prefetchResult() = %v, want no requeue", result)
+	}
+
+	condition, err = PrefetchBootArtifacts(context.Background(), prefetcher, config,
+		[]string{kernelDigest.String(), digest.FromString("initrd").String()})
+	if err != nil {
+		t.Fatalf("PrefetchBootArtifacts() error = %v", err)
+	}
+	if condition.Status != metav1.ConditionFalse || condition.Message != "1 of 2 boot artifacts cached" {
+		t.Errorf("condition = %s %q, want %s %q", condition.Status, condition.Message,
+			metav1.ConditionFalse, "1 of 2 boot artifacts cached")
+	}
+	if result := prefetchResult(condition); result.RequeueAfter != prefetchPollInterval {
+		t.Errorf("prefetchResult() = %v, want requeue after %s", result, prefetchPollInterval)
+	}
+}
+
 var _ = Describe("PatchServerBootConfigWithError", func() {
 	var ns *corev1.Namespace
 
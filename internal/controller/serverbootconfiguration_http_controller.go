@@ -7,12 +7,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 
-	"github.com/containerd/containerd/remotes/docker"
 	"github.com/ironcore-dev/boot-operator/internal/oci"
+	"github.com/ironcore-dev/boot-operator/internal/prefetch"
 	"github.com/ironcore-dev/boot-operator/internal/registry"
 
 	corev1 "k8s.io/api/core/v1"
@@ -41,6 +42,10 @@ type ServerBootConfigurationHTTPReconciler struct {
 	ImageServerURL    string
 	Architecture      string
 	RegistryValidator *registry.Validator
+	CredentialStore   *registry.CredentialStore
+	// Prefetcher warms the image proxy's cache before the configuration is reported
+	// Ready. Prefetching is disabled if nil.
+	Prefetcher *prefetch.Prefetcher
 }
 
 //+kubebuilder:rbac:groups=metal.ironcore.dev,resources=serverbootconfigurations,verbs=get;list;watch
@@ -87,7 +92,7 @@ func (r *ServerBootConfigurationHTTPReconciler) reconcile(ctx context.Context, l
 	}
 	log.V(1).Info("Got Network Identifiers from Server", "networkIdentifiers", networkIdentifiers)
 
-	ukiURL, err := r.constructUKIURL(ctx, config.Spec.Image)
+	ukiURL, ukiDigest, err := r.constructUKIURL(ctx, config.Spec.Image, ImagePullSecretOverride(config))
 	if err != nil {
 		log.Error(err, "Failed to construct UKI URL")
 		if patchErr := PatchServerBootConfigWithError(ctx, r.Client,
@@ -98,6 +103,20 @@ func (r *ServerBootConfigurationHTTPReconciler) reconcile(ctx context.Context, l
 	}
 	log.V(1).Info("Extracted UKI URL for boot")
 
+	ignitionPolicy, err := IgnitionPolicyOverride(config)
+	if err != nil {
+		if patchErr := PatchServerBootConfigWithError(ctx, r.Client,
+			types.NamespacedName{Name: config.Name, Namespace: config.Namespace}, err); patchErr != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to patch state to error: %w (original error: %w)", patchErr, err)
+		}
+		return ctrl.Result{}, err
+	}
+
+	prefetched, err := PrefetchBootArtifacts(ctx, r.Prefetcher, config, []string{ukiDigest})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	httpBootConfig := &bootv1alpha1.HTTPBootConfig{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "boot.ironcore.dev/v1alpha1",
@@ -111,6 +130,8 @@ func (r *ServerBootConfigurationHTTPReconciler) reconcile(ctx context.Context, l
 			SystemUUID:         systemUUID,
 			NetworkIdentifiers: networkIdentifiers,
 			UKIURL:             ukiURL,
+			IgnitionPolicy:     ignitionPolicy,
+			IgnitionLayers:     IgnitionLayersOverride(config),
 		},
 	}
 	if config.Spec.IgnitionSecretRef != nil {
@@ -135,16 +156,16 @@ func (r *ServerBootConfigurationHTTPReconciler) reconcile(ctx context.Context, l
 		return ctrl.Result{}, fmt.Errorf("failed to get HTTPBoot config: %w", err)
 	}
 
-	if err := r.patchConfigStateFromHTTPState(ctx, httpBootConfig, config); err != nil {
+	if err := r.patchConfigStateFromHTTPState(ctx, httpBootConfig, config, prefetched); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to patch server boot config state to %s: %w", httpBootConfig.Status.State, err)
 	}
 	log.V(1).Info("Patched server boot config state")
 
 	log.V(1).Info("Reconciled ServerBootConfiguration")
-	return ctrl.Result{}, nil
+	return prefetchResult(prefetched), nil
 }
 
-func (r *ServerBootConfigurationHTTPReconciler) patchConfigStateFromHTTPState(ctx context.Context, httpBootConfig *bootv1alpha1.HTTPBootConfig, cfg *metalv1alpha1.ServerBootConfiguration) error {
+func (r *ServerBootConfigurationHTTPReconciler) patchConfigStateFromHTTPState(ctx context.Context, httpBootConfig *bootv1alpha1.HTTPBootConfig, cfg *metalv1alpha1.ServerBootConfiguration, prefetched *metav1.Condition) error {
 	key := types.NamespacedName{Name: cfg.Name, Namespace: cfg.Namespace}
 	var cur metalv1alpha1.ServerBootConfiguration
 	if err := r.Get(ctx, key, &cur); err != nil {
@@ -154,6 +175,11 @@ func (r *ServerBootConfigurationHTTPReconciler) patchConfigStateFromHTTPState(ct
 
 	switch httpBootConfig.Status.State {
 	case bootv1alpha1.HTTPBootConfigStateReady:
+		if prefetched != nil && prefetched.Status != metav1.ConditionTrue {
+			// Hold back Ready until the boot artifacts are cached.
+			cur.Status.State = metalv1alpha1.ServerBootConfigurationStatePending
+			break
+		}
 		cur.Status.State = metalv1alpha1.ServerBootConfigurationStateReady
 		// Remove ImageValidation condition when transitioning to Ready
 		apimeta.RemoveStatusCondition(&cur.Status.Conditions, "ImageValidation")
@@ -164,6 +190,9 @@ func (r *ServerBootConfigurationHTTPReconciler) patchConfigStateFromHTTPState(ct
 	for _, c := range httpBootConfig.Status.Conditions {
 		apimeta.SetStatusCondition(&cur.Status.Conditions, c)
 	}
+	if prefetched != nil {
+		apimeta.SetStatusCondition(&cur.Status.Conditions, *prefetched)
+	}
 
 	return r.Status().Patch(ctx, &cur, client.MergeFrom(base))
 }
@@ -187,29 +216,37 @@ func (r *ServerBootConfigurationHTTPReconciler) getSystemNetworkIDsFromServer(ct
 	return ExtractServerNetworkIDs(server, true), nil
 }
 
-func (r *ServerBootConfigurationHTTPReconciler) constructUKIURL(ctx context.Context, image string) (string, error) {
+// constructUKIURL returns the image proxy URL of the image's UKI layer along with its digest.
+func (r *ServerBootConfigurationHTTPReconciler) constructUKIURL(ctx context.Context, image string, pullSecret *client.ObjectKey) (string, string, error) {
 	imageName, imageVersion, err := ParseImageReference(image)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	ukiDigest, err := r.getUKIDigestFromNestedManifest(ctx, imageName, imageVersion)
+	ukiDigest, err := r.getUKIDigestFromNestedManifest(ctx, imageName, imageVersion, pullSecret)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch UKI layer digest: %w", err)
+		return "", "", fmt.Errorf("failed to fetch UKI layer digest: %w", err)
 	}
 
-	ukiDigest = strings.TrimPrefix(ukiDigest, "sha256:")
-	ukiURL := fmt.Sprintf("%s/%s/sha256-%s.efi", r.ImageServerURL, imageName, ukiDigest)
-	return ukiURL, nil
+	ukiURL := fmt.Sprintf("%s/%s/sha256-%s.efi", r.ImageServerURL, imageName, strings.TrimPrefix(ukiDigest, "sha256:"))
+	if pullSecret != nil {
+		// Let the image proxy authenticate with the same Secret.
+		ukiURL += "?" + url.Values{"pullSecret": {pullSecret.String()}}.Encode()
+	}
+	return ukiURL, ukiDigest, nil
 }
 
-func (r *ServerBootConfigurationHTTPReconciler) getUKIDigestFromNestedManifest(ctx context.Context, imageName, imageVersion string) (string, error) {
+func (r *ServerBootConfigurationHTTPReconciler) getUKIDigestFromNestedManifest(ctx context.Context, imageName, imageVersion string, pullSecret *client.ObjectKey) (string, error) {
 	imageRef := BuildImageReference(imageName, imageVersion)
 	if err := r.RegistryValidator.ValidateImageRegistry(imageRef); err != nil {
 		return "", fmt.Errorf("registry validation failed: %w", err)
 	}
 
-	resolver := docker.NewResolver(docker.ResolverOptions{})
+	keychain, err := r.CredentialStore.Keychain(ctx, imageRef, pullSecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to get registry credentials: %w", err)
+	}
+	resolver := oci.NewResolver(keychain)
 	name, desc, err := resolver.Resolve(ctx, imageRef)
 	if err != nil {
 		return "", fmt.Errorf("failed to resolve image reference: %w", err)
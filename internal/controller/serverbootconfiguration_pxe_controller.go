@@ -28,6 +28,7 @@ import (
 
 	"github.com/ironcore-dev/boot-operator/api/v1alpha1"
 	"github.com/ironcore-dev/boot-operator/internal/oci"
+	"github.com/ironcore-dev/boot-operator/internal/prefetch"
 	"github.com/ironcore-dev/boot-operator/internal/registry"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,8 +39,6 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-
-	"github.com/containerd/containerd/remotes/docker"
 )
 
 const (
@@ -58,6 +57,10 @@ type ServerBootConfigurationPXEReconciler struct {
 	IPXEServiceURL    string
 	Architecture      string
 	RegistryValidator *registry.Validator
+	CredentialStore   *registry.CredentialStore
+	// Prefetcher warms the image proxy's cache before the configuration is reported
+	// Ready. Prefetching is disabled if nil.
+	Prefetcher *prefetch.Prefetcher
 }
 
 //+kubebuilder:rbac:groups=metal.ironcore.dev,resources=serverbootconfigurations,verbs=get;list;watch
@@ -99,13 +102,13 @@ func (r *ServerBootConfigurationPXEReconciler) reconcile(ctx context.Context, lo
 	}
 	log.V(1).Info("Got system UUID from BootConfig", "systemUUID", systemUUID)
 
-	systemIPs, err := r.getSystemIPFromBootConfig(ctx, bootConfig)
+	systemIPs, systemMACs, err := r.getSystemNetworkIDsFromBootConfig(ctx, bootConfig)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to get system IP from BootConfig: %w", err)
 	}
-	log.V(1).Info("Got system IP from BootConfig", "systemIPs", systemIPs)
+	log.V(1).Info("Got system IP from BootConfig", "systemIPs", systemIPs, "systemMACs", systemMACs)
 
-	kernelURL, initrdURL, squashFSURL, err := r.getImageDetailsFromConfig(ctx, log, bootConfig)
+	kernelURL, initrdURL, squashFSURL, layerDigests, err := r.getImageDetailsFromConfig(ctx, log, bootConfig)
 	if err != nil {
 		if patchErr := PatchServerBootConfigWithError(ctx, r.Client,
 			types.NamespacedName{Name: bootConfig.Name, Namespace: bootConfig.Namespace}, err); patchErr != nil {
@@ -115,6 +118,20 @@ func (r *ServerBootConfigurationPXEReconciler) reconcile(ctx context.Context, lo
 	}
 	log.V(1).Info("Extracted OS image layer details")
 
+	ignitionPolicy, err := IgnitionPolicyOverride(bootConfig)
+	if err != nil {
+		if patchErr := PatchServerBootConfigWithError(ctx, r.Client,
+			types.NamespacedName{Name: bootConfig.Name, Namespace: bootConfig.Namespace}, err); patchErr != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to patch server boot config state: %w (original error: %w)", patchErr, err)
+		}
+		return ctrl.Result{}, err
+	}
+
+	prefetched, err := PrefetchBootArtifacts(ctx, r.Prefetcher, bootConfig, layerDigests)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	config := &v1alpha1.IPXEBootConfig{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "boot.ironcore.dev/v1alpha1",
@@ -125,11 +142,16 @@ func (r *ServerBootConfigurationPXEReconciler) reconcile(ctx context.Context, lo
 			Name:      bootConfig.Name,
 		},
 		Spec: v1alpha1.IPXEBootConfigSpec{
-			SystemUUID:  systemUUID,
-			SystemIPs:   systemIPs,
-			KernelURL:   kernelURL,
-			InitrdURL:   initrdURL,
-			SquashfsURL: squashFSURL,
+			SystemUUID:      systemUUID,
+			SystemIPs:       systemIPs,
+			SystemMACs:      systemMACs,
+			KernelURL:       kernelURL,
+			InitrdURL:       initrdURL,
+			SquashfsURL:     squashFSURL,
+			KernelArgs:      KernelArgsOverride(bootConfig),
+			IgnitionPolicy:  ignitionPolicy,
+			IgnitionLayers:  IgnitionLayersOverride(bootConfig),
+			IPXETemplateRef: IPXETemplateOverride(bootConfig),
 		},
 	}
 	if bootConfig.Spec.IgnitionSecretRef != nil {
@@ -154,20 +176,25 @@ func (r *ServerBootConfigurationPXEReconciler) reconcile(ctx context.Context, lo
 		return ctrl.Result{}, fmt.Errorf("failed to get IPXE config: %w", err)
 	}
 
-	if err := r.patchConfigStateFromIPXEState(ctx, config, bootConfig); err != nil {
+	if err := r.patchConfigStateFromIPXEState(ctx, config, bootConfig, prefetched); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to patch server boot config state to %s: %w", config.Status.State, err)
 	}
 	log.V(1).Info("Patched server boot config state")
 
 	log.V(1).Info("Reconciled ServerBootConfiguration")
-	return ctrl.Result{}, nil
+	return prefetchResult(prefetched), nil
 }
 
-func (r *ServerBootConfigurationPXEReconciler) patchConfigStateFromIPXEState(ctx context.Context, config *v1alpha1.IPXEBootConfig, bootConfig *metalv1alpha1.ServerBootConfiguration) error {
+func (r *ServerBootConfigurationPXEReconciler) patchConfigStateFromIPXEState(ctx context.Context, config *v1alpha1.IPXEBootConfig, bootConfig *metalv1alpha1.ServerBootConfiguration, prefetched *metav1.Condition) error {
 	bootConfigBase := bootConfig.DeepCopy()
 
 	switch config.Status.State {
 	case v1alpha1.IPXEBootConfigStateReady:
+		if prefetched != nil && prefetched.Status != metav1.ConditionTrue {
+			// Hold back Ready until the boot artifacts are cached.
+			bootConfig.Status.State = metalv1alpha1.ServerBootConfigurationStatePending
+			break
+		}
 		bootConfig.Status.State = metalv1alpha1.ServerBootConfigurationStateReady
 		// Remove ImageValidation condition when transitioning to Ready
 		apimeta.RemoveStatusCondition(&bootConfig.Status.Conditions, "ImageValidation")
@@ -178,6 +205,9 @@ func (r *ServerBootConfigurationPXEReconciler) patchConfigStateFromIPXEState(ctx
 	for _, c := range config.Status.Conditions {
 		apimeta.SetStatusCondition(&bootConfig.Status.Conditions, c)
 	}
+	if prefetched != nil {
+		apimeta.SetStatusCondition(&bootConfig.Status.Conditions, *prefetched)
+	}
 
 	return r.Status().Patch(ctx, bootConfig, client.MergeFrom(bootConfigBase))
 }
@@ -191,49 +221,58 @@ func (r *ServerBootConfigurationPXEReconciler) getSystemUUIDFromBootConfig(ctx c
 	return server.Spec.SystemUUID, nil
 }
 
-func (r *ServerBootConfigurationPXEReconciler) getSystemIPFromBootConfig(ctx context.Context, config *metalv1alpha1.ServerBootConfiguration) ([]string, error) {
+func (r *ServerBootConfigurationPXEReconciler) getSystemNetworkIDsFromBootConfig(ctx context.Context, config *metalv1alpha1.ServerBootConfiguration) ([]string, []string, error) {
 	server := &metalv1alpha1.Server{}
 	if err := r.Get(ctx, client.ObjectKey{Name: config.Spec.ServerRef.Name}, server); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return ExtractServerNetworkIDs(server, false), nil
+	return ExtractServerNetworkIDs(server, false), ExtractServerMACAddresses(server), nil
 }
 
-func (r *ServerBootConfigurationPXEReconciler) getImageDetailsFromConfig(ctx context.Context, log logr.Logger, config *metalv1alpha1.ServerBootConfiguration) (string, string, string, error) {
+func (r *ServerBootConfigurationPXEReconciler) getImageDetailsFromConfig(ctx context.Context, log logr.Logger, config *metalv1alpha1.ServerBootConfiguration) (string, string, string, []string, error) {
 	imageName, imageVersion, err := ParseImageReference(config.Spec.Image)
 	if err != nil {
-		return "", "", "", err
+		return "", "", "", nil, err
 	}
 	log.V(1).Info("Parsed image reference", "specImage", config.Spec.Image, "imageName", imageName, "imageVersion", imageVersion)
 
-	kernelDigest, initrdDigest, squashFSDigest, err := r.getLayerDigestsFromNestedManifest(ctx, imageName, imageVersion)
+	pullSecret := ImagePullSecretOverride(config)
+	kernelDigest, initrdDigest, squashFSDigest, err := r.getLayerDigestsFromNestedManifest(ctx, imageName, imageVersion, pullSecret)
 	if err != nil {
-		return "", "", "", fmt.Errorf("failed to fetch layer digests: %w", err)
+		return "", "", "", nil, fmt.Errorf("failed to fetch layer digests: %w", err)
 	}
 
 	var kernelURL, initrdURL, squashFSURL string
+	var layerDigests []string
 	if kernelDigest != "" {
-		kernelURL = buildImageURL(r.IPXEServiceURL, imageName, imageVersion, kernelDigest)
+		kernelURL = buildImageURL(r.IPXEServiceURL, imageName, imageVersion, kernelDigest, pullSecret)
+		layerDigests = append(layerDigests, kernelDigest)
 	}
 	if initrdDigest != "" {
-		initrdURL = buildImageURL(r.IPXEServiceURL, imageName, imageVersion, initrdDigest)
+		initrdURL = buildImageURL(r.IPXEServiceURL, imageName, imageVersion, initrdDigest, pullSecret)
+		layerDigests = append(layerDigests, initrdDigest)
 	}
 	if squashFSDigest != "" {
-		squashFSURL = buildImageURL(r.IPXEServiceURL, imageName, imageVersion, squashFSDigest)
+		squashFSURL = buildImageURL(r.IPXEServiceURL, imageName, imageVersion, squashFSDigest, pullSecret)
+		layerDigests = append(layerDigests, squashFSDigest)
 	}
 	log.V(1).Info("Built image URLs", "kernelURL", kernelURL, "initrdURL", initrdURL, "squashfsURL", squashFSURL)
 
-	return kernelURL, initrdURL, squashFSURL, nil
+	return kernelURL, initrdURL, squashFSURL, layerDigests, nil
 }
 
-func (r *ServerBootConfigurationPXEReconciler) getLayerDigestsFromNestedManifest(ctx context.Context, imageName, imageVersion string) (string, string, string, error) {
+func (r *ServerBootConfigurationPXEReconciler) getLayerDigestsFromNestedManifest(ctx context.Context, imageName, imageVersion string, pullSecret *client.ObjectKey) (string, string, string, error) {
 	imageRef := BuildImageReference(imageName, imageVersion)
 	if err := r.RegistryValidator.ValidateImageRegistry(imageRef); err != nil {
 		return "", "", "", fmt.Errorf("registry validation failed: %w", err)
 	}
 
-	resolver := docker.NewResolver(docker.ResolverOptions{})
+	keychain, err := r.CredentialStore.Keychain(ctx, imageRef, pullSecret)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to get registry credentials: %w", err)
+	}
+	resolver := oci.NewResolver(keychain)
 	name, desc, err := resolver.Resolve(ctx, imageRef)
 	if err != nil {
 		return "", "", "", fmt.Errorf("failed to resolve image reference: %w", err)
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// systemUUIDCondition returns the SystemUUIDAmbiguous condition of a boot config, or nil if its
+// SystemUUID identifies a single server. sharing are the other boot configs of the same kind with
+// the same SystemUUID. Boot configs whose Server cannot be resolved are not considered duplicates.
+func systemUUIDCondition(ctx context.Context, c client.Client, config client.Object, uuid string, sharing []client.Object) (*metav1.Condition, error) {
+	condition := &metav1.Condition{
+		Type:               bootv1alpha1.SystemUUIDAmbiguousCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: config.GetGeneration(),
+	}
+	switch {
+	case uuid == "":
+		return nil, nil
+	case !systemuuid.IsValid(uuid):
+		condition.Reason = bootv1alpha1.InvalidSystemUUIDReason
+		condition.Message = fmt.Sprintf("SystemUUID %q is not a valid SMBIOS UUID.", uuid)
+		return condition, nil
+	case systemuuid.IsPlaceholder(uuid):
+		condition.Reason = bootv1alpha1.PlaceholderSystemUUIDReason
+		condition.Message = fmt.Sprintf("SystemUUID %s is a placeholder shipped by the vendor.", uuid)
+		return condition, nil
+	}
+
+	serverName, err := bootConfigServerName(ctx, c, config)
+	if err != nil || serverName == "" {
+		return nil, err
+	}
+	var duplicates []string
+	for _, other := range sharing {
+		otherServerName, err := bootConfigServerName(ctx, c, other)
+		if err != nil {
+			return nil, err
+		}
+		if otherServerName != "" && otherServerName != serverName {
+			duplicates = append(duplicates, fmt.Sprintf("%s (Server %s)", client.ObjectKeyFromObject(other), otherServerName))
+		}
+	}
+	if len(duplicates) == 0 {
+		return nil, nil
+	}
+	slices.Sort(duplicates)
+	condition.Reason = bootv1alpha1.DuplicateSystemUUIDReason
+	condition.Message = fmt.Sprintf("SystemUUID %s is also used by %s.", uuid, strings.Join(duplicates, ", "))
+	return condition, nil
+}
+
+// listSharingSystemUUID returns the boot configs other than config whose SystemUUID is uuid or its
+// mixed-endian variant, which the boot-server treats as the same server. newList returns an empty
+// list of the kind of config, which is looked up through the SystemUUID index.
+func listSharingSystemUUID(ctx context.Context, c client.Client, config client.Object, uuid string, newList func() client.ObjectList) ([]client.Object, error) {
+	if uuid == "" {
+		return nil, nil
+	}
+	keys := []string{systemuuid.Canonical(uuid)}
+	if swapped := systemuuid.Swapped(uuid); swapped != "" && swapped != keys[0] {
+		keys = append(keys, swapped)
+	}
+
+	var sharing []client.Object
+	for _, key := range keys {
+		list := newList()
+		if err := c.List(ctx, list, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: key}); err != nil {
+			return nil, err
+		}
+		items, err := apimeta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if other, ok := item.(client.Object); ok && other.GetUID() != config.GetUID() {
+				sharing = append(sharing, other)
+			}
+		}
+	}
+	return sharing, nil
+}
+
+// bootConfigServerName returns the name of the Server that the ServerBootConfiguration owning a boot
+// config refers to, or an empty string if there is none.
+func bootConfigServerName(ctx context.Context, c client.Client, config client.Object) (string, error) {
+	owner := metav1.GetControllerOf(config)
+	if owner == nil || owner.APIVersion != metalv1alpha1.GroupVersion.String() || owner.Kind != "ServerBootConfiguration" {
+		return "", nil
+	}
+	sbc := &metalv1alpha1.ServerBootConfiguration{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: config.GetNamespace(), Name: owner.Name}, sbc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get ServerBootConfiguration %s: %w", owner.Name, err)
+	}
+	return sbc.Spec.ServerRef.Name, nil
+}
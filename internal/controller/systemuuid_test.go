@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestIPXEBootConfigSystemUUIDCondition(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := bootv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := metalv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	const sharedUUID = "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91"
+	sbc := func(name, serverName string) *metalv1alpha1.ServerBootConfiguration {
+		return &metalv1alpha1.ServerBootConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       metalv1alpha1.ServerBootConfigurationSpec{ServerRef: corev1.LocalObjectReference{Name: serverName}},
+		}
+	}
+	config := func(name, owner, uuid string) *bootv1alpha1.IPXEBootConfig {
+		return &bootv1alpha1.IPXEBootConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID(name),
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: metalv1alpha1.GroupVersion.String(),
+					Kind:       "ServerBootConfiguration",
+					Name:       owner,
+					Controller: ptr.To(true),
+				}},
+			},
+			Spec: bootv1alpha1.IPXEBootConfigSpec{SystemUUID: uuid},
+		}
+	}
+
+	objects := []client.Object{
+		sbc("workload-a", "server-a"),
+		sbc("maintenance-a", "server-a"),
+		sbc("workload-b", "server-b"),
+		sbc("workload-c", "server-c"),
+		config("workload-a", "workload-a", sharedUUID),
+		config("maintenance-a", "maintenance-a", sharedUUID),
+		config("workload-b", "workload-b", "5F2C8A1E-9B3D-4C7F-A6E1-0D4B8C2E7F91"),
+		config("workload-c", "workload-c", systemuuid.Swapped(sharedUUID)),
+		config("unique", "workload-a", "8e1b4d7a-2c5f-4a9e-b3d6-7f0a1c4e8b25"),
+		config("placeholder", "workload-a", "03000200-0400-0500-0006-000700080009"),
+		config("invalid", "workload-a", "not-a-uuid"),
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}).
+		WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+			return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+		}).
+		Build()
+	r := &IPXEBootConfigReconciler{Client: c, Scheme: scheme}
+
+	for name, wantReason := range map[string]string{
+		"workload-a":    bootv1alpha1.DuplicateSystemUUIDReason,
+		"maintenance-a": bootv1alpha1.DuplicateSystemUUIDReason,
+		"workload-b":    bootv1alpha1.DuplicateSystemUUIDReason,
+		"workload-c":    bootv1alpha1.DuplicateSystemUUIDReason,
+		"unique":        "",
+		"placeholder":   bootv1alpha1.PlaceholderSystemUUIDReason,
+		"invalid":       bootv1alpha1.InvalidSystemUUIDReason,
+	} {
+		stored := &bootv1alpha1.IPXEBootConfig{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: name}, stored); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.ensureSystemUUIDCondition(ctx, stored); err != nil {
+			t.Fatalf("ensureSystemUUIDCondition(%s) error = %v", name, err)
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(stored), stored); err != nil {
+			t.Fatal(err)
+		}
+		condition := apimeta.FindStatusCondition(stored.Status.Conditions, bootv1alpha1.SystemUUIDAmbiguousCondition)
+		switch {
+		case wantReason == "" && condition != nil:
+			t.Errorf("%s: unexpected condition %+v", name, condition)
+		case wantReason != "" && (condition == nil || condition.Reason != wantReason):
+			t.Errorf("%s: condition = %+v, want reason %s", name, condition, wantReason)
+		}
+	}
+
+	// Once the configs of the other Servers are gone, the condition is removed again.
+	for _, name := range []string{"workload-b", "workload-c"} {
+		if err := c.Delete(ctx, config(name, name, "")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stored := &bootv1alpha1.IPXEBootConfig{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "default", Name: "workload-a"}, stored); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ensureSystemUUIDCondition(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+	if apimeta.FindStatusCondition(stored.Status.Conditions, bootv1alpha1.SystemUUIDAmbiguousCondition) != nil {
+		t.Errorf("expected the %s condition to be removed", bootv1alpha1.SystemUUIDAmbiguousCondition)
+	}
+
+	requests := r.enqueueIPXEBootConfigsSharingSystemUUID(ctx, stored)
+	if len(requests) != 1 || requests[0].Name != "maintenance-a" {
+		t.Errorf("enqueueIPXEBootConfigsSharingSystemUUID() = %v, want maintenance-a", requests)
+	}
+}
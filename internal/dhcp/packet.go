@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dhcp implements encoding and decoding of the DHCPv4 messages (RFC 2131, 2132)
+// needed to answer PXE and UEFI HTTP boot clients as a ProxyDHCP server.
+package dhcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+)
+
+// Opcodes of the BOOTP header.
+const (
+	OpRequest byte = 1
+	OpReply   byte = 2
+)
+
+// MessageType is the value of the DHCP message type option.
+type MessageType byte
+
+const (
+	MessageTypeDiscover MessageType = 1
+	MessageTypeOffer    MessageType = 2
+	MessageTypeRequest  MessageType = 3
+	MessageTypeAck      MessageType = 5
+	MessageTypeInform   MessageType = 8
+)
+
+// Option codes used by PXE and UEFI HTTP boot.
+const (
+	OptionPad                   byte = 0
+	OptionVendorSpecific        byte = 43
+	OptionMessageType           byte = 53
+	OptionServerIdentifier      byte = 54
+	OptionParameterRequestList  byte = 55
+	OptionVendorClassIdentifier byte = 60
+	OptionBootfileName          byte = 67
+	OptionUserClass             byte = 77
+	OptionClientArchitecture    byte = 93
+	OptionClientMachineID       byte = 97
+	OptionEnd                   byte = 255
+)
+
+// FlagBroadcast asks servers to broadcast replies because the client cannot receive
+// unicast datagrams before it is configured.
+const FlagBroadcast uint16 = 0x8000
+
+const (
+	headerLength = 236
+	fileLength   = 128
+)
+
+var magicCookie = []byte{99, 130, 83, 99}
+
+// Packet is a DHCPv4 message.
+type Packet struct {
+	Op     byte
+	HType  byte
+	HLen   byte
+	Hops   byte
+	XID    uint32
+	Secs   uint16
+	Flags  uint16
+	CIAddr net.IP
+	YIAddr net.IP
+	SIAddr net.IP
+	GIAddr net.IP
+	CHAddr net.HardwareAddr
+	// File is the boot file name of the fixed header.
+	File string
+	// Options maps option codes to their values. Options split across several
+	// instances (RFC 3396) are concatenated.
+	Options map[byte][]byte
+}
+
+// Parse decodes a DHCPv4 message.
+func Parse(data []byte) (*Packet, error) {
+	if len(data) < headerLength+len(magicCookie) {
+		return nil, errors.New("packet too short")
+	}
+	if !bytes.Equal(data[headerLength:headerLength+len(magicCookie)], magicCookie) {
+		return nil, errors.New("missing DHCP magic cookie")
+	}
+	p := &Packet{
+		Op:      data[0],
+		HType:   data[1],
+		HLen:    data[2],
+		Hops:    data[3],
+		XID:     binary.BigEndian.Uint32(data[4:8]),
+		Secs:    binary.BigEndian.Uint16(data[8:10]),
+		Flags:   binary.BigEndian.Uint16(data[10:12]),
+		CIAddr:  net.IP(slices.Clone(data[12:16])),
+		YIAddr:  net.IP(slices.Clone(data[16:20])),
+		SIAddr:  net.IP(slices.Clone(data[20:24])),
+		GIAddr:  net.IP(slices.Clone(data[24:28])),
+		File:    string(bytes.TrimRight(data[108:108+fileLength], "\x00")),
+		Options: make(map[byte][]byte),
+	}
+	if p.HLen > 16 {
+		return nil, fmt.Errorf("invalid hardware address length %d", p.HLen)
+	}
+	p.CHAddr = net.HardwareAddr(slices.Clone(data[28 : 28+int(p.HLen)]))
+
+	options := data[headerLength+len(magicCookie):]
+	for len(options) > 0 {
+		code := options[0]
+		if code == OptionEnd {
+			break
+		}
+		if code == OptionPad {
+			options = options[1:]
+			continue
+		}
+		if len(options) < 2 || len(options) < 2+int(options[1]) {
+			return nil, fmt.Errorf("truncated option %d", code)
+		}
+		length := int(options[1])
+		p.Options[code] = append(p.Options[code], options[2:2+length]...)
+		options = options[2+length:]
+	}
+	return p, nil
+}
+
+// MessageType returns the value of the message type option, or 0 if it is missing.
+func (p *Packet) MessageType() MessageType {
+	if v := p.Options[OptionMessageType]; len(v) == 1 {
+		return MessageType(v[0])
+	}
+	return 0
+}
+
+// Marshal encodes the message. Options are written in ascending order of their codes,
+// split into several instances if longer than 255 bytes.
+func (p *Packet) Marshal() []byte {
+	data := make([]byte, headerLength, 576)
+	data[0], data[1], data[2], data[3] = p.Op, p.HType, p.HLen, p.Hops
+	binary.BigEndian.PutUint32(data[4:8], p.XID)
+	binary.BigEndian.PutUint16(data[8:10], p.Secs)
+	binary.BigEndian.PutUint16(data[10:12], p.Flags)
+	for i, ip := range []net.IP{p.CIAddr, p.YIAddr, p.SIAddr, p.GIAddr} {
+		if ip4 := ip.To4(); ip4 != nil {
+			copy(data[12+4*i:16+4*i], ip4)
+		}
+	}
+	copy(data[28:44], p.CHAddr)
+	copy(data[108:108+fileLength-1], p.File)
+	data = append(data, magicCookie...)
+
+	codes := make([]byte, 0, len(p.Options))
+	for code := range p.Options {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	for _, code := range codes {
+		value := p.Options[code]
+		for {
+			chunk := value[:min(len(value), 255)]
+			data = append(data, code, byte(len(chunk)))
+			data = append(data, chunk...)
+			value = value[len(chunk):]
+			if len(value) == 0 {
+				break
+			}
+		}
+	}
+	data = append(data, OptionEnd)
+	// Pad to the minimum BOOTP message size some clients insist on.
+	for len(data) < 300 {
+		data = append(data, OptionPad)
+	}
+	return data
+}
+
+// NewReply returns a reply to p of the given message type with the transaction
+// details of p copied.
+func NewReply(p *Packet, messageType MessageType) *Packet {
+	return &Packet{
+		Op:      OpReply,
+		HType:   p.HType,
+		HLen:    p.HLen,
+		XID:     p.XID,
+		Flags:   p.Flags,
+		CIAddr:  p.CIAddr,
+		GIAddr:  p.GIAddr,
+		CHAddr:  p.CHAddr,
+		Options: map[byte][]byte{OptionMessageType: {byte(messageType)}},
+	}
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dhcp
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+)
+
+func discover() *Packet {
+	return &Packet{
+		Op:     OpRequest,
+		HType:  1,
+		HLen:   6,
+		XID:    0xdeadbeef,
+		Flags:  FlagBroadcast,
+		CHAddr: net.HardwareAddr{0x52, 0x54, 0x00, 0x12, 0x34, 0x56},
+		Options: map[byte][]byte{
+			OptionMessageType:           {byte(MessageTypeDiscover)},
+			OptionVendorClassIdentifier: []byte("PXEClient:Arch:00007:UNDI:003016"),
+			OptionClientArchitecture:    {0, 7},
+		},
+	}
+}
+
+func TestMarshalAndParse(t *testing.T) {
+	in := discover()
+	in.SIAddr = net.IPv4(10, 0, 0, 1)
+	in.File = "amd64/ipxe.efi"
+
+	out, err := Parse(in.Marshal())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if out.XID != in.XID || out.Flags != in.Flags || out.CHAddr.String() != in.CHAddr.String() {
+		t.Errorf("Parse() header = %+v, want %+v", out, in)
+	}
+	if !out.SIAddr.Equal(in.SIAddr) || out.File != in.File {
+		t.Errorf("Parse() siaddr, file = %s, %q; want %s, %q", out.SIAddr, out.File, in.SIAddr, in.File)
+	}
+	if out.MessageType() != MessageTypeDiscover {
+		t.Errorf("MessageType() = %d, want %d", out.MessageType(), MessageTypeDiscover)
+	}
+	if out.VendorClass() != VendorClassPXEClient {
+		t.Errorf("VendorClass() = %q, want %q", out.VendorClass(), VendorClassPXEClient)
+	}
+	if arch, ok := out.ClientArchitecture(); !ok || arch != ArchX64UEFI {
+		t.Errorf("ClientArchitecture() = %d, %v; want %d, true", arch, ok, ArchX64UEFI)
+	}
+	if out.IsIPXE() {
+		t.Error("IsIPXE() = true for a PXE ROM request")
+	}
+}
+
+func TestMarshalSplitsLongOptions(t *testing.T) {
+	in := discover()
+	bootfile := "http://boot.example.com/" + strings.Repeat("a", 300) + ".efi"
+	in.Options[OptionBootfileName] = []byte(bootfile)
+
+	out, err := Parse(in.Marshal())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := string(out.Options[OptionBootfileName]); got != bootfile {
+		t.Errorf("bootfile = %q, want %q", got, bootfile)
+	}
+}
+
+func TestParseRejectsMalformedPackets(t *testing.T) {
+	valid := discover().Marshal()
+
+	noCookie := bytes.Clone(valid)
+	noCookie[headerLength] = 0
+	truncated := bytes.Clone(valid[:headerLength+len(magicCookie)])
+	truncated = append(truncated, OptionBootfileName, 10, 'x')
+
+	for name, data := range map[string][]byte{
+		"too short":        valid[:100],
+		"no magic cookie":  noCookie,
+		"truncated option": truncated,
+	} {
+		if _, err := Parse(data); err == nil {
+			t.Errorf("%s: expected Parse() to fail", name)
+		}
+	}
+}
+
+func TestIsIPXE(t *testing.T) {
+	for _, userClass := range []string{"iPXE", "\x04iPXE"} {
+		p := discover()
+		p.Options[OptionUserClass] = []byte(userClass)
+		if !p.IsIPXE() {
+			t.Errorf("IsIPXE() = false for user class %q", userClass)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dhcp
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Client system architectures of option 93 (RFC 4578 and the IANA registry).
+const (
+	ArchX86BIOS       uint16 = 0
+	ArchX64UEFI       uint16 = 7
+	ArchEFIBC         uint16 = 9 // x86-64 UEFI, sent by many firmwares instead of 7
+	ArchARM64UEFI     uint16 = 11
+	ArchX64UEFIHTTP   uint16 = 16
+	ArchARM64UEFIHTTP uint16 = 19
+)
+
+// Vendor classes of PXE and UEFI HTTP boot clients.
+const (
+	VendorClassPXEClient  = "PXEClient"
+	VendorClassHTTPClient = "HTTPClient"
+)
+
+// pxeDiscoveryControl is the PXE vendor sub-option controlling boot server discovery.
+const pxeDiscoveryControl byte = 6
+
+// VendorClass returns VendorClassPXEClient or VendorClassHTTPClient if the packet comes
+// from a PXE or UEFI HTTP boot client, or an empty string otherwise.
+func (p *Packet) VendorClass() string {
+	class := p.Options[OptionVendorClassIdentifier]
+	for _, prefix := range []string{VendorClassPXEClient, VendorClassHTTPClient} {
+		if bytes.HasPrefix(class, []byte(prefix)) {
+			return prefix
+		}
+	}
+	return ""
+}
+
+// ClientArchitecture returns the first architecture listed in option 93.
+func (p *Packet) ClientArchitecture() (uint16, bool) {
+	v := p.Options[OptionClientArchitecture]
+	if len(v) < 2 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(v), true
+}
+
+// IsIPXE reports whether the packet was sent by iPXE, which identifies itself with
+// the user class "iPXE".
+func (p *Packet) IsIPXE() bool {
+	userClass := p.Options[OptionUserClass]
+	// The user class is either the plain string or, per RFC 3004, length-prefixed.
+	return bytes.Equal(userClass, []byte("iPXE")) || bytes.Equal(userClass, []byte("\x04iPXE"))
+}
+
+// PXEVendorOptions returns option 43 telling PXE clients to skip boot server discovery
+// and boot the file named in the reply directly.
+func PXEVendorOptions() []byte {
+	return []byte{pxeDiscoveryControl, 1, 0x08, OptionEnd}
+}
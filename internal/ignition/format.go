@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ignition
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	ignitionconfig "github.com/coreos/ignition/v2/config"
+	"sigs.k8s.io/yaml"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+)
+
+// plainIgnition is an alias of the ignition format.
+const plainIgnition = "plain-ignition"
+
+// Format describes how ignition data of a format is validated and rendered into the document that
+// is served to the booted OS.
+type Format struct {
+	// ContentType is the media type of the rendered document.
+	ContentType string
+	// CloudInit is set for formats that are rendered into cloud-init user-data, which is also served
+	// by the NoCloud data source of the boot-server.
+	CloudInit bool
+	// Render renders the data into the document that is served.
+	Render func(data []byte) ([]byte, error)
+	// Validate checks the data and returns the warnings reported for it.
+	Validate func(data []byte) ([]string, error)
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]Format{}
+)
+
+// RegisterFormat registers the renderer of a format, replacing the one registered before.
+func RegisterFormat(name string, format Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[name] = format
+}
+
+// LookupFormat returns the renderer of the format with the given name, as found in the format key
+// of an ignition Secret. The empty name selects the ignition format.
+func LookupFormat(name string) (Format, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = bootv1alpha1.IgnitionFormat
+	}
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	format, ok := formats[name]
+	return format, ok
+}
+
+func init() {
+	ignitionFormat := Format{
+		ContentType: "application/json",
+		Render:      func(data []byte) ([]byte, error) { return data, nil },
+		Validate: func(data []byte) ([]string, error) {
+			_, rpt, err := ignitionconfig.Parse(data)
+			return reportResult(rpt, err)
+		},
+	}
+	RegisterFormat(bootv1alpha1.IgnitionFormat, ignitionFormat)
+	RegisterFormat(plainIgnition, ignitionFormat)
+	RegisterFormat(bootv1alpha1.FCOSFormat, butaneFormat(""))
+	RegisterFormat(bootv1alpha1.FlatcarFormat, butaneFormat("flatcar"))
+	RegisterFormat(bootv1alpha1.CloudInitFormat, Format{
+		ContentType: "text/plain; charset=utf-8",
+		CloudInit:   true,
+		Render:      func(data []byte) ([]byte, error) { return data, validateUserData(data) },
+		Validate:    func(data []byte) ([]string, error) { return nil, validateUserData(data) },
+	})
+	RegisterFormat(bootv1alpha1.AutoinstallFormat, Format{
+		ContentType: "text/plain; charset=utf-8",
+		CloudInit:   true,
+		Render:      renderAutoinstall,
+		Validate: func(data []byte) ([]string, error) {
+			_, err := renderAutoinstall(data)
+			return nil, err
+		},
+	})
+}
+
+// butaneFormat returns the format of Butane configs, which are translated to Ignition configs. If
+// variant is set, the Butane configs must be of that variant.
+func butaneFormat(variant string) Format {
+	checkVariant := func(data []byte) error {
+		if variant == "" {
+			return nil
+		}
+		fields := struct {
+			Variant string `json:"variant"`
+		}{}
+		if err := yaml.Unmarshal(data, &fields); err != nil {
+			return fmt.Errorf("failed to parse Butane config: %w", err)
+		}
+		if fields.Variant != variant {
+			return fmt.Errorf("butane variant %q does not match the format %s", fields.Variant, variant)
+		}
+		return nil
+	}
+	return Format{
+		ContentType: "application/json",
+		Render: func(data []byte) ([]byte, error) {
+			if err := checkVariant(data); err != nil {
+				return nil, err
+			}
+			jsonData, _, err := TranslateButane(data)
+			return jsonData, err
+		},
+		Validate: func(data []byte) ([]string, error) {
+			if err := checkVariant(data); err != nil {
+				return nil, err
+			}
+			_, rpt, err := TranslateButane(data)
+			return reportResult(rpt, err)
+		},
+	}
+}
+
+// userDataHeaders are the prefixes by which cloud-init recognizes the type of user-data.
+var userDataHeaders = []string{
+	"#cloud-config",
+	"#!",
+	"#include",
+	"#cloud-boothook",
+	"#part-handler",
+	"## template: jinja",
+	"Content-Type: multipart/",
+}
+
+// validateUserData checks that data is cloud-init user-data of a type recognized by cloud-init, and
+// that cloud-config user-data is a YAML mapping.
+func validateUserData(data []byte) error {
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		// cloud-init decompresses gzipped user-data before looking at its header.
+		return nil
+	}
+	text := string(data)
+	if strings.HasPrefix(text, "#cloud-config-archive") {
+		return nil
+	}
+	if strings.HasPrefix(text, "#cloud-config") {
+		var config map[string]any
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("cloud-config is not a YAML mapping: %w", err)
+		}
+		return nil
+	}
+	for _, header := range userDataHeaders {
+		if strings.HasPrefix(text, header) {
+			return nil
+		}
+	}
+	return fmt.Errorf("user-data must start with one of %s", strings.Join(userDataHeaders, ", "))
+}
+
+// renderAutoinstall renders an Ubuntu autoinstall config into cloud-config user-data. The data may
+// either be the autoinstall config itself, or a cloud-config with an autoinstall section, which is
+// served as is.
+func renderAutoinstall(data []byte) ([]byte, error) {
+	var config map[string]any
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("autoinstall config is not a YAML mapping: %w", err)
+	}
+	autoinstall, wrapped := config["autoinstall"]
+	if !wrapped {
+		autoinstall = config
+	}
+	section, ok := autoinstall.(map[string]any)
+	if !ok {
+		return nil, errors.New("autoinstall section is not a YAML mapping")
+	}
+	if version, ok := section["version"].(float64); !ok || version != 1 {
+		return nil, errors.New("autoinstall config must have version 1")
+	}
+	if wrapped {
+		if !strings.HasPrefix(string(data), "#cloud-config") {
+			return nil, errors.New("autoinstall section must be part of a #cloud-config")
+		}
+		return data, nil
+	}
+
+	userData, err := yaml.Marshal(map[string]any{"autoinstall": section})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render autoinstall user-data: %w", err)
+	}
+	return append([]byte("#cloud-config\n"), userData...), nil
+}
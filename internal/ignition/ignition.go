@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ignition translates and validates the ignition data referenced by boot configs, i.e. the
+// provisioning data served to the booted OS in one of several formats.
+package ignition
+
+import (
+	"fmt"
+	"strings"
+
+	butaneconfig "github.com/coreos/butane/config"
+	butanecommon "github.com/coreos/butane/config/common"
+	"github.com/coreos/vcontext/report"
+)
+
+// TranslateButane translates a Butane config into an Ignition config in JSON.
+func TranslateButane(data []byte) ([]byte, report.Report, error) {
+	translateOptions := butanecommon.TranslateBytesOptions{
+		Raw:    true,
+		Pretty: false,
+		TranslateOptions: butanecommon.TranslateOptions{
+			NoResourceAutoCompression: true,
+		},
+	}
+	return butaneconfig.TranslateBytes(data, translateOptions)
+}
+
+// Validate checks that ignition data can be rendered according to its format, as found in the
+// format key of the ignition Secret. It returns the warnings reported for the data.
+func Validate(data []byte, format string) ([]string, error) {
+	f, ok := LookupFormat(format)
+	if !ok {
+		return []string{fmt.Sprintf("unknown format %q, the data is served as is", strings.TrimSpace(format))}, nil
+	}
+	return f.Validate(data)
+}
+
+// reportResult returns the warnings of a report, and err extended by the errors of the report.
+func reportResult(rpt report.Report, err error) ([]string, error) {
+	var errs, warnings []string
+	for _, entry := range rpt.Entries {
+		switch entry.Kind {
+		case report.Error:
+			errs = append(errs, entry.String())
+		case report.Warn:
+			warnings = append(warnings, entry.String())
+		}
+	}
+	if err != nil {
+		if len(errs) > 0 {
+			return warnings, fmt.Errorf("%w: %s", err, strings.Join(errs, "; "))
+		}
+		return warnings, err
+	}
+	return warnings, nil
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ignition
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		format   string
+		wantErr  string
+		warnings int
+	}{
+		{name: "ignition", data: `{"ignition":{"version":"3.4.0"}}`},
+		{name: "ignition of an older spec", data: `{"ignition":{"version":"3.0.0"}}`},
+		{name: "not JSON", data: "ignition: {}", wantErr: "invalid character"},
+		{name: "unsupported version", data: `{"ignition":{"version":"9.9.9"}}`, wantErr: "unsupported config version"},
+		{
+			name:    "invalid field",
+			data:    `{"ignition":{"version":"3.4.0"},"storage":{"files":[{"path":"relative"}]}}`,
+			wantErr: "$.storage.files.0.path",
+		},
+		{name: "empty", wantErr: "empty"},
+		{
+			name:   "butane",
+			format: "fcos",
+			data:   "variant: fcos\nversion: 1.5.0\npasswd:\n  users:\n  - name: core\n",
+		},
+		{
+			name:     "butane with warnings",
+			format:   " fcos\n",
+			data:     "variant: fcos\nversion: 1.5.0\npasswd:\n  users:\n  - name: core\nunknown: true\n",
+			warnings: 1,
+		},
+		{name: "invalid butane", format: "fcos", data: "variant: fcos\nversion: 1.5.0\nstorage:\n  files:\n  - path: relative\n", wantErr: "$.storage.files.0.path"},
+		{name: "butane without variant", format: "fcos", data: "version: 1.5.0\n", wantErr: "variant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := Validate([]byte(tt.data), tt.format)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Validate() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Validate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("Validate() warnings = %v, want %d", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		data     string
+		want     string
+		wantErr  string
+		warnings int
+	}{
+		{name: "default format", data: `{"ignition":{"version":"3.4.0"}}`, want: `{"ignition":{"version":"3.4.0"}}`},
+		{name: "plain ignition", format: "plain-ignition", data: `{"ignition":{"version":"3.4.0"}}`, want: `{"ignition":{"version":"3.4.0"}}`},
+		{
+			name:   "flatcar",
+			format: "flatcar",
+			data:   "variant: flatcar\nversion: 1.0.0\n",
+			want:   `{"ignition":{"version":"3.3.0"}}`,
+		},
+		{name: "flatcar of another variant", format: "flatcar", data: "variant: fcos\nversion: 1.5.0\n", wantErr: "does not match"},
+		{name: "cloud-config", format: "cloud-init", data: "#cloud-config\nhostname: a\n", want: "#cloud-config\nhostname: a\n"},
+		{name: "user-data script", format: "cloud-init", data: "#!/bin/sh\necho hi\n", want: "#!/bin/sh\necho hi\n"},
+		{name: "invalid cloud-config", format: "cloud-init", data: "#cloud-config\n- a\n", wantErr: "not a YAML mapping"},
+		{name: "user-data without header", format: "cloud-init", data: "hostname: a\n", wantErr: "must start with"},
+		{
+			name:   "autoinstall",
+			format: "autoinstall",
+			data:   "version: 1\nidentity:\n  hostname: a\n",
+			want:   "#cloud-config\nautoinstall:\n  identity:\n    hostname: a\n  version: 1\n",
+		},
+		{
+			name:   "autoinstall in cloud-config",
+			format: "autoinstall",
+			data:   "#cloud-config\nautoinstall:\n  version: 1\n",
+			want:   "#cloud-config\nautoinstall:\n  version: 1\n",
+		},
+		{name: "autoinstall without version", format: "autoinstall", data: "identity: {}\n", wantErr: "version 1"},
+		{name: "unknown format", format: "kickstart", data: "install\n", warnings: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := Validate([]byte(tt.data), tt.format)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Validate() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Validate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("Validate() warnings = %v, want %d", warnings, tt.warnings)
+			}
+
+			format, ok := LookupFormat(tt.format)
+			if !ok || tt.wantErr != "" {
+				return
+			}
+			rendered, err := format.Render([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if string(rendered) != tt.want {
+				t.Errorf("Render() = %q, want %q", rendered, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ignition
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	v3_0 "github.com/coreos/ignition/v2/config/v3_0"
+	v3_1 "github.com/coreos/ignition/v2/config/v3_1"
+	v3_2 "github.com/coreos/ignition/v2/config/v3_2"
+	v3_3 "github.com/coreos/ignition/v2/config/v3_3"
+	v3_4 "github.com/coreos/ignition/v2/config/v3_4"
+	v3_5 "github.com/coreos/ignition/v2/config/v3_5"
+	v3_6 "github.com/coreos/ignition/v2/config/v3_6"
+	v3_7_experimental "github.com/coreos/ignition/v2/config/v3_7_experimental"
+	"github.com/coreos/vcontext/report"
+)
+
+// Layer is the ignition data of a layer, as found in its Secret.
+type Layer struct {
+	// Name identifies the layer in errors, e.g. by the name of its Secret.
+	Name string
+	// Data is the ignition data, after rendering it as a template if the Secret enables it.
+	Data []byte
+	// Format is the format of the data.
+	Format string
+	// Replace discards the layers before this one.
+	Replace bool
+}
+
+// mergeVersion is an Ignition spec version that layers are merged at.
+type mergeVersion struct {
+	version string
+	merge   func(configs [][]byte) ([]byte, error)
+}
+
+// mergeVersions are the Ignition spec versions that layers are merged at, in ascending order.
+var mergeVersions = []mergeVersion{
+	{"3.0.0", mergeConfigs(v3_0.ParseCompatibleVersion, v3_0.Merge)},
+	{"3.1.0", mergeConfigs(v3_1.ParseCompatibleVersion, v3_1.Merge)},
+	{"3.2.0", mergeConfigs(v3_2.ParseCompatibleVersion, v3_2.Merge)},
+	{"3.3.0", mergeConfigs(v3_3.ParseCompatibleVersion, v3_3.Merge)},
+	{"3.4.0", mergeConfigs(v3_4.ParseCompatibleVersion, v3_4.Merge)},
+	{"3.5.0", mergeConfigs(v3_5.ParseCompatibleVersion, v3_5.Merge)},
+	{"3.6.0", mergeConfigs(v3_6.ParseCompatibleVersion, v3_6.Merge)},
+	{"3.7.0-experimental", mergeConfigs(v3_7_experimental.ParseCompatibleVersion, v3_7_experimental.Merge)},
+}
+
+// MergeLayers renders the layers according to their formats, and merges the resulting Ignition
+// configs in order with the merge semantics of Ignition, i.e. the fields of a layer take precedence
+// over the layers before it. The merged config has the highest spec version of the layers.
+func MergeLayers(layers []Layer) ([]byte, error) {
+	if len(layers) == 0 {
+		return nil, errors.New("no ignition layers to merge")
+	}
+	start := 0
+	for i, layer := range layers {
+		if layer.Replace {
+			start = i
+		}
+	}
+
+	configs := make([][]byte, 0, len(layers)-start)
+	version := 0
+	for _, layer := range layers[start:] {
+		format, ok := LookupFormat(layer.Format)
+		if !ok || format.CloudInit {
+			return nil, fmt.Errorf("layer %s: format %q cannot be merged into an Ignition config", layer.Name, layer.Format)
+		}
+		config, err := format.Render(layer.Data)
+		if err != nil {
+			return nil, fmt.Errorf("layer %s: %w", layer.Name, err)
+		}
+		v, err := specVersion(config)
+		if err != nil {
+			return nil, fmt.Errorf("layer %s: %w", layer.Name, err)
+		}
+		version = max(version, v)
+		configs = append(configs, config)
+	}
+	if len(configs) == 1 {
+		return configs[0], nil
+	}
+	return mergeVersions[version].merge(configs)
+}
+
+// specVersion returns the index of the spec version of an Ignition config in mergeVersions.
+func specVersion(config []byte) (int, error) {
+	fields := struct {
+		Ignition struct {
+			Version string `json:"version"`
+		} `json:"ignition"`
+	}{}
+	if err := json.Unmarshal(config, &fields); err != nil {
+		return 0, fmt.Errorf("failed to parse Ignition config: %w", err)
+	}
+	i := slices.IndexFunc(mergeVersions, func(v mergeVersion) bool { return v.version == fields.Ignition.Version })
+	if i < 0 {
+		return 0, fmt.Errorf("unsupported Ignition spec version %q", fields.Ignition.Version)
+	}
+	return i, nil
+}
+
+// mergeConfigs returns a function that parses Ignition configs as the config type of a spec version
+// and merges them in order.
+func mergeConfigs[C any](parse func([]byte) (C, report.Report, error), merge func(parent, child C) C) func([][]byte) ([]byte, error) {
+	return func(configs [][]byte) ([]byte, error) {
+		var merged C
+		for i, data := range configs {
+			config, _, err := parse(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse Ignition config %d: %w", i, err)
+			}
+			if i == 0 {
+				merged = config
+				continue
+			}
+			merged = merge(merged, config)
+		}
+		return json.Marshal(merged)
+	}
+}
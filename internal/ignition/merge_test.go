@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ignition
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMergeLayers(t *testing.T) {
+	const (
+		base = `{"ignition":{"version":"3.2.0"},"storage":{"files":[` +
+			`{"path":"/etc/motd","contents":{"source":"data:,base"}},` +
+			`{"path":"/etc/sysctl.d/50-hardening.conf","contents":{"source":"data:,kernel.kptr_restrict%3D2"}}]}}`
+		site = "variant: fcos\nversion: 1.5.0\nstorage:\n  files:\n  - path: /etc/motd\n    contents:\n      inline: site\n"
+		host = `{"ignition":{"version":"3.4.0"},"storage":{"files":[{"path":"/etc/hostname","contents":{"source":"data:,node-1"}}]}}`
+	)
+
+	type file struct {
+		Path     string `json:"path"`
+		Contents struct {
+			Source string `json:"source"`
+		} `json:"contents"`
+	}
+	parse := func(t *testing.T, data []byte) (string, map[string]string) {
+		t.Helper()
+		var config struct {
+			Ignition struct {
+				Version string `json:"version"`
+			} `json:"ignition"`
+			Storage struct {
+				Files []file `json:"files"`
+			} `json:"storage"`
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			t.Fatalf("failed to parse merged config %s: %v", data, err)
+		}
+		files := map[string]string{}
+		for _, f := range config.Storage.Files {
+			files[f.Path] = f.Contents.Source
+		}
+		return config.Ignition.Version, files
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		merged, err := MergeLayers([]Layer{
+			{Name: "base", Data: []byte(base)},
+			{Name: "site", Data: []byte(site), Format: "fcos"},
+			{Name: "host", Data: []byte(host)},
+		})
+		if err != nil {
+			t.Fatalf("MergeLayers() error = %v", err)
+		}
+		version, files := parse(t, merged)
+		if version != "3.4.0" {
+			t.Errorf("version = %s, want the highest version of the layers", version)
+		}
+		if len(files) != 3 || files["/etc/hostname"] != "data:,node-1" || !strings.Contains(files["/etc/motd"], "site") ||
+			files["/etc/sysctl.d/50-hardening.conf"] == "" {
+			t.Errorf("files = %v", files)
+		}
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		merged, err := MergeLayers([]Layer{
+			{Name: "base", Data: []byte(base)},
+			{Name: "site", Data: []byte(site), Format: "fcos", Replace: true},
+			{Name: "host", Data: []byte(host)},
+		})
+		if err != nil {
+			t.Fatalf("MergeLayers() error = %v", err)
+		}
+		if _, files := parse(t, merged); len(files) != 2 || files["/etc/sysctl.d/50-hardening.conf"] != "" {
+			t.Errorf("files = %v, want the base layer to be replaced", files)
+		}
+	})
+
+	for name, tt := range map[string]struct {
+		layers  []Layer
+		wantErr string
+	}{
+		"cloud-init":          {layers: []Layer{{Name: "base", Data: []byte(base)}, {Name: "user-data", Data: []byte("#cloud-config\n"), Format: "cloud-init"}}, wantErr: "layer user-data"},
+		"unsupported version": {layers: []Layer{{Name: "base", Data: []byte(base)}, {Name: "old", Data: []byte(`{"ignition":{"version":"2.3.0"}}`)}}, wantErr: "unsupported Ignition spec version"},
+		"invalid butane":      {layers: []Layer{{Name: "site", Data: []byte("variant: fcos\n"), Format: "fcos"}}, wantErr: "layer site"},
+		"no layers":           {wantErr: "no ignition layers"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := MergeLayers(tt.layers); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("MergeLayers() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ignition
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"text/template"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TemplateData is the data model that templated ignition data is rendered with.
+type TemplateData struct {
+	// SystemUUID is the SystemUUID of the boot config.
+	SystemUUID string
+	// Hostname is the name of the Server, or empty if the boot config is not owned by a
+	// ServerBootConfiguration of an existing Server.
+	Hostname string
+	// IPs are the IP addresses of the network interfaces of the Server, or the IP addresses of the
+	// boot config if there is no Server.
+	IPs []string
+	// MACs are the MAC addresses of the network interfaces of the Server, or the MAC addresses of the
+	// boot config if there is no Server.
+	MACs []string
+	// Interfaces are the network interfaces of the Server.
+	Interfaces []TemplateInterface
+	// Labels are the labels of the ServerBootConfiguration owning the boot config.
+	Labels map[string]string
+	// Annotations are the annotations of the ServerBootConfiguration owning the boot config.
+	Annotations map[string]string
+}
+
+// TemplateInterface is a network interface of the Server in the TemplateData.
+type TemplateInterface struct {
+	Name       string
+	MACAddress string
+	IPs        []string
+}
+
+// templateFuncs are the functions available to templated ignition data in addition to the builtin
+// functions of text/template.
+var templateFuncs = template.FuncMap{
+	"join":  func(sep string, values []string) string { return strings.Join(values, sep) },
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"toJSON": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"default": func(fallback, value string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// IsTemplate reports whether the data of an ignition Secret is to be rendered as a Go template.
+func IsTemplate(secret *corev1.Secret) bool {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(string(secret.Data[bootv1alpha1.DefaultTemplateKey])))
+	return err == nil && enabled
+}
+
+// RenderTemplate renders data as a Go template with the given TemplateData. Referencing a missing
+// label or annotation is an error.
+func RenderTemplate(data []byte, values *TemplateData) ([]byte, error) {
+	tmpl, err := template.New("ignition").Funcs(templateFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ignition template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return nil, fmt.Errorf("failed to execute ignition template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadTemplateData returns the TemplateData of an IPXEBootConfig or HTTPBootConfig. The Server is
+// resolved through the ServerBootConfiguration controlling the boot config. Boot configs without
+// one get the TemplateData of their own spec.
+func LoadTemplateData(ctx context.Context, c client.Client, config client.Object) (*TemplateData, error) {
+	values := &TemplateData{}
+	var identifiers []string
+	switch resource := config.(type) {
+	case *bootv1alpha1.IPXEBootConfig:
+		values.SystemUUID = resource.Spec.SystemUUID
+		identifiers = append(append(identifiers, resource.Spec.SystemIPs...), resource.Spec.SystemMACs...)
+	case *bootv1alpha1.HTTPBootConfig:
+		values.SystemUUID = resource.Spec.SystemUUID
+		identifiers = resource.Spec.NetworkIdentifiers
+	default:
+		return nil, fmt.Errorf("unsupported boot config type %T", config)
+	}
+
+	sbc, err := owningServerBootConfiguration(ctx, c, config)
+	if err != nil {
+		return nil, err
+	}
+	var server *metalv1alpha1.Server
+	if sbc != nil {
+		values.Labels = sbc.Labels
+		values.Annotations = sbc.Annotations
+		server = &metalv1alpha1.Server{}
+		if err := c.Get(ctx, client.ObjectKey{Name: sbc.Spec.ServerRef.Name}, server); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get Server %s: %w", sbc.Spec.ServerRef.Name, err)
+			}
+			server = nil
+		}
+	}
+
+	if server == nil {
+		for _, identifier := range identifiers {
+			if _, err := netip.ParseAddr(identifier); err == nil {
+				values.IPs = append(values.IPs, identifier)
+			} else if _, err := net.ParseMAC(identifier); err == nil {
+				values.MACs = append(values.MACs, identifier)
+			}
+		}
+		return values, nil
+	}
+
+	values.Hostname = server.Name
+	if values.SystemUUID == "" {
+		values.SystemUUID = server.Spec.SystemUUID
+	}
+	for _, nic := range server.Status.NetworkInterfaces {
+		iface := TemplateInterface{Name: nic.Name, MACAddress: nic.MACAddress}
+		for _, ip := range nic.IPs {
+			iface.IPs = append(iface.IPs, ip.String())
+		}
+		if len(iface.IPs) == 0 && nic.IP != nil {
+			iface.IPs = append(iface.IPs, nic.IP.String())
+		}
+		values.Interfaces = append(values.Interfaces, iface)
+		values.MACs = append(values.MACs, iface.MACAddress)
+		values.IPs = append(values.IPs, iface.IPs...)
+	}
+	return values, nil
+}
+
+// owningServerBootConfiguration returns the ServerBootConfiguration controlling a boot config, or
+// nil if there is none.
+func owningServerBootConfiguration(ctx context.Context, c client.Client, config client.Object) (*metalv1alpha1.ServerBootConfiguration, error) {
+	owner := metav1.GetControllerOf(config)
+	if owner == nil || owner.APIVersion != metalv1alpha1.GroupVersion.String() || owner.Kind != "ServerBootConfiguration" {
+		return nil, nil
+	}
+	sbc := &metalv1alpha1.ServerBootConfiguration{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: config.GetNamespace(), Name: owner.Name}, sbc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ServerBootConfiguration %s: %w", owner.Name, err)
+	}
+	return sbc, nil
+}
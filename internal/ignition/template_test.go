@@ -0,0 +1,141 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ignition
+
+import (
+	"context"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	values := &TemplateData{
+		SystemUUID:  "4c4c4544-0042-3010-8052-b4c04f384d32",
+		Hostname:    "server-1",
+		IPs:         []string{"10.0.0.10", "2001:db8::10"},
+		Labels:      map[string]string{"rack": "r1"},
+		Annotations: map[string]string{},
+	}
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr string
+	}{
+		{name: "plain", data: `{"ignition":{"version":"3.4.0"}}`, want: `{"ignition":{"version":"3.4.0"}}`},
+		{name: "fields", data: "{{.Hostname}} {{.SystemUUID}} {{.Labels.rack}}", want: "server-1 4c4c4544-0042-3010-8052-b4c04f384d32 r1"},
+		{name: "functions", data: `{{join "," .IPs}} {{upper .Hostname}} {{toJSON .Hostname}}`, want: `10.0.0.10,2001:db8::10 SERVER-1 "server-1"`},
+		{name: "default", data: `{{default "none" (index .Annotations "missing")}}`, want: "none"},
+		{name: "missing label", data: "{{.Labels.missing}}", wantErr: "map has no entry"},
+		{name: "syntax error", data: "{{.Hostname", wantErr: "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate([]byte(tt.data), values)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RenderTemplate() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderTemplate() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("RenderTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTemplateData(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := bootv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := metalv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	server := &metalv1alpha1.Server{
+		ObjectMeta: metav1.ObjectMeta{Name: "server-1"},
+		Spec:       metalv1alpha1.ServerSpec{SystemUUID: "4c4c4544-0042-3010-8052-b4c04f384d32"},
+		Status: metalv1alpha1.ServerStatus{NetworkInterfaces: []metalv1alpha1.NetworkInterface{{
+			Name:       "eth0",
+			MACAddress: "52:54:00:12:34:56",
+			IPs:        []metalv1alpha1.IP{{Addr: netip.MustParseAddr("10.0.0.10")}},
+		}}},
+	}
+	sbc := &metalv1alpha1.ServerBootConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "sbc",
+			Namespace:   "default",
+			Labels:      map[string]string{"rack": "r1"},
+			Annotations: map[string]string{"role": "worker"},
+		},
+		Spec: metalv1alpha1.ServerBootConfigurationSpec{ServerRef: corev1.LocalObjectReference{Name: server.Name}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(server, sbc).Build()
+
+	owned := &bootv1alpha1.IPXEBootConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "owned",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: metalv1alpha1.GroupVersion.String(),
+				Kind:       "ServerBootConfiguration",
+				Name:       sbc.Name,
+				Controller: ptr.To(true),
+			}},
+		},
+		Spec: bootv1alpha1.IPXEBootConfigSpec{SystemUUID: server.Spec.SystemUUID, SystemIPs: []string{"192.0.2.1"}},
+	}
+	got, err := LoadTemplateData(ctx, c, owned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &TemplateData{
+		SystemUUID:  server.Spec.SystemUUID,
+		Hostname:    "server-1",
+		IPs:         []string{"10.0.0.10"},
+		MACs:        []string{"52:54:00:12:34:56"},
+		Interfaces:  []TemplateInterface{{Name: "eth0", MACAddress: "52:54:00:12:34:56", IPs: []string{"10.0.0.10"}}},
+		Labels:      sbc.Labels,
+		Annotations: sbc.Annotations,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadTemplateData() = %+v, want %+v", got, want)
+	}
+
+	standalone := &bootv1alpha1.HTTPBootConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "standalone", Namespace: "default"},
+		Spec: bootv1alpha1.HTTPBootConfigSpec{
+			SystemUUID:         "8e1b4d7a-2c5f-4a9e-b3d6-7f0a1c4e8b25",
+			NetworkIdentifiers: []string{"10.0.0.20", "52:54:00:ab:cd:ef"},
+		},
+	}
+	got, err = LoadTemplateData(ctx, c, standalone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &TemplateData{
+		SystemUUID: standalone.Spec.SystemUUID,
+		IPs:        []string{"10.0.0.20"},
+		MACs:       []string{"52:54:00:ab:cd:ef"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadTemplateData() = %+v, want %+v", got, want)
+	}
+}
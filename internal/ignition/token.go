@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ignition
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// tokenBytes is the number of random bytes in an ignition token.
+const tokenBytes = 32
+
+// NewToken mints a random ignition token for the given generation of a boot config, which expires
+// after ttl.
+func NewToken(generation int64, ttl time.Duration, now time.Time) (*bootv1alpha1.IgnitionToken, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate ignition token: %w", err)
+	}
+	return &bootv1alpha1.IgnitionToken{
+		Token:              base64.RawURLEncoding.EncodeToString(b),
+		ExpirationTime:     metav1.NewTime(now.Add(ttl).Truncate(time.Second)),
+		ObservedGeneration: generation,
+	}, nil
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ipxe
+
+import (
+	"slices"
+	"strings"
+)
+
+// KernelCommandLine returns the kernel command line of the booted OS: the default kernel arguments
+// for the boot, merged with KernelArgs as described by MergeKernelArgs. It refers to the iPXE
+// variables ipxe-svc, squashfs-url and uuid.
+func (d TemplateData) KernelCommandLine() string {
+	return strings.Join(MergeKernelArgs(d.defaultKernelArgs(), d.KernelArgs), " ")
+}
+
+// defaultKernelArgs returns the kernel arguments that the OS is booted with by default.
+func (d TemplateData) defaultKernelArgs() []string {
+	args := []string{"initrd=initrd"}
+	if d.SquashfsURL != "" {
+		args = append(args, "gl.ovl=/:tmpfs", "gl.url=${squashfs-url}", "gl.live=1")
+	}
+
+	id := "${uuid}"
+	if d.MACAddress != "" {
+		id = "mac/" + d.MACAddress
+	}
+	ignitionURL := "${ipxe-svc}/ignition/" + id
+	if d.IgnitionToken != "" {
+		ignitionURL += "?token=" + d.IgnitionToken
+	}
+	args = append(args, "ip=any", "ignition.firstboot=1", "ignition.config.url="+ignitionURL, "ignition.platform.id=metal")
+
+	if d.NoCloud {
+		seedURL := "${ipxe-svc}/nocloud/" + id + "/"
+		if d.IgnitionToken != "" {
+			seedURL += d.IgnitionToken + "/"
+		}
+		args = append(args, "ds=nocloud;s="+seedURL)
+	}
+	return append(args, "console=ttyS0,115200", "console=tty0", "console=ttyAMA0", "earlyprintk=ttyS0,115200", "consoleblank=0")
+}
+
+// MergeKernelArgs merges kernel arguments into the default ones:
+//
+//   - An argument replaces all default arguments with the same key, i.e. the part before the first
+//     "=". Several arguments with the same key, e.g. console, are all kept.
+//   - An argument prefixed with "-" removes the arguments with its key, e.g. -earlyprintk, or the
+//     argument itself if it has a value, e.g. -console=tty0.
+//
+// Duplicate arguments are removed.
+func MergeKernelArgs(defaults, args []string) []string {
+	overridden := map[string]bool{}
+	var removals []string
+	for _, arg := range args {
+		if removal, ok := strings.CutPrefix(arg, "-"); ok {
+			removals = append(removals, removal)
+			continue
+		}
+		overridden[kernelArgKey(arg)] = true
+	}
+
+	var merged []string
+	for _, arg := range defaults {
+		if !overridden[kernelArgKey(arg)] {
+			merged = append(merged, arg)
+		}
+	}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") && !slices.Contains(merged, arg) {
+			merged = append(merged, arg)
+		}
+	}
+	return slices.DeleteFunc(merged, func(arg string) bool {
+		return slices.ContainsFunc(removals, func(removal string) bool {
+			return arg == removal || (!strings.Contains(removal, "=") && kernelArgKey(arg) == removal)
+		})
+	})
+}
+
+// kernelArgKey returns the key of a kernel argument, i.e. the part before the first "=".
+func kernelArgKey(arg string) string {
+	key, _, _ := strings.Cut(arg, "=")
+	return key
+}
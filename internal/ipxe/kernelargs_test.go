@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ipxe
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMergeKernelArgs(t *testing.T) {
+	defaults := []string{"initrd=initrd", "ip=any", "console=ttyS0,115200", "console=tty0", "earlyprintk=ttyS0,115200", "quiet"}
+	for name, tt := range map[string]struct {
+		args []string
+		want []string
+	}{
+		"none":         {want: defaults},
+		"add":          {args: []string{"nomodeset"}, want: append(slices.Clone(defaults), "nomodeset")},
+		"override":     {args: []string{"console=ttyS1,9600", "console=tty1"}, want: []string{"initrd=initrd", "ip=any", "earlyprintk=ttyS0,115200", "quiet", "console=ttyS1,9600", "console=tty1"}},
+		"remove":       {args: []string{"-earlyprintk", "-quiet"}, want: []string{"initrd=initrd", "ip=any", "console=ttyS0,115200", "console=tty0"}},
+		"remove value": {args: []string{"-console=tty0"}, want: []string{"initrd=initrd", "ip=any", "console=ttyS0,115200", "earlyprintk=ttyS0,115200", "quiet"}},
+		"duplicates":   {args: []string{"ip=dhcp", "ip=dhcp", "quiet"}, want: []string{"initrd=initrd", "console=ttyS0,115200", "console=tty0", "earlyprintk=ttyS0,115200", "ip=dhcp", "quiet"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := MergeKernelArgs(defaults, tt.args); !slices.Equal(got, tt.want) {
+				t.Errorf("MergeKernelArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKernelCommandLine(t *testing.T) {
+	data := TemplateData{
+		SquashfsURL:   "http://boot.example.com/squashfs",
+		IgnitionToken: "token",
+		NoCloud:       true,
+		KernelArgs:    []string{"console=ttyS1,57600", "-earlyprintk", "ignition.platform.id=packet"},
+	}
+	got := data.KernelCommandLine()
+	for _, want := range []string{
+		"gl.url=${squashfs-url}",
+		"ignition.config.url=${ipxe-svc}/ignition/${uuid}?token=token ",
+		"ds=nocloud;s=${ipxe-svc}/nocloud/${uuid}/token/ ",
+		"ignition.platform.id=packet",
+		"console=ttyS1,57600",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("KernelCommandLine() = %q, want it to contain %q", got, want)
+		}
+	}
+	for _, unwanted := range []string{"ignition.platform.id=metal", "console=ttyS0", "earlyprintk"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("KernelCommandLine() = %q, want it not to contain %q", got, unwanted)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ipxe renders the iPXE scripts that the boot-server serves from templates.
+package ipxe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// scriptHeader is the magic line that iPXE scripts start with.
+const scriptHeader = "#!ipxe"
+
+// TemplateData is the data that iPXE script templates are rendered with.
+type TemplateData struct {
+	KernelURL     string
+	InitrdURL     string
+	SquashfsURL   string
+	RegistryURL   string
+	IPXEServerURL string
+	// SystemUUID is the SystemUUID of the boot config that the script is rendered for.
+	SystemUUID string
+	// UKIURL is the URL of the unified kernel image that the script of an HTTP boot client chains.
+	UKIURL string
+	// IgnitionToken authenticates the ignition request of the booted OS.
+	IgnitionToken string
+	// MACAddress is set if the script has been looked up by MAC address, making the booted OS
+	// fetch its ignition data by MAC address too.
+	MACAddress string
+	// NoCloud makes the booted OS fetch its ignition data, which is cloud-init user-data, from the
+	// NoCloud data source of the boot-server.
+	NoCloud bool
+	// KernelArgs are added to the default kernel arguments of the booted OS, overriding or removing
+	// them, see KernelCommandLine.
+	KernelArgs []string
+
+	// Signed makes the scripts verify what they boot with imgverify. KernelSignatureURL and
+	// InitrdSignatureURL locate the detached signatures of the kernel and initrd.
+	Signed             bool
+	KernelSignatureURL string
+	InitrdSignatureURL string
+}
+
+// sampleData exercises the branches of a template that depend on the boot, so that Validate
+// catches errors in all of them.
+var sampleData = []TemplateData{
+	{
+		KernelURL:     "http://boot.example.com/kernel",
+		InitrdURL:     "http://boot.example.com/initrd",
+		IPXEServerURL: "http://boot.example.com",
+	},
+	{
+		KernelURL:          "http://boot.example.com/kernel",
+		InitrdURL:          "http://boot.example.com/initrd",
+		SquashfsURL:        "http://boot.example.com/squashfs",
+		UKIURL:             "http://boot.example.com/uki.efi",
+		RegistryURL:        "http://registry.example.com",
+		IPXEServerURL:      "http://boot.example.com",
+		SystemUUID:         "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91",
+		IgnitionToken:      "token",
+		MACAddress:         "52:54:00:12:34:56",
+		NoCloud:            true,
+		KernelArgs:         []string{"console=ttyS1,115200"},
+		Signed:             true,
+		KernelSignatureURL: "http://boot.example.com/kernel.sig",
+		InitrdSignatureURL: "http://boot.example.com/initrd.sig",
+	},
+}
+
+// IsTemplate reports whether the custom iPXE script of a Secret is to be rendered as a template.
+func IsTemplate(secret *corev1.Secret) bool {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(string(secret.Data[bootv1alpha1.DefaultTemplateKey])))
+	return err == nil && enabled
+}
+
+// Render parses text as an iPXE script template and renders it with data.
+func Render(text string, data TemplateData) ([]byte, error) {
+	tmpl, err := parse("ipxe", text)
+	if err != nil {
+		return nil, err
+	}
+	return execute(tmpl, data)
+}
+
+// Validate renders text as an iPXE script template with sample data, and verifies that the result is
+// an iPXE script.
+func Validate(text string) error {
+	tmpl, err := parse("ipxe", text)
+	if err != nil {
+		return err
+	}
+	return validate(tmpl)
+}
+
+// parse parses text as an iPXE script template.
+func parse(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// execute renders an iPXE script template with data.
+func execute(tmpl *template.Template, data TemplateData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// validate renders an iPXE script template with sample data, and verifies that the result is an
+// iPXE script.
+func validate(tmpl *template.Template) error {
+	for _, data := range sampleData {
+		script, err := execute(tmpl, data)
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(script, []byte(scriptHeader)) {
+			return errors.New("rendered script does not start with " + scriptHeader)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ipxe
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, name := range []string{"ipxe-script.tpl", "ipxe-chainload.tpl", "ipxe-uki.tpl"} {
+		data, err := os.ReadFile("../../templates/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Validate(string(data)); err != nil {
+			t.Errorf("Validate() of the built-in template %s = %v", name, err)
+		}
+	}
+
+	for name, tt := range map[string]struct {
+		text    string
+		wantErr string
+	}{
+		"custom":        {text: "#!ipxe\nkernel {{.KernelURL}} console=ttyS1,9600\ninitrd {{.InitrdURL}}\nboot\n"},
+		"syntax error":  {text: "#!ipxe\nkernel {{.KernelURL}\n", wantErr: "failed to parse"},
+		"unknown field": {text: "#!ipxe\n{{if .Signed}}{{.Signature}}{{end}}\n", wantErr: "can't evaluate field Signature"},
+		"no header":     {text: "kernel {{.KernelURL}}\n", wantErr: "does not start with #!ipxe"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := Validate(tt.text)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
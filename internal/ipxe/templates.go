@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ipxe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/template"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+
+	"github.com/ironcore-dev/boot-operator/templates"
+)
+
+const (
+	// ScriptTemplate is the name of the template that the iPXE scripts of IPXEBootConfigs are
+	// rendered from by default.
+	ScriptTemplate = "ipxe-script.tpl"
+	// ChainloadTemplate is the name of the template of the script that chainloads the iPXE script of
+	// the booting server from the boot-server.
+	ChainloadTemplate = "ipxe-chainload.tpl"
+	// UKITemplate is the name of the template of the script that chains the UKI of an HTTP boot
+	// client.
+	UKITemplate = "ipxe-uki.tpl"
+)
+
+// Templates are the parsed iPXE script templates that the boot-server renders its scripts from. The
+// built-in templates are embedded into the binary, and may be overridden by the files of the same
+// name in a directory, which are reloaded when they change.
+type Templates struct {
+	dir string
+	log logr.Logger
+
+	builtin map[string]*template.Template
+
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+}
+
+// NewTemplates parses the built-in templates and the files overriding them in dir. If dir is empty,
+// only the built-in templates are used.
+func NewTemplates(dir string, log logr.Logger) (*Templates, error) {
+	t := &Templates{
+		dir:       dir,
+		log:       log,
+		builtin:   map[string]*template.Template{},
+		templates: map[string]*template.Template{},
+	}
+	names, err := fs.Glob(templates.FS, "*.tpl")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		text, err := fs.ReadFile(templates.FS, name)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := parse(name, string(text))
+		if err != nil {
+			return nil, fmt.Errorf("built-in template %s: %w", name, err)
+		}
+		t.builtin[name] = tmpl
+		t.templates[name] = tmpl
+	}
+	t.reload()
+	return t, nil
+}
+
+// Render renders the template with the given name with data.
+func (t *Templates) Render(name string, data TemplateData) ([]byte, error) {
+	t.mu.RLock()
+	tmpl, ok := t.templates[name]
+	t.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown template %s", name)
+	}
+	return execute(tmpl, data)
+}
+
+// Watch reloads the templates whenever the files in the directory change, until ctx is done.
+func (t *Templates) Watch(ctx context.Context) error {
+	if t.dir == "" {
+		return nil
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+	if err := watcher.Add(t.dir); err != nil {
+		return fmt.Errorf("failed to watch template directory %s: %w", t.dir, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			t.log.V(1).Info("Template directory changed", "event", event.String())
+			t.reload()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			t.log.Error(err, "Failed to watch template directory", "dir", t.dir)
+		}
+	}
+}
+
+// reload parses the files in the directory that override the built-in templates. Templates whose
+// file does not exist revert to the built-in version, and templates whose file cannot be read or
+// rendered keep their previous version.
+func (t *Templates) reload() {
+	if t.dir == "" {
+		return
+	}
+	for name, builtin := range t.builtin {
+		tmpl, err := t.load(name)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			tmpl = builtin
+		case err != nil:
+			t.log.Error(err, "Failed to load template, keeping the previous version", "template", name, "dir", t.dir)
+			continue
+		default:
+			t.log.Info("Loaded template", "template", name, "dir", t.dir)
+		}
+		t.mu.Lock()
+		t.templates[name] = tmpl
+		t.mu.Unlock()
+	}
+}
+
+// load parses the file overriding the template with the given name, and verifies that it renders
+// into an iPXE script.
+func (t *Templates) load(name string) (*template.Template, error) {
+	text, err := os.ReadFile(filepath.Join(t.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := parse(name, string(text))
+	if err != nil {
+		return nil, err
+	}
+	if err := validate(tmpl); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
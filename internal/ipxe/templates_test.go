@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ipxe
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestTemplates(t *testing.T) {
+	const custom = "#!ipxe\nchain {{.IPXEServerURL}}/custom\n"
+	data := TemplateData{IPXEServerURL: "http://boot.example.com"}
+
+	render := func(t *testing.T, templates *Templates, name string) string {
+		t.Helper()
+		script, err := templates.Render(name, data)
+		if err != nil {
+			t.Fatalf("Render(%s) error = %v", name, err)
+		}
+		return string(script)
+	}
+	write := func(t *testing.T, dir, name, text string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("built-in", func(t *testing.T) {
+		templates, err := NewTemplates("", logr.Discard())
+		if err != nil {
+			t.Fatalf("NewTemplates() error = %v", err)
+		}
+		for _, name := range []string{ScriptTemplate, ChainloadTemplate, UKITemplate} {
+			if script := render(t, templates, name); !strings.HasPrefix(script, scriptHeader) {
+				t.Errorf("Render(%s) = %q", name, script)
+			}
+		}
+		if _, err := templates.Render("unknown.tpl", data); err == nil {
+			t.Error("Render() of an unknown template succeeded")
+		}
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, ChainloadTemplate, custom)
+		templates, err := NewTemplates(dir, logr.Discard())
+		if err != nil {
+			t.Fatalf("NewTemplates() error = %v", err)
+		}
+		if script := render(t, templates, ChainloadTemplate); script != "#!ipxe\nchain http://boot.example.com/custom\n" {
+			t.Errorf("Render() = %q, want the template of the directory", script)
+		}
+		if script := render(t, templates, ScriptTemplate); !strings.Contains(script, "kernel ") {
+			t.Errorf("Render() = %q, want the built-in template", script)
+		}
+
+		write(t, dir, ChainloadTemplate, "#!ipxe\nchain {{.IPXEServerURL}\n")
+		templates.reload()
+		if script := render(t, templates, ChainloadTemplate); !strings.HasSuffix(script, "/custom\n") {
+			t.Errorf("Render() = %q, want the previous template after a failed reload", script)
+		}
+
+		if err := os.Remove(filepath.Join(dir, ChainloadTemplate)); err != nil {
+			t.Fatal(err)
+		}
+		templates.reload()
+		if script := render(t, templates, ChainloadTemplate); !strings.Contains(script, "${base-url}") {
+			t.Errorf("Render() = %q, want the built-in template after removing the file", script)
+		}
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		dir := t.TempDir()
+		templates, err := NewTemplates(dir, logr.Discard())
+		if err != nil {
+			t.Fatalf("NewTemplates() error = %v", err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- templates.Watch(ctx) }()
+		defer func() {
+			cancel()
+			if err := <-done; err != nil {
+				t.Errorf("Watch() error = %v", err)
+			}
+		}()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for !strings.HasSuffix(render(t, templates, ChainloadTemplate), "/custom\n") {
+			if time.Now().After(deadline) {
+				t.Fatal("template was not reloaded")
+			}
+			// The watcher may not be set up yet, so the file is rewritten until it is picked up.
+			write(t, dir, ChainloadTemplate, custom)
+			time.Sleep(50 * time.Millisecond)
+		}
+	})
+}
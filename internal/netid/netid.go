@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package netid canonicalizes the IP and MAC addresses that identify the network interfaces of
+// servers, so that they can be indexed and looked up by exact match.
+package netid
+
+import (
+	"net"
+	"net/netip"
+)
+
+// CanonicalIP returns the canonical form of an IP address, e.g. 2001:db8::1 for 2001:DB8:0:0::1 and
+// 10.0.0.1 for ::ffff:10.0.0.1. Values that are not IP addresses are returned unchanged.
+func CanonicalIP(value string) string {
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return value
+	}
+	return addr.Unmap().String()
+}
+
+// CanonicalMAC returns the lowercase colon notation of a MAC address, e.g. 52:54:00:ab:cd:ef for
+// 52-54-00-AB-CD-EF. Values that are not MAC addresses are returned unchanged.
+func CanonicalMAC(value string) string {
+	mac, err := net.ParseMAC(value)
+	if err != nil {
+		return value
+	}
+	return mac.String()
+}
+
+// Canonical returns the canonical form of a network identifier, which is either an IP or a MAC
+// address. Values that are neither are returned unchanged.
+func Canonical(value string) string {
+	if _, err := netip.ParseAddr(value); err == nil {
+		return CanonicalIP(value)
+	}
+	return CanonicalMAC(value)
+}
+
+// CanonicalAll returns the canonical forms of a list of network identifiers.
+func CanonicalAll(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	canonical := make([]string, 0, len(values))
+	for _, value := range values {
+		canonical = append(canonical, Canonical(value))
+	}
+	return canonical
+}
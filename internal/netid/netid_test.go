@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package netid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	for value, want := range map[string]string{
+		"10.0.0.1":          "10.0.0.1",
+		"::ffff:10.0.0.1":   "10.0.0.1",
+		"2001:DB8:0:0::1":   "2001:db8::1",
+		"52-54-00-AB-CD-EF": "52:54:00:ab:cd:ef",
+		"5254.00ab.cdef":    "52:54:00:ab:cd:ef",
+		"52:54:00:ab:cd:ef": "52:54:00:ab:cd:ef",
+		"not-an-address":    "not-an-address",
+	} {
+		if got := Canonical(value); got != want {
+			t.Errorf("Canonical(%q) = %q, want %q", value, got, want)
+		}
+	}
+
+	if got := CanonicalIP("52-54-00-AB-CD-EF"); got != "52-54-00-AB-CD-EF" {
+		t.Errorf("CanonicalIP() of a MAC address = %q, want it unchanged", got)
+	}
+	if got := CanonicalMAC("::ffff:10.0.0.1"); got != "::ffff:10.0.0.1" {
+		t.Errorf("CanonicalMAC() of an IP address = %q, want it unchanged", got)
+	}
+	if got, want := CanonicalAll([]string{"10.0.0.1", "52-54-00-AB-CD-EF"}), []string{"10.0.0.1", "52:54:00:ab:cd:ef"}; !slices.Equal(got, want) {
+		t.Errorf("CanonicalAll() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,18 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package oci
+
+import (
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/ironcore-dev/boot-operator/internal/registry"
+)
+
+// NewResolver returns a docker resolver that authenticates with credentials from
+// the given keychain. A nil keychain results in anonymous access.
+func NewResolver(keychain registry.Keychain) remotes.Resolver {
+	return docker.NewResolver(docker.ResolverOptions{
+		Credentials: keychain.CredentialsFunc(),
+	})
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package prefetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/ironcore-dev/boot-operator/internal/blobcache"
+	"github.com/ironcore-dev/boot-operator/internal/oci"
+	"github.com/ironcore-dev/boot-operator/internal/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Progress describes how many of the requested blobs are available in the cache.
+type Progress struct {
+	Total  int
+	Cached int
+	// Err is the most recent prefetch failure, if any. Failed blobs are retried
+	// on the next call to Prefetch.
+	Err error
+}
+
+// Complete reports whether all requested blobs are cached.
+func (p Progress) Complete() bool {
+	return p.Cached == p.Total
+}
+
+func (p Progress) String() string {
+	return fmt.Sprintf("%d of %d boot artifacts cached", p.Cached, p.Total)
+}
+
+// Prefetcher downloads image layers into the image proxy's blob cache in the
+// background, so that machines booting from them are served locally.
+type Prefetcher struct {
+	cache           *blobcache.Cache
+	credentialStore *registry.CredentialStore
+	log             logr.Logger
+
+	mu       sync.Mutex
+	inflight map[digest.Digest]struct{}
+	failures map[digest.Digest]error
+}
+
+// NewPrefetcher creates a Prefetcher filling the given cache. Registry credentials are
+// looked up in credentialStore, which may be nil for anonymous access.
+func NewPrefetcher(cache *blobcache.Cache, credentialStore *registry.CredentialStore, log logr.Logger) *Prefetcher {
+	return &Prefetcher{
+		cache:           cache,
+		credentialStore: credentialStore,
+		log:             log,
+		inflight:        make(map[digest.Digest]struct{}),
+		failures:        make(map[digest.Digest]error),
+	}
+}
+
+// Prefetch starts downloading the layers of imageRef that are not yet cached and
+// returns immediately with the current progress. Callers poll until the returned
+// progress is complete.
+func (p *Prefetcher) Prefetch(ctx context.Context, imageRef string, pullSecret *client.ObjectKey, layerDigests []string) (Progress, error) {
+	var missing []digest.Digest
+	progress := Progress{Total: len(layerDigests)}
+	for _, layerDigest := range layerDigests {
+		dgst, err := digest.Parse(layerDigest)
+		if err != nil {
+			return Progress{}, fmt.Errorf("invalid layer digest %q: %w", layerDigest, err)
+		}
+		if p.cache.Has(dgst) {
+			progress.Cached++
+			continue
+		}
+		missing = append(missing, dgst)
+	}
+	if len(missing) == 0 {
+		return progress, nil
+	}
+
+	keychain, err := p.credentialStore.Keychain(ctx, imageRef, pullSecret)
+	if err != nil {
+		return Progress{}, fmt.Errorf("failed to get registry credentials: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, dgst := range missing {
+		if err, ok := p.failures[dgst]; ok {
+			// Report the failure once, then retry.
+			progress.Err = err
+			delete(p.failures, dgst)
+			continue
+		}
+		if _, ok := p.inflight[dgst]; ok {
+			continue
+		}
+		p.inflight[dgst] = struct{}{}
+		// Downloads outlive the reconcile that started them.
+		go p.fetch(context.WithoutCancel(ctx), imageRef, keychain, dgst)
+	}
+	return progress, nil
+}
+
+func (p *Prefetcher) fetch(ctx context.Context, imageRef string, keychain registry.Keychain, dgst digest.Digest) {
+	log := p.log.WithValues("image", imageRef, "digest", dgst)
+	log.V(1).Info("Prefetching blob")
+
+	err := p.cache.Fill(ctx, dgst, func(ctx context.Context) (io.ReadCloser, int64, error) {
+		fetcher, err := oci.NewResolver(keychain).Fetcher(ctx, imageRef)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to create fetcher: %w", err)
+		}
+		// The layer size is not known here; -1 keeps the fetcher from truncating the body.
+		body, err := fetcher.Fetch(ctx, ocispec.Descriptor{Digest: dgst, Size: -1})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to fetch blob: %w", err)
+		}
+		return body, -1, nil
+	})
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.inflight, dgst)
+	if err != nil {
+		log.Error(err, "Failed to prefetch blob")
+		p.failures[dgst] = err
+		return
+	}
+	log.Info("Prefetched blob")
+}
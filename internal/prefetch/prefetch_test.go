@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package prefetch
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/ironcore-dev/boot-operator/internal/blobcache"
+	"github.com/opencontainers/go-digest"
+)
+
+// newRegistry starts a fake registry serving the given blobs of the os/image repository
+// and returns the image reference to prefetch from.
+func newRegistry(t *testing.T, blobs ...[]byte) string {
+	t.Helper()
+	byPath := make(map[string][]byte, len(blobs))
+	for _, blob := range blobs {
+		byPath["/v2/os/image/blobs/"+digest.FromBytes(blob).String()] = blob
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		blob, ok := byPath[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(blob))
+	}))
+	t.Cleanup(server.Close)
+	return server.Listener.Addr().String() + "/os/image:v1"
+}
+
+// waitForPrefetch polls Prefetch until it completes or reports an error.
+func waitForPrefetch(t *testing.T, p *Prefetcher, imageRef string, layerDigests []string) Progress {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		progress, err := p.Prefetch(context.Background(), imageRef, nil, layerDigests)
+		if err != nil {
+			t.Fatalf("Prefetch() error = %v", err)
+		}
+		if progress.Complete() || progress.Err != nil {
+			return progress
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("prefetch did not finish in time: %s", progress)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPrefetchFillsCache(t *testing.T) {
+	kernel, initrd := []byte("kernel"), []byte("initrd")
+	imageRef := newRegistry(t, kernel, initrd)
+	cache, err := blobcache.New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("blobcache.New() error = %v", err)
+	}
+	p := NewPrefetcher(cache, nil, logr.Discard())
+
+	layerDigests := []string{digest.FromBytes(kernel).String(), digest.FromBytes(initrd).String()}
+	progress, err := p.Prefetch(context.Background(), imageRef, nil, layerDigests)
+	if err != nil {
+		t.Fatalf("Prefetch() error = %v", err)
+	}
+	if progress.Total != 2 {
+		t.Errorf("Total = %d, want 2", progress.Total)
+	}
+
+	progress = waitForPrefetch(t, p, imageRef, layerDigests)
+	if !progress.Complete() {
+		t.Fatalf("prefetch failed: %v", progress.Err)
+	}
+	for _, data := range [][]byte{kernel, initrd} {
+		if !cache.Has(digest.FromBytes(data)) {
+			t.Errorf("expected %q to be cached", data)
+		}
+	}
+}
+
+func TestPrefetchReportsFailures(t *testing.T) {
+	imageRef := newRegistry(t)
+	cache, err := blobcache.New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("blobcache.New() error = %v", err)
+	}
+	p := NewPrefetcher(cache, nil, logr.Discard())
+
+	progress := waitForPrefetch(t, p, imageRef, []string{digest.FromString("missing").String()})
+	if progress.Complete() || progress.Err == nil {
+		t.Fatalf("expected prefetch of a missing blob to fail, got %s", progress)
+	}
+	if progress.Cached != 0 {
+		t.Errorf("Cached = %d, want 0", progress.Cached)
+	}
+}
+
+func TestPrefetchRejectsInvalidDigest(t *testing.T) {
+	cache, err := blobcache.New(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("blobcache.New() error = %v", err)
+	}
+	p := NewPrefetcher(cache, nil, logr.Discard())
+
+	if _, err := p.Prefetch(context.Background(), "ghcr.io/os/image:v1", nil, []string{"not-a-digest"}); err == nil {
+		t.Fatal("expected an invalid digest to be rejected")
+	}
+}
@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/distribution/reference"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Credentials holds the secret material used to authenticate against a registry.
+// Either Username/Password or IdentityToken (an OAuth2 refresh token) is set.
+type Credentials struct {
+	Username      string
+	Password      string
+	IdentityToken string
+}
+
+// Keychain maps normalized registry hosts to their credentials.
+type Keychain map[string]Credentials
+
+// dockerConfigJSON mirrors the subset of a .dockerconfigjson document used for pulling.
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+// ParseDockerConfigJSON parses the content of a kubernetes.io/dockerconfigjson Secret
+// into a Keychain. The combined "auth" field takes precedence over username/password.
+func ParseDockerConfigJSON(data []byte) (Keychain, error) {
+	var cfg dockerConfigJSON
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse docker config json: %w", err)
+	}
+
+	keychain := make(Keychain, len(cfg.Auths))
+	for server, entry := range cfg.Auths {
+		creds := Credentials{
+			Username:      entry.Username,
+			Password:      entry.Password,
+			IdentityToken: entry.IdentityToken,
+		}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode auth for registry %s: %w", server, err)
+			}
+			username, password, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid auth for registry %s: expected username:password", server)
+			}
+			creds.Username = username
+			creds.Password = password
+		}
+		keychain[normalizeRegistryHost(server)] = creds
+	}
+	return keychain, nil
+}
+
+// Lookup returns the credentials configured for the given registry host.
+func (k Keychain) Lookup(host string) (Credentials, bool) {
+	if k == nil {
+		return Credentials{}, false
+	}
+	creds, ok := k[normalizeRegistryHost(host)]
+	return creds, ok
+}
+
+// CredentialsFunc returns a callback suitable for docker.ResolverOptions.Credentials.
+// An identity token is returned with an empty username, which the containerd
+// authorizer treats as a refresh token for the OAuth2 token exchange.
+func (k Keychain) CredentialsFunc() func(string) (string, string, error) {
+	return func(host string) (string, string, error) {
+		creds, ok := k.Lookup(host)
+		if !ok {
+			return "", "", nil
+		}
+		if creds.IdentityToken != "" {
+			return "", creds.IdentityToken, nil
+		}
+		return creds.Username, creds.Password, nil
+	}
+}
+
+// normalizeRegistryHost strips the scheme and path from a docker config server
+// entry (e.g. "https://index.docker.io/v1/") and normalizes Docker Hub variants.
+func normalizeRegistryHost(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	return normalizeDockerHubDomain(host)
+}
+
+// RepositoryKey returns the fully qualified repository name of an image reference
+// (e.g. "docker.io/library/ubuntu"), stripping any tag or digest.
+func RepositoryKey(imageRef string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference: %w", err)
+	}
+	return named.Name(), nil
+}
+
+// CredentialStore resolves registry credentials from kubernetes.io/dockerconfigjson Secrets.
+// A default Secret applies to every image unless the caller names an override.
+type CredentialStore struct {
+	client        client.Reader
+	defaultSecret *client.ObjectKey
+}
+
+// NewCredentialStore creates a CredentialStore reading Secrets through the given client.
+// defaultSecret may be nil, in which case registries are accessed anonymously unless
+// an override is given.
+func NewCredentialStore(c client.Reader, defaultSecret *client.ObjectKey) *CredentialStore {
+	return &CredentialStore{
+		client:        c,
+		defaultSecret: defaultSecret,
+	}
+}
+
+// Keychain returns the keychain to use for the given image reference: the keychain of the
+// override Secret if set, otherwise that of the default Secret. A nil store yields an
+// empty keychain.
+func (s *CredentialStore) Keychain(ctx context.Context, imageRef string, override *client.ObjectKey) (Keychain, error) {
+	if s == nil {
+		return nil, nil
+	}
+	if _, err := RepositoryKey(imageRef); err != nil {
+		return nil, err
+	}
+
+	secretKey := s.defaultSecret
+	if override != nil {
+		secretKey = override
+	}
+	if secretKey == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := s.client.Get(ctx, *secretKey, secret); err != nil {
+		return nil, fmt.Errorf("failed to get pull secret %s: %w", secretKey, err)
+	}
+	if secret.Type != corev1.SecretTypeDockerConfigJson {
+		return nil, fmt.Errorf("pull secret %s has type %q, expected %q", secretKey, secret.Type, corev1.SecretTypeDockerConfigJson)
+	}
+	keychain, err := ParseDockerConfigJSON(secret.Data[corev1.DockerConfigJsonKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid pull secret %s: %w", secretKey, err)
+	}
+	return keychain, nil
+}
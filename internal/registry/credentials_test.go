@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseDockerConfigJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		host    string
+		want    Credentials
+		wantHit bool
+		wantErr bool
+	}{
+		{
+			name:    "username and password",
+			data:    `{"auths":{"harbor.example.com":{"username":"robot","password":"s3cret"}}}`,
+			host:    "harbor.example.com",
+			want:    Credentials{Username: "robot", Password: "s3cret"},
+			wantHit: true,
+		},
+		{
+			name:    "combined auth field",
+			data:    `{"auths":{"harbor.example.com":{"auth":"cm9ib3Q6czNjcmV0"}}}`,
+			host:    "harbor.example.com",
+			want:    Credentials{Username: "robot", Password: "s3cret"},
+			wantHit: true,
+		},
+		{
+			name:    "identity token",
+			data:    `{"auths":{"harbor.example.com":{"identitytoken":"refresh"}}}`,
+			host:    "harbor.example.com",
+			want:    Credentials{IdentityToken: "refresh"},
+			wantHit: true,
+		},
+		{
+			name:    "docker hub legacy server URL",
+			data:    `{"auths":{"https://index.docker.io/v1/":{"username":"u","password":"p"}}}`,
+			host:    "registry-1.docker.io",
+			want:    Credentials{Username: "u", Password: "p"},
+			wantHit: true,
+		},
+		{
+			name:    "server entry with scheme and port",
+			data:    `{"auths":{"https://registry.example.com:5000":{"username":"u","password":"p"}}}`,
+			host:    "registry.example.com:5000",
+			want:    Credentials{Username: "u", Password: "p"},
+			wantHit: true,
+		},
+		{
+			name:    "unknown host",
+			data:    `{"auths":{"harbor.example.com":{"username":"u","password":"p"}}}`,
+			host:    "ghcr.io",
+			wantHit: false,
+		},
+		{
+			name:    "invalid auth encoding",
+			data:    `{"auths":{"harbor.example.com":{"auth":"not base64!"}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			data:    `{"auths":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keychain, err := ParseDockerConfigJSON([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDockerConfigJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, ok := keychain.Lookup(tt.host)
+			if ok != tt.wantHit {
+				t.Fatalf("Lookup(%q) found = %v, want %v", tt.host, ok, tt.wantHit)
+			}
+			if got != tt.want {
+				t.Errorf("Lookup(%q) = %+v, want %+v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeychainCredentialsFunc(t *testing.T) {
+	keychain := Keychain{
+		"harbor.example.com": {Username: "robot", Password: "s3cret"},
+		"token.example.com":  {IdentityToken: "refresh"},
+	}
+	creds := keychain.CredentialsFunc()
+
+	user, secret, err := creds("harbor.example.com")
+	if err != nil || user != "robot" || secret != "s3cret" {
+		t.Errorf("basic credentials = (%q, %q, %v), want (robot, s3cret, nil)", user, secret, err)
+	}
+	user, secret, err = creds("token.example.com")
+	if err != nil || user != "" || secret != "refresh" {
+		t.Errorf("identity token credentials = (%q, %q, %v), want (\"\", refresh, nil)", user, secret, err)
+	}
+	user, secret, err = creds("ghcr.io")
+	if err != nil || user != "" || secret != "" {
+		t.Errorf("anonymous credentials = (%q, %q, %v), want empty", user, secret, err)
+	}
+}
+
+func TestCredentialStoreKeychain(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	newPullSecret := func(namespace, name, host string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(`{"auths":{"` + host + `":{"username":"` + name + `","password":"p"}}}`),
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newPullSecret("system", "global", "harbor.example.com"),
+		newPullSecret("tenant", "override", "harbor.example.com"),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "tenant", Name: "opaque"}},
+	).Build()
+
+	ctx := context.Background()
+	store := NewCredentialStore(c, &client.ObjectKey{Namespace: "system", Name: "global"})
+
+	keychain, err := store.Keychain(ctx, "harbor.example.com/os/gardenlinux:1.0", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds, _ := keychain.Lookup("harbor.example.com"); creds.Username != "global" {
+		t.Errorf("default keychain username = %q, want global", creds.Username)
+	}
+
+	override := &client.ObjectKey{Namespace: "tenant", Name: "override"}
+	keychain, err = store.Keychain(ctx, "harbor.example.com/os/gardenlinux:1.0", override)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds, _ := keychain.Lookup("harbor.example.com"); creds.Username != "override" {
+		t.Errorf("override keychain username = %q, want override", creds.Username)
+	}
+
+	// An override only applies to the lookup naming it; later lookups use the default Secret.
+	keychain, err = store.Keychain(ctx, "harbor.example.com/os/gardenlinux@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds, _ := keychain.Lookup("harbor.example.com"); creds.Username != "global" {
+		t.Errorf("keychain username after override = %q, want global", creds.Username)
+	}
+
+	if _, err := store.Keychain(ctx, "harbor.example.com/os/other:1.0", &client.ObjectKey{Namespace: "tenant", Name: "opaque"}); err == nil {
+		t.Error("expected error for a Secret that is not of type dockerconfigjson")
+	}
+
+	var nilStore *CredentialStore
+	if keychain, err := nilStore.Keychain(ctx, "ghcr.io/foo/bar:1.0", nil); err != nil || keychain != nil {
+		t.Errorf("nil store = (%v, %v), want (nil, nil)", keychain, err)
+	}
+}
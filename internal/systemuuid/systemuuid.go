@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package systemuuid classifies and canonicalizes the SMBIOS UUIDs that servers identify themselves
+// with.
+package systemuuid
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// pattern matches the textual representation of an SMBIOS UUID.
+var pattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// placeholders are SMBIOS UUIDs that boards commonly ship with instead of a unique one.
+var placeholders = []string{
+	"00000000-0000-0000-0000-000000000000",
+	"ffffffff-ffff-ffff-ffff-ffffffffffff",
+	"03000200-0400-0500-0006-000700080009",
+}
+
+// IsValid reports whether uuid is the textual representation of an SMBIOS UUID.
+func IsValid(uuid string) bool {
+	return pattern.MatchString(uuid)
+}
+
+// IsPlaceholder reports whether uuid is a known vendor placeholder that does not identify a single
+// server.
+func IsPlaceholder(uuid string) bool {
+	return slices.Contains(placeholders, strings.ToLower(uuid))
+}
+
+// Canonical returns the lowercase RFC 4122 form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx of uuid. It also
+// accepts the forms without hyphens, in braces and with a urn:uuid: prefix. Values that are not UUIDs
+// are returned lowercased, so that they can still be compared case-insensitively.
+func Canonical(value string) string {
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return strings.ToLower(value)
+	}
+	return parsed.String()
+}
+
+// Swapped returns the canonical form of the mixed-endian variant of uuid, in which the bytes of the
+// first three fields are reversed. SMBIOS stores these fields in little-endian byte order, and some
+// firmware reports them without converting them, so the same server may be known by either form.
+// It returns an empty string if uuid is not a UUID.
+func Swapped(value string) string {
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return ""
+	}
+	slices.Reverse(parsed[0:4])
+	slices.Reverse(parsed[4:6])
+	slices.Reverse(parsed[6:8])
+	return parsed.String()
+}
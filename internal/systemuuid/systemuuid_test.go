@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package systemuuid
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		uuid        string
+		valid       bool
+		placeholder bool
+	}{
+		{uuid: "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91", valid: true},
+		{uuid: "5F2C8A1E-9B3D-4C7F-A6E1-0D4B8C2E7F91", valid: true},
+		{uuid: "00000000-0000-0000-0000-000000000000", valid: true, placeholder: true},
+		{uuid: "FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF", valid: true, placeholder: true},
+		{uuid: "03000200-0400-0500-0006-000700080009", valid: true, placeholder: true},
+		{uuid: "5f2c8a1e9b3d4c7fa6e10d4b8c2e7f91"},
+		{uuid: "not-a-uuid"},
+		{uuid: ""},
+	}
+	for _, tt := range tests {
+		if got := IsValid(tt.uuid); got != tt.valid {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.uuid, got, tt.valid)
+		}
+		if got := IsPlaceholder(tt.uuid); got != tt.placeholder {
+			t.Errorf("IsPlaceholder(%q) = %v, want %v", tt.uuid, got, tt.placeholder)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	for value, want := range map[string]string{
+		"5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91":          "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91",
+		"5F2C8A1E-9B3D-4C7F-A6E1-0D4B8C2E7F91":          "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91",
+		"5F2C8A1E9B3D4C7FA6E10D4B8C2E7F91":              "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91",
+		"{5F2C8A1E-9B3D-4C7F-A6E1-0D4B8C2E7F91}":        "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91",
+		"urn:uuid:5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91": "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91",
+		"Not-A-UUID": "not-a-uuid",
+		"":           "",
+	} {
+		if got := Canonical(value); got != want {
+			t.Errorf("Canonical(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestSwapped(t *testing.T) {
+	for value, want := range map[string]string{
+		"5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91": "1e8a2c5f-3d9b-7f4c-a6e1-0d4b8c2e7f91",
+		"1E8A2C5F-3D9B-7F4C-A6E1-0D4B8C2E7F91": "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91",
+		"5f2c8a1e9b3d4c7fa6e10d4b8c2e7f91":     "1e8a2c5f-3d9b-7f4c-a6e1-0d4b8c2e7f91",
+		"not-a-uuid":                           "",
+		"":                                     "",
+	} {
+		if got := Swapped(value); got != want {
+			t.Errorf("Swapped(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
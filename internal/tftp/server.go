@@ -0,0 +1,315 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tftp implements a read-only TFTP server (RFC 1350) supporting the blksize,
+// timeout and tsize options (RFC 2347, 2348 and 2349) used by PXE firmware.
+package tftp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	opRRQ   uint16 = 1
+	opWRQ   uint16 = 2
+	opDATA  uint16 = 3
+	opACK   uint16 = 4
+	opERROR uint16 = 5
+	opOACK  uint16 = 6
+)
+
+const (
+	errCodeNotDefined       uint16 = 0
+	errCodeFileNotFound     uint16 = 1
+	errCodeAccessViolation  uint16 = 2
+	errCodeIllegalOperation uint16 = 4
+	errCodeUnknownTID       uint16 = 5
+)
+
+const (
+	defaultBlockSize = 512
+	minBlockSize     = 8
+	maxBlockSize     = 65464
+
+	defaultTimeout = 2 * time.Second
+	defaultRetries = 5
+)
+
+// ErrNotFound is returned by a ReadFunc if the requested file does not exist.
+var ErrNotFound = errors.New("file not found")
+
+// errAborted is returned when the client terminates a transfer with an ERROR packet.
+var errAborted = errors.New("transfer aborted by client")
+
+// ReadFunc opens the file requested by a client. size is the length of the file,
+// or -1 if it is unknown.
+type ReadFunc func(filename string, remote net.Addr) (r io.ReadCloser, size int64, err error)
+
+// Server is a read-only TFTP server. Write requests are rejected.
+type Server struct {
+	// ReadFunc opens requested files.
+	ReadFunc ReadFunc
+	Log      logr.Logger
+	// Timeout is the time to wait for an acknowledgement before retransmitting a packet,
+	// unless the client negotiates a different one. Defaults to 2 seconds.
+	Timeout time.Duration
+	// Retries is the number of retransmissions before a transfer is abandoned. Defaults to 5.
+	Retries int
+}
+
+// ListenAndServe listens on the UDP address addr and serves requests until ctx is done.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	return s.Serve(ctx, conn)
+}
+
+// Serve serves requests arriving on conn until ctx is done. Each transfer is handled
+// on its own socket, as required by the protocol. conn is closed when Serve returns.
+func (s *Server) Serve(ctx context.Context, conn net.PacketConn) error {
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	buf := make([]byte, maxBlockSize+4)
+	for {
+		n, remote, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+		req, err := parseRequest(buf[:n])
+		if err != nil {
+			s.Log.V(1).Info("Ignoring malformed request", "remote", remote, "error", err)
+			continue
+		}
+		go s.handle(ctx, conn.LocalAddr(), remote, req)
+	}
+}
+
+type request struct {
+	opcode   uint16
+	filename string
+	mode     string
+	options  map[string]string
+}
+
+func parseRequest(packet []byte) (*request, error) {
+	if len(packet) < 2 {
+		return nil, errors.New("packet too short")
+	}
+	req := &request{opcode: binary.BigEndian.Uint16(packet)}
+	if req.opcode != opRRQ && req.opcode != opWRQ {
+		return nil, fmt.Errorf("unexpected opcode %d", req.opcode)
+	}
+	fields := strings.Split(string(packet[2:]), "\x00")
+	// A well-formed request ends with a NUL byte, leaving an empty last field.
+	if len(fields) < 3 || fields[len(fields)-1] != "" {
+		return nil, errors.New("request is not NUL terminated")
+	}
+	fields = fields[:len(fields)-1]
+	req.filename, req.mode = fields[0], strings.ToLower(fields[1])
+	req.options = make(map[string]string)
+	for i := 2; i+1 < len(fields); i += 2 {
+		req.options[strings.ToLower(fields[i])] = fields[i+1]
+	}
+	return req, nil
+}
+
+// transfer is a single read transfer to a client.
+type transfer struct {
+	conn      net.PacketConn
+	remote    net.Addr
+	blockSize int
+	timeout   time.Duration
+	retries   int
+}
+
+func (s *Server) handle(ctx context.Context, local, remote net.Addr, req *request) {
+	log := s.Log.WithValues("remote", remote, "filename", req.filename)
+
+	// Answer from an ephemeral port on the address the request was received on.
+	host := ""
+	if udpAddr, ok := local.(*net.UDPAddr); ok && !udpAddr.IP.IsUnspecified() {
+		host = udpAddr.IP.String()
+	}
+	conn, err := net.ListenPacket("udp", net.JoinHostPort(host, "0"))
+	if err != nil {
+		log.Error(err, "Failed to open transfer socket")
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	t := &transfer{
+		conn:      conn,
+		remote:    remote,
+		blockSize: defaultBlockSize,
+		timeout:   s.Timeout,
+		retries:   s.Retries,
+	}
+	if t.timeout <= 0 {
+		t.timeout = defaultTimeout
+	}
+	if t.retries <= 0 {
+		t.retries = defaultRetries
+	}
+
+	if req.opcode == opWRQ {
+		t.sendError(errCodeAccessViolation, "write requests are not supported")
+		return
+	}
+	if req.mode != "octet" {
+		t.sendError(errCodeIllegalOperation, fmt.Sprintf("unsupported transfer mode %q", req.mode))
+		return
+	}
+
+	r, size, err := s.ReadFunc(req.filename, remote)
+	if errors.Is(err, ErrNotFound) {
+		log.V(1).Info("Requested file not found")
+		t.sendError(errCodeFileNotFound, "file not found")
+		return
+	}
+	if err != nil {
+		log.Error(err, "Failed to open requested file")
+		t.sendError(errCodeNotDefined, "failed to open file")
+		return
+	}
+	defer func() { _ = r.Close() }()
+
+	if err := t.negotiate(req.options, size); err != nil {
+		// PXE ROMs commonly abort after the OACK once they have learned the file size.
+		log.V(1).Info("Transfer ended during option negotiation", "reason", err)
+		return
+	}
+	sent, err := t.send(r)
+	if err != nil {
+		log.Info("Transfer failed", "error", err, "bytesSent", sent)
+		return
+	}
+	log.Info("Served file", "bytes", sent, "blockSize", t.blockSize)
+}
+
+// negotiate acknowledges the options the server supports with an OACK, if any were requested.
+func (t *transfer) negotiate(options map[string]string, size int64) error {
+	accepted := make([]string, 0, 6)
+	if v, ok := options["blksize"]; ok {
+		if n, err := strconv.Atoi(v); err == nil && n >= minBlockSize {
+			t.blockSize = min(n, maxBlockSize)
+			accepted = append(accepted, "blksize", strconv.Itoa(t.blockSize))
+		}
+	}
+	if v, ok := options["timeout"]; ok {
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 && n <= 255 {
+			t.timeout = time.Duration(n) * time.Second
+			accepted = append(accepted, "timeout", v)
+		}
+	}
+	if _, ok := options["tsize"]; ok && size >= 0 {
+		accepted = append(accepted, "tsize", strconv.FormatInt(size, 10))
+	}
+	if len(accepted) == 0 {
+		return nil
+	}
+
+	packet := binary.BigEndian.AppendUint16(nil, opOACK)
+	for _, field := range accepted {
+		packet = append(packet, field...)
+		packet = append(packet, 0)
+	}
+	return t.sendAndWait(packet, 0)
+}
+
+// send transfers the contents of r in blocks and returns the number of bytes sent.
+func (t *transfer) send(r io.Reader) (int64, error) {
+	buf := make([]byte, 4+t.blockSize)
+	binary.BigEndian.PutUint16(buf, opDATA)
+	var sent int64
+	// The block number wraps around for files with more than 65535 blocks.
+	for block := uint16(1); ; block++ {
+		n, err := io.ReadFull(r, buf[4:])
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.sendError(errCodeNotDefined, "failed to read file")
+			return sent, fmt.Errorf("failed to read file: %w", err)
+		}
+		binary.BigEndian.PutUint16(buf[2:], block)
+		if err := t.sendAndWait(buf[:4+n], block); err != nil {
+			return sent, err
+		}
+		sent += int64(n)
+		// A block shorter than the block size ends the transfer.
+		if n < t.blockSize {
+			return sent, nil
+		}
+	}
+}
+
+// sendAndWait sends packet and waits for the client to acknowledge block, retransmitting
+// the packet on timeout.
+func (t *transfer) sendAndWait(packet []byte, block uint16) error {
+	buf := make([]byte, maxBlockSize+4)
+	for attempt := 0; attempt <= t.retries; attempt++ {
+		if _, err := t.conn.WriteTo(packet, t.remote); err != nil {
+			return fmt.Errorf("failed to send packet: %w", err)
+		}
+		if err := t.conn.SetReadDeadline(time.Now().Add(t.timeout)); err != nil {
+			return err
+		}
+		for {
+			n, from, err := t.conn.ReadFrom(buf)
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read acknowledgement: %w", err)
+			}
+			if from.String() != t.remote.String() {
+				sendError(t.conn, from, errCodeUnknownTID, "unknown transfer ID")
+				continue
+			}
+			if n < 4 {
+				continue
+			}
+			switch binary.BigEndian.Uint16(buf) {
+			case opACK:
+				// Ignore duplicate acknowledgements of earlier blocks instead of
+				// retransmitting, which would double the traffic (Sorcerer's Apprentice).
+				if binary.BigEndian.Uint16(buf[2:]) == block {
+					return nil
+				}
+			case opERROR:
+				return fmt.Errorf("%w: %s", errAborted, bytes.TrimRight(buf[4:n], "\x00"))
+			}
+		}
+	}
+	return fmt.Errorf("timed out waiting for acknowledgement of block %d", block)
+}
+
+func (t *transfer) sendError(code uint16, message string) {
+	sendError(t.conn, t.remote, code, message)
+}
+
+func sendError(conn net.PacketConn, remote net.Addr, code uint16, message string) {
+	packet := binary.BigEndian.AppendUint16(nil, opERROR)
+	packet = binary.BigEndian.AppendUint16(packet, code)
+	packet = append(packet, message...)
+	packet = append(packet, 0)
+	_, _ = conn.WriteTo(packet, remote)
+}
@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package tftp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// startServer serves the given files on a loopback address and returns that address.
+func startServer(t *testing.T, files map[string][]byte) net.Addr {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &Server{
+		ReadFunc: func(filename string, _ net.Addr) (io.ReadCloser, int64, error) {
+			data, ok := files[filename]
+			if !ok {
+				return nil, 0, ErrNotFound
+			}
+			return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+		},
+		Log:     logr.Discard(),
+		Timeout: 200 * time.Millisecond,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = s.Serve(ctx, conn) }()
+	return conn.LocalAddr()
+}
+
+// client is a minimal TFTP client for tests.
+type client struct {
+	t    *testing.T
+	conn net.PacketConn
+	peer net.Addr
+}
+
+func newClient(t *testing.T, server net.Addr) *client {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return &client{t: t, conn: conn, peer: server}
+}
+
+func (c *client) send(opcode uint16, fields ...string) {
+	c.t.Helper()
+	packet := binary.BigEndian.AppendUint16(nil, opcode)
+	for _, field := range fields {
+		packet = append(packet, field...)
+		packet = append(packet, 0)
+	}
+	if _, err := c.conn.WriteTo(packet, c.peer); err != nil {
+		c.t.Fatalf("failed to send packet: %v", err)
+	}
+}
+
+func (c *client) ack(block uint16) {
+	c.t.Helper()
+	packet := binary.BigEndian.AppendUint16(nil, opACK)
+	packet = binary.BigEndian.AppendUint16(packet, block)
+	if _, err := c.conn.WriteTo(packet, c.peer); err != nil {
+		c.t.Fatalf("failed to send ACK: %v", err)
+	}
+}
+
+// receive returns the opcode and payload of the next packet, following the server's
+// transfer ID.
+func (c *client) receive() (uint16, []byte) {
+	c.t.Helper()
+	buf := make([]byte, maxBlockSize+4)
+	if err := c.conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		c.t.Fatalf("failed to set deadline: %v", err)
+	}
+	n, from, err := c.conn.ReadFrom(buf)
+	if err != nil {
+		c.t.Fatalf("failed to receive packet: %v", err)
+	}
+	c.peer = from
+	return binary.BigEndian.Uint16(buf), buf[2:n]
+}
+
+// download receives DATA packets until the transfer ends.
+func (c *client) download(blockSize int) []byte {
+	c.t.Helper()
+	var data []byte
+	for want := uint16(1); ; want++ {
+		opcode, payload := c.receive()
+		if opcode != opDATA {
+			c.t.Fatalf("got opcode %d, want DATA", opcode)
+		}
+		if block := binary.BigEndian.Uint16(payload); block != want {
+			c.t.Fatalf("got block %d, want %d", block, want)
+		}
+		data = append(data, payload[2:]...)
+		c.ack(want)
+		if len(payload)-2 < blockSize {
+			return data
+		}
+	}
+}
+
+func TestServeFile(t *testing.T) {
+	// A multiple of the block size ends with an empty block.
+	content := bytes.Repeat([]byte("x"), 2*defaultBlockSize)
+	c := newClient(t, startServer(t, map[string][]byte{"undionly.kpxe": content}))
+
+	c.send(opRRQ, "undionly.kpxe", "octet")
+	if got := c.download(defaultBlockSize); !bytes.Equal(got, content) {
+		t.Errorf("downloaded %d bytes, want %d", len(got), len(content))
+	}
+}
+
+func TestNegotiateOptions(t *testing.T) {
+	content := bytes.Repeat([]byte("ipxe"), 1000)
+	c := newClient(t, startServer(t, map[string][]byte{"ipxe.efi": content}))
+
+	c.send(opRRQ, "ipxe.efi", "octet", "blksize", "1432", "tsize", "0", "unknown", "1")
+	opcode, payload := c.receive()
+	if opcode != opOACK {
+		t.Fatalf("got opcode %d, want OACK", opcode)
+	}
+	if got, want := string(payload), "blksize\x001432\x00tsize\x004000\x00"; got != want {
+		t.Errorf("OACK = %q, want %q", got, want)
+	}
+	c.ack(0)
+	if got := c.download(1432); !bytes.Equal(got, content) {
+		t.Errorf("downloaded %d bytes, want %d", len(got), len(content))
+	}
+}
+
+func TestRetransmitsUnacknowledgedBlocks(t *testing.T) {
+	c := newClient(t, startServer(t, map[string][]byte{"boot.ipxe": []byte("#!ipxe")}))
+
+	c.send(opRRQ, "boot.ipxe", "octet")
+	_, first := c.receive()
+	// Drop the acknowledgement; the server must send the same block again.
+	opcode, second := c.receive()
+	if opcode != opDATA || !bytes.Equal(first, second) {
+		t.Fatalf("got opcode %d with %q, want retransmission of %q", opcode, second, first)
+	}
+	c.ack(1)
+}
+
+func TestRejectsRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		opcode   uint16
+		fields   []string
+		wantCode uint16
+	}{
+		{
+			name:     "missing file",
+			opcode:   opRRQ,
+			fields:   []string{"missing.efi", "octet"},
+			wantCode: errCodeFileNotFound,
+		},
+		{
+			name:     "write request",
+			opcode:   opWRQ,
+			fields:   []string{"snp.efi", "octet"},
+			wantCode: errCodeAccessViolation,
+		},
+		{
+			name:     "netascii mode",
+			opcode:   opRRQ,
+			fields:   []string{"snp.efi", "netascii"},
+			wantCode: errCodeIllegalOperation,
+		},
+	}
+	server := startServer(t, map[string][]byte{"snp.efi": []byte("snp")})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newClient(t, server)
+			c.send(tt.opcode, tt.fields...)
+			opcode, payload := c.receive()
+			if opcode != opERROR {
+				t.Fatalf("got opcode %d, want ERROR", opcode)
+			}
+			if code := binary.BigEndian.Uint16(payload); code != tt.wantCode {
+				t.Errorf("error code = %d, want %d (%s)", code, tt.wantCode, strings.TrimRight(string(payload[2:]), "\x00"))
+			}
+		})
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	if _, err := parseRequest([]byte{0, 1, 'a'}); err == nil {
+		t.Error("expected unterminated request to be rejected")
+	}
+	if _, err := parseRequest([]byte{0, 3, 0, 1}); err == nil {
+		t.Error("expected DATA packet to be rejected as a request")
+	}
+	req, err := parseRequest([]byte("\x00\x01ipxe.efi\x00OCTET\x00BLKSIZE\x001024\x00"))
+	if err != nil {
+		t.Fatalf("parseRequest() error = %v", err)
+	}
+	if req.filename != "ipxe.efi" || req.mode != "octet" || req.options["blksize"] != "1024" {
+		t.Errorf("parseRequest() = %+v", req)
+	}
+}
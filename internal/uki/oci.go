@@ -8,19 +8,21 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/containerd/containerd/remotes/docker"
 	"github.com/ironcore-dev/boot-operator/internal/oci"
+	"github.com/ironcore-dev/boot-operator/internal/registry"
 )
 
 const MediaTypeUKI = "application/vnd.ironcore.image.uki"
 
-func ConstructUKIURLFromOCI(ctx context.Context, image string, imageServerURL string, architecture string) (string, error) {
+// ConstructUKIURLFromOCI resolves the UKI layer of an OCI image and builds the image server URL for it.
+// The keychain is used to authenticate against private registries and may be nil.
+func ConstructUKIURLFromOCI(ctx context.Context, image string, imageServerURL string, architecture string, keychain registry.Keychain) (string, error) {
 	repository, imageRef, err := parseOCIReferenceForUKI(image)
 	if err != nil {
 		return "", err
 	}
 
-	ukiDigest, err := getUKIDigestFromNestedManifest(ctx, imageRef, architecture)
+	ukiDigest, err := getUKIDigestFromNestedManifest(ctx, imageRef, architecture, keychain)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch UKI layer digest: %w", err)
 	}
@@ -74,8 +76,8 @@ func lastTagSeparatorIndex(ref string) int {
 	return -1
 }
 
-func getUKIDigestFromNestedManifest(ctx context.Context, imageRef, architecture string) (string, error) {
-	resolver := docker.NewResolver(docker.ResolverOptions{})
+func getUKIDigestFromNestedManifest(ctx context.Context, imageRef, architecture string, keychain registry.Keychain) (string, error) {
+	resolver := oci.NewResolver(keychain)
 	name, desc, err := resolver.Resolve(ctx, imageRef)
 	if err != nil {
 		return "", fmt.Errorf("failed to resolve image reference: %w", err)
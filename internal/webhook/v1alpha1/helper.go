@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"unicode"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// validateSystemUUID checks that a SystemUUID, if set, is an SMBIOS UUID.
+func validateSystemUUID(uuid string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if uuid != "" && !systemuuid.IsValid(uuid) {
+		allErrs = append(allErrs, field.Invalid(path, uuid, "must be an SMBIOS UUID of the form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"))
+	}
+	return allErrs
+}
+
+// validateURL checks that a URL, if set, is an absolute http or https URL.
+func validateURL(value string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if value == "" {
+		return allErrs
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(path, value, "must be an absolute http or https URL"))
+	}
+	return allErrs
+}
+
+// validateIPs checks that each of the values is an IP address.
+func validateIPs(values []string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, value := range values {
+		if _, err := netip.ParseAddr(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), value, "must be an IP address"))
+		}
+	}
+	return allErrs
+}
+
+// validateMACs checks that each of the values is a MAC address.
+func validateMACs(values []string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, value := range values {
+		if _, err := net.ParseMAC(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), value, "must be a MAC address"))
+		}
+	}
+	return allErrs
+}
+
+// validateNetworkIdentifiers checks that each of the values is an IP or MAC address.
+func validateNetworkIdentifiers(values []string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, value := range values {
+		if _, err := netip.ParseAddr(value); err == nil {
+			continue
+		}
+		if _, err := net.ParseMAC(value); err == nil {
+			continue
+		}
+		allErrs = append(allErrs, field.Invalid(path.Index(i), value, "must be an IP or MAC address"))
+	}
+	return allErrs
+}
+
+// validateIgnitionLayers checks that each ignition layer references a Secret, and that no Secret is
+// layered twice.
+func validateIgnitionLayers(layers []bootv1alpha1.IgnitionLayer, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := map[string]bool{}
+	for i, layer := range layers {
+		namePath := path.Index(i).Child("secretRef", "name")
+		switch {
+		case layer.SecretRef.Name == "":
+			allErrs = append(allErrs, field.Required(namePath, "must reference a Secret"))
+		case seen[layer.SecretRef.Name]:
+			allErrs = append(allErrs, field.Duplicate(namePath, layer.SecretRef.Name))
+		}
+		seen[layer.SecretRef.Name] = true
+	}
+	return allErrs
+}
+
+// validateKernelArgs checks that each of the values is a single kernel argument, optionally prefixed
+// with "-" to remove it.
+func validateKernelArgs(values []string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, value := range values {
+		arg := strings.TrimPrefix(value, "-")
+		if arg == "" || strings.HasPrefix(arg, "=") || strings.ContainsFunc(arg, unicode.IsSpace) {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), value, "must be a kernel argument of the form key or key=value without whitespace, optionally prefixed with -"))
+		}
+	}
+	return allErrs
+}
+
+// validateSystemUUIDCollision checks that the SystemUUID of a boot config is not used by another
+// boot config of the same kind that is served for a different Server. Boot configs of the same
+// Server, e.g. its workload and maintenance boot configs, may share the SystemUUID, and orphaned
+// boot configs are ignored. Vendor placeholder UUIDs are allowed to collide, since the boot-server
+// matches them by the address of the client.
+func validateSystemUUIDCollision(ctx context.Context, c client.Client, config client.Object, uuid string, list client.ObjectList, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if uuid == "" || systemuuid.IsPlaceholder(uuid) {
+		return allErrs
+	}
+
+	if err := c.List(ctx, list, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: systemuuid.Canonical(uuid)}); err != nil {
+		return append(allErrs, field.InternalError(path, fmt.Errorf("failed to list boot configs by SystemUUID: %w", err)))
+	}
+	others, err := apimeta.ExtractList(list)
+	if err != nil {
+		return append(allErrs, field.InternalError(path, err))
+	}
+	if len(others) == 0 {
+		return allErrs
+	}
+
+	serverName, _, err := owningServer(ctx, c, config)
+	if err != nil {
+		return append(allErrs, field.InternalError(path, err))
+	}
+	for _, item := range others {
+		other, ok := item.(client.Object)
+		if !ok || (other.GetNamespace() == config.GetNamespace() && other.GetName() == config.GetName()) {
+			continue
+		}
+		otherServerName, recognized, err := owningServer(ctx, c, other)
+		if err != nil {
+			return append(allErrs, field.InternalError(path, err))
+		}
+		if !recognized || (serverName != "" && otherServerName == serverName) {
+			continue
+		}
+		allErrs = append(allErrs, field.Duplicate(path, fmt.Sprintf("%s (used by %s of Server %s)",
+			uuid, client.ObjectKeyFromObject(other), otherServerName)))
+	}
+	return allErrs
+}
+
+// owningServer returns the name of the Server that the ServerBootConfiguration owning a boot config
+// refers to, and whether the Server still references that ServerBootConfiguration, i.e. whether the
+// boot config is not orphaned.
+func owningServer(ctx context.Context, c client.Client, config client.Object) (string, bool, error) {
+	owner := metav1.GetControllerOf(config)
+	if owner == nil || owner.APIVersion != metalv1alpha1.GroupVersion.String() || owner.Kind != "ServerBootConfiguration" {
+		return "", false, nil
+	}
+	sbc := &metalv1alpha1.ServerBootConfiguration{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: config.GetNamespace(), Name: owner.Name}, sbc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to get ServerBootConfiguration %s: %w", owner.Name, err)
+	}
+	server := &metalv1alpha1.Server{}
+	if err := c.Get(ctx, client.ObjectKey{Name: sbc.Spec.ServerRef.Name}, server); err != nil {
+		if apierrors.IsNotFound(err) {
+			return sbc.Spec.ServerRef.Name, false, nil
+		}
+		return "", false, fmt.Errorf("failed to get Server %s: %w", sbc.Spec.ServerRef.Name, err)
+	}
+	for _, ref := range []*metalv1alpha1.ObjectReference{server.Spec.BootConfigurationRef, server.Spec.MaintenanceBootConfigurationRef} {
+		if ref != nil && ref.Namespace == sbc.Namespace && ref.Name == sbc.Name {
+			return server.Name, true, nil
+		}
+	}
+	return server.Name, false, nil
+}
+
+// sameSystemUUID reports whether two SystemUUIDs are equal in their canonical form.
+func sameSystemUUID(a, b string) bool {
+	return a != "" && systemuuid.Canonical(a) == systemuuid.Canonical(b)
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/netid"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+)
+
+// log is for logging in this package.
+var httpbootconfiglog = logf.Log.WithName("httpbootconfig-resource")
+
+// SetupHTTPBootConfigWebhookWithManager registers the webhook for HTTPBootConfig in the manager.
+func SetupHTTPBootConfigWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &bootv1alpha1.HTTPBootConfig{}).
+		WithValidator(&HTTPBootConfigCustomValidator{Client: mgr.GetClient()}).
+		WithDefaulter(&HTTPBootConfigCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-boot-ironcore-dev-v1alpha1-httpbootconfig,mutating=true,failurePolicy=fail,sideEffects=None,groups=boot.ironcore.dev,resources=httpbootconfigs,verbs=create;update,versions=v1alpha1,name=mhttpbootconfig-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// HTTPBootConfigCustomDefaulter normalizes the HTTPBootConfig resource when it is created or updated, so that
+// the boot-server can look it up by the canonical SystemUUID and network addresses.
+type HTTPBootConfigCustomDefaulter struct{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type HTTPBootConfig.
+func (d *HTTPBootConfigCustomDefaulter) Default(_ context.Context, obj *bootv1alpha1.HTTPBootConfig) error {
+	httpbootconfiglog.Info("Defaulting for HTTPBootConfig", "name", obj.GetName())
+
+	obj.Spec.SystemUUID = systemuuid.Canonical(obj.Spec.SystemUUID)
+	obj.Spec.NetworkIdentifiers = netid.CanonicalAll(obj.Spec.NetworkIdentifiers)
+	return nil
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:path=/validate-boot-ironcore-dev-v1alpha1-httpbootconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=boot.ironcore.dev,resources=httpbootconfigs,verbs=create;update,versions=v1alpha1,name=vhttpbootconfig-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// HTTPBootConfigCustomValidator validates the HTTPBootConfig resource when it is created or updated.
+type HTTPBootConfigCustomValidator struct {
+	Client client.Client
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type HTTPBootConfig.
+func (v *HTTPBootConfigCustomValidator) ValidateCreate(ctx context.Context, obj *bootv1alpha1.HTTPBootConfig) (admission.Warnings, error) {
+	httpbootconfiglog.Info("Validation for HTTPBootConfig upon creation", "name", obj.GetName())
+
+	allErrs := validateHTTPBootConfigSpec(obj.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, v.validateSystemUUIDCollision(ctx, obj)...)
+	return nil, invalidHTTPBootConfig(obj, allErrs)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type HTTPBootConfig.
+func (v *HTTPBootConfigCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj *bootv1alpha1.HTTPBootConfig) (admission.Warnings, error) {
+	httpbootconfiglog.Info("Validation for HTTPBootConfig upon update", "name", newObj.GetName())
+
+	allErrs := validateHTTPBootConfigSpec(newObj.Spec, field.NewPath("spec"))
+	if !sameSystemUUID(oldObj.Spec.SystemUUID, newObj.Spec.SystemUUID) {
+		allErrs = append(allErrs, v.validateSystemUUIDCollision(ctx, newObj)...)
+	}
+	return nil, invalidHTTPBootConfig(newObj, allErrs)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type HTTPBootConfig.
+func (v *HTTPBootConfigCustomValidator) ValidateDelete(_ context.Context, obj *bootv1alpha1.HTTPBootConfig) (admission.Warnings, error) {
+	httpbootconfiglog.Info("Validation for HTTPBootConfig upon deletion", "name", obj.GetName())
+
+	return nil, nil
+}
+
+func (v *HTTPBootConfigCustomValidator) validateSystemUUIDCollision(ctx context.Context, config *bootv1alpha1.HTTPBootConfig) field.ErrorList {
+	return validateSystemUUIDCollision(ctx, v.Client, config, config.Spec.SystemUUID, &bootv1alpha1.HTTPBootConfigList{},
+		field.NewPath("spec", "systemUUID"))
+}
+
+func validateHTTPBootConfigSpec(spec bootv1alpha1.HTTPBootConfigSpec, path *field.Path) field.ErrorList {
+	allErrs := validateSystemUUID(spec.SystemUUID, path.Child("systemUUID"))
+	allErrs = append(allErrs, validateNetworkIdentifiers(spec.NetworkIdentifiers, path.Child("networkIdentifiers"))...)
+	allErrs = append(allErrs, validateURL(spec.UKIURL, path.Child("ukiURL"))...)
+	allErrs = append(allErrs, validateIgnitionLayers(spec.IgnitionLayers, path.Child("ignitionLayers"))...)
+	return allErrs
+}
+
+func invalidHTTPBootConfig(config *bootv1alpha1.HTTPBootConfig, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(bootv1alpha1.GroupVersion.WithKind("HTTPBootConfig").GroupKind(), config.GetName(), allErrs)
+}
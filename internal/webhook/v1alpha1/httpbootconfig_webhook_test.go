@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHTTPBootConfigSpecValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  bootv1alpha1.HTTPBootConfigSpec
+		field string
+	}{
+		{
+			name: "valid",
+			spec: bootv1alpha1.HTTPBootConfigSpec{
+				SystemUUID:         systemUUID,
+				NetworkIdentifiers: []string{"10.0.0.10", "2001:db8::10", "52:54:00:ab:cd:ef"},
+				UKIURL:             "https://images.example.com/uki.efi",
+			},
+		},
+		{name: "empty", spec: bootv1alpha1.HTTPBootConfigSpec{}},
+		{name: "invalid SystemUUID", spec: bootv1alpha1.HTTPBootConfigSpec{SystemUUID: "abc"}, field: "spec.systemUUID"},
+		{
+			name:  "invalid network identifier",
+			spec:  bootv1alpha1.HTTPBootConfigSpec{NetworkIdentifiers: []string{"10.0.0.10", "server-a"}},
+			field: "spec.networkIdentifiers[1]",
+		},
+		{name: "relative UKIURL", spec: bootv1alpha1.HTTPBootConfigSpec{UKIURL: "uki.efi"}, field: "spec.ukiURL"},
+		{
+			name: "duplicate ignition layer",
+			spec: bootv1alpha1.HTTPBootConfigSpec{IgnitionLayers: []bootv1alpha1.IgnitionLayer{
+				{SecretRef: corev1.LocalObjectReference{Name: "base"}},
+				{SecretRef: corev1.LocalObjectReference{Name: "site"}},
+				{SecretRef: corev1.LocalObjectReference{Name: "base"}},
+			}},
+			field: "spec.ignitionLayers[2].secretRef.name",
+		},
+		{
+			name:  "unnamed ignition layer",
+			spec:  bootv1alpha1.HTTPBootConfigSpec{IgnitionLayers: []bootv1alpha1.IgnitionLayer{{}}},
+			field: "spec.ignitionLayers[0].secretRef.name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := &HTTPBootConfigCustomValidator{Client: newClient(t)}
+			_, err := validator.ValidateCreate(context.Background(), &bootv1alpha1.HTTPBootConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+				Spec:       tt.spec,
+			})
+			switch {
+			case tt.field == "" && err != nil:
+				t.Errorf("ValidateCreate() error = %v", err)
+			case tt.field != "" && (err == nil || !strings.Contains(err.Error(), tt.field)):
+				t.Errorf("ValidateCreate() error = %v, want an error for %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestHTTPBootConfigSystemUUIDCollision(t *testing.T) {
+	existing := &bootv1alpha1.HTTPBootConfig{ObjectMeta: ownedBy("server-a", "server-a"), Spec: bootv1alpha1.HTTPBootConfigSpec{SystemUUID: systemUUID}}
+	validator := &HTTPBootConfigCustomValidator{Client: newClient(t, existing)}
+
+	config := &bootv1alpha1.HTTPBootConfig{ObjectMeta: ownedBy("server-b", "server-b"), Spec: bootv1alpha1.HTTPBootConfigSpec{SystemUUID: systemUUID}}
+	if _, err := validator.ValidateCreate(context.Background(), config); err == nil || !strings.Contains(err.Error(), "spec.systemUUID") {
+		t.Errorf("ValidateCreate() error = %v, want a duplicate spec.systemUUID", err)
+	}
+
+	config = &bootv1alpha1.HTTPBootConfig{ObjectMeta: ownedBy("maintenance-a", "server-a"), Spec: bootv1alpha1.HTTPBootConfigSpec{SystemUUID: systemUUID}}
+	if _, err := validator.ValidateCreate(context.Background(), config); err != nil {
+		t.Errorf("ValidateCreate() for a config of the same Server error = %v", err)
+	}
+}
+
+func TestHTTPBootConfigDefaulting(t *testing.T) {
+	config := &bootv1alpha1.HTTPBootConfig{
+		Spec: bootv1alpha1.HTTPBootConfigSpec{
+			SystemUUID:         strings.ToUpper(systemUUID),
+			NetworkIdentifiers: []string{"::ffff:10.0.0.10", "52-54-00-AB-CD-EF"},
+		},
+	}
+	if err := (&HTTPBootConfigCustomDefaulter{}).Default(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Spec.SystemUUID != systemUUID {
+		t.Errorf("Default() SystemUUID = %q, want %q", config.Spec.SystemUUID, systemUUID)
+	}
+	if got, want := config.Spec.NetworkIdentifiers, []string{"10.0.0.10", "52:54:00:ab:cd:ef"}; !slices.Equal(got, want) {
+		t.Errorf("Default() NetworkIdentifiers = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/netid"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+)
+
+// log is for logging in this package.
+var ipxebootconfiglog = logf.Log.WithName("ipxebootconfig-resource")
+
+// SetupIPXEBootConfigWebhookWithManager registers the webhook for IPXEBootConfig in the manager.
+func SetupIPXEBootConfigWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &bootv1alpha1.IPXEBootConfig{}).
+		WithValidator(&IPXEBootConfigCustomValidator{Client: mgr.GetClient()}).
+		WithDefaulter(&IPXEBootConfigCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-boot-ironcore-dev-v1alpha1-ipxebootconfig,mutating=true,failurePolicy=fail,sideEffects=None,groups=boot.ironcore.dev,resources=ipxebootconfigs,verbs=create;update,versions=v1alpha1,name=mipxebootconfig-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// IPXEBootConfigCustomDefaulter normalizes the IPXEBootConfig resource when it is created or updated, so that
+// the boot-server can look it up by the canonical SystemUUID and network addresses.
+type IPXEBootConfigCustomDefaulter struct{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type IPXEBootConfig.
+func (d *IPXEBootConfigCustomDefaulter) Default(_ context.Context, obj *bootv1alpha1.IPXEBootConfig) error {
+	ipxebootconfiglog.Info("Defaulting for IPXEBootConfig", "name", obj.GetName())
+
+	obj.Spec.SystemUUID = systemuuid.Canonical(obj.Spec.SystemUUID)
+	obj.Spec.SystemIPs = netid.CanonicalAll(obj.Spec.SystemIPs)
+	obj.Spec.SystemMACs = netid.CanonicalAll(obj.Spec.SystemMACs)
+	return nil
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:path=/validate-boot-ironcore-dev-v1alpha1-ipxebootconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=boot.ironcore.dev,resources=ipxebootconfigs,verbs=create;update,versions=v1alpha1,name=vipxebootconfig-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// IPXEBootConfigCustomValidator validates the IPXEBootConfig resource when it is created or updated.
+type IPXEBootConfigCustomValidator struct {
+	Client client.Client
+}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type IPXEBootConfig.
+func (v *IPXEBootConfigCustomValidator) ValidateCreate(ctx context.Context, obj *bootv1alpha1.IPXEBootConfig) (admission.Warnings, error) {
+	ipxebootconfiglog.Info("Validation for IPXEBootConfig upon creation", "name", obj.GetName())
+
+	allErrs := validateIPXEBootConfigSpec(obj.Spec, field.NewPath("spec"))
+	allErrs = append(allErrs, v.validateSystemUUIDCollision(ctx, obj)...)
+	return nil, invalidIPXEBootConfig(obj, allErrs)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type IPXEBootConfig.
+func (v *IPXEBootConfigCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj *bootv1alpha1.IPXEBootConfig) (admission.Warnings, error) {
+	ipxebootconfiglog.Info("Validation for IPXEBootConfig upon update", "name", newObj.GetName())
+
+	allErrs := validateIPXEBootConfigSpec(newObj.Spec, field.NewPath("spec"))
+	if !sameSystemUUID(oldObj.Spec.SystemUUID, newObj.Spec.SystemUUID) {
+		allErrs = append(allErrs, v.validateSystemUUIDCollision(ctx, newObj)...)
+	}
+	return nil, invalidIPXEBootConfig(newObj, allErrs)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type IPXEBootConfig.
+func (v *IPXEBootConfigCustomValidator) ValidateDelete(_ context.Context, obj *bootv1alpha1.IPXEBootConfig) (admission.Warnings, error) {
+	ipxebootconfiglog.Info("Validation for IPXEBootConfig upon deletion", "name", obj.GetName())
+
+	return nil, nil
+}
+
+func (v *IPXEBootConfigCustomValidator) validateSystemUUIDCollision(ctx context.Context, config *bootv1alpha1.IPXEBootConfig) field.ErrorList {
+	return validateSystemUUIDCollision(ctx, v.Client, config, config.Spec.SystemUUID, &bootv1alpha1.IPXEBootConfigList{},
+		field.NewPath("spec", "systemUUID"))
+}
+
+func validateIPXEBootConfigSpec(spec bootv1alpha1.IPXEBootConfigSpec, path *field.Path) field.ErrorList {
+	allErrs := validateSystemUUID(spec.SystemUUID, path.Child("systemUUID"))
+	allErrs = append(allErrs, validateIPs(spec.SystemIPs, path.Child("systemIPs"))...)
+	allErrs = append(allErrs, validateMACs(spec.SystemMACs, path.Child("systemMACs"))...)
+	allErrs = append(allErrs, validateURL(spec.KernelURL, path.Child("kernelURL"))...)
+	allErrs = append(allErrs, validateURL(spec.InitrdURL, path.Child("initrdURL"))...)
+	allErrs = append(allErrs, validateURL(spec.SquashfsURL, path.Child("squashfsURL"))...)
+	allErrs = append(allErrs, validateIgnitionLayers(spec.IgnitionLayers, path.Child("ignitionLayers"))...)
+	allErrs = append(allErrs, validateKernelArgs(spec.KernelArgs, path.Child("kernelArgs"))...)
+	return allErrs
+}
+
+func invalidIPXEBootConfig(config *bootv1alpha1.IPXEBootConfig, allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(bootv1alpha1.GroupVersion.WithKind("IPXEBootConfig").GroupKind(), config.GetName(), allErrs)
+}
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const systemUUID = "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91"
+
+// newClient returns a fake client with Servers server-a and server-b, each referencing a workload
+// ServerBootConfiguration, and an orphaned ServerBootConfiguration of server-b.
+func newClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := bootv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := metalv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"server-a", "server-b"} {
+		objects = append(objects,
+			&metalv1alpha1.Server{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: metalv1alpha1.ServerSpec{
+					BootConfigurationRef: &metalv1alpha1.ObjectReference{Namespace: "default", Name: name},
+				},
+			},
+			&metalv1alpha1.ServerBootConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec:       metalv1alpha1.ServerBootConfigurationSpec{ServerRef: corev1.LocalObjectReference{Name: name}},
+			},
+		)
+	}
+	objects = append(objects, &metalv1alpha1.ServerBootConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "orphan", Namespace: "default"},
+		Spec:       metalv1alpha1.ServerBootConfigurationSpec{ServerRef: corev1.LocalObjectReference{Name: "server-b"}},
+	})
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+			return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+		}).
+		WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+			return []string{systemuuid.Canonical(obj.(*bootv1alpha1.HTTPBootConfig).Spec.SystemUUID)}
+		}).
+		Build()
+}
+
+// ownedBy returns the object metadata of a boot config owned by the given ServerBootConfiguration,
+// or of an unowned boot config if sbc is empty.
+func ownedBy(name, sbc string) metav1.ObjectMeta {
+	meta := metav1.ObjectMeta{Name: name, Namespace: "default"}
+	if sbc != "" {
+		meta.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: metalv1alpha1.GroupVersion.String(),
+			Kind:       "ServerBootConfiguration",
+			Name:       sbc,
+			Controller: ptr.To(true),
+		}}
+	}
+	return meta
+}
+
+func TestIPXEBootConfigSpecValidation(t *testing.T) {
+	valid := bootv1alpha1.IPXEBootConfigSpec{
+		SystemUUID:  systemUUID,
+		SystemIPs:   []string{"10.0.0.10", "2001:db8::10"},
+		SystemMACs:  []string{"52:54:00:ab:cd:ef"},
+		KernelURL:   "http://images.example.com/kernel",
+		InitrdURL:   "https://images.example.com/initrd",
+		SquashfsURL: "http://images.example.com/squashfs",
+		KernelArgs:  []string{"console=ttyS1,115200", "-earlyprintk", "nomodeset"},
+	}
+	tests := []struct {
+		name   string
+		modify func(spec *bootv1alpha1.IPXEBootConfigSpec)
+		field  string
+	}{
+		{name: "valid", modify: func(*bootv1alpha1.IPXEBootConfigSpec) {}},
+		{name: "invalid SystemUUID", modify: func(spec *bootv1alpha1.IPXEBootConfigSpec) { spec.SystemUUID = "not-a-uuid" }, field: "spec.systemUUID"},
+		{name: "invalid SystemIP", modify: func(spec *bootv1alpha1.IPXEBootConfigSpec) { spec.SystemIPs[1] = "10.0.0" }, field: "spec.systemIPs[1]"},
+		{name: "invalid SystemMAC", modify: func(spec *bootv1alpha1.IPXEBootConfigSpec) { spec.SystemMACs[0] = "52:54:00" }, field: "spec.systemMACs[0]"},
+		{name: "relative KernelURL", modify: func(spec *bootv1alpha1.IPXEBootConfigSpec) { spec.KernelURL = "/kernel" }, field: "spec.kernelURL"},
+		{name: "unsupported InitrdURL scheme", modify: func(spec *bootv1alpha1.IPXEBootConfigSpec) { spec.InitrdURL = "ftp://example.com/initrd" }, field: "spec.initrdURL"},
+		{name: "invalid SquashfsURL", modify: func(spec *bootv1alpha1.IPXEBootConfigSpec) { spec.SquashfsURL = "http://%zz" }, field: "spec.squashfsURL"},
+		{name: "kernel argument with whitespace", modify: func(spec *bootv1alpha1.IPXEBootConfigSpec) { spec.KernelArgs[0] = "console=ttyS1 quiet" }, field: "spec.kernelArgs[0]"},
+		{name: "empty kernel argument removal", modify: func(spec *bootv1alpha1.IPXEBootConfigSpec) { spec.KernelArgs[1] = "-" }, field: "spec.kernelArgs[1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := *valid.DeepCopy()
+			tt.modify(&spec)
+			validator := &IPXEBootConfigCustomValidator{Client: newClient(t)}
+			_, err := validator.ValidateCreate(context.Background(), &bootv1alpha1.IPXEBootConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"},
+				Spec:       spec,
+			})
+			switch {
+			case tt.field == "" && err != nil:
+				t.Errorf("ValidateCreate() error = %v", err)
+			case tt.field != "" && (err == nil || !strings.Contains(err.Error(), tt.field)):
+				t.Errorf("ValidateCreate() error = %v, want an error for %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestIPXEBootConfigSystemUUIDCollision(t *testing.T) {
+	ctx := context.Background()
+	existing := func(name, sbc, uuid string) *bootv1alpha1.IPXEBootConfig {
+		return &bootv1alpha1.IPXEBootConfig{
+			ObjectMeta: ownedBy(name, sbc),
+			Spec: bootv1alpha1.IPXEBootConfigSpec{
+				SystemUUID: uuid,
+				KernelURL:  "http://images.example.com/kernel",
+				InitrdURL:  "http://images.example.com/initrd",
+			},
+		}
+	}
+	validator := &IPXEBootConfigCustomValidator{Client: newClient(t,
+		existing("server-a", "server-a", strings.ToUpper(systemUUID)),
+		existing("orphan", "orphan", "8e1b4d7a-2c5f-4a9e-b3d6-7f0a1c4e8b25"),
+		existing("placeholder", "server-a", "00000000-0000-0000-0000-000000000000"),
+	)}
+
+	tests := []struct {
+		name    string
+		config  *bootv1alpha1.IPXEBootConfig
+		wantErr bool
+	}{
+		{name: "UUID of another Server", config: existing("server-b", "server-b", systemUUID), wantErr: true},
+		{name: "UUID of the same Server", config: existing("maintenance-a", "server-a", systemUUID)},
+		{name: "unowned config with the UUID of a Server", config: existing("manual", "", systemUUID), wantErr: true},
+		{name: "UUID of an orphaned config", config: existing("server-b", "server-b", "8e1b4d7a-2c5f-4a9e-b3d6-7f0a1c4e8b25")},
+		{name: "placeholder UUID", config: existing("server-b", "server-b", "00000000-0000-0000-0000-000000000000")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validator.ValidateCreate(ctx, tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Updates that keep the SystemUUID are not rejected for collisions that arose later.
+	config := existing("server-b", "server-b", systemUUID)
+	if _, err := validator.ValidateUpdate(ctx, config, config); err != nil {
+		t.Errorf("ValidateUpdate() error = %v", err)
+	}
+	updated := config.DeepCopy()
+	updated.Spec.SystemUUID = strings.ToUpper(systemUUID)
+	if _, err := validator.ValidateUpdate(ctx, config, updated); err != nil {
+		t.Errorf("ValidateUpdate() with a differently cased SystemUUID error = %v", err)
+	}
+	previous := existing("server-b", "server-b", "d3a7f1c2-6e4b-4f8a-9c2d-1b5e7a3f9d04")
+	if _, err := validator.ValidateUpdate(ctx, previous, config); err == nil {
+		t.Errorf("expected ValidateUpdate() to reject a changed SystemUUID of another Server")
+	}
+}
+
+func TestIPXEBootConfigDefaulting(t *testing.T) {
+	config := &bootv1alpha1.IPXEBootConfig{
+		Spec: bootv1alpha1.IPXEBootConfigSpec{
+			SystemUUID: "{" + strings.ToUpper(systemUUID) + "}",
+			SystemIPs:  []string{"::ffff:10.0.0.10", "2001:DB8::10", "not-an-ip"},
+			SystemMACs: []string{"52-54-00-AB-CD-EF"},
+		},
+	}
+	if err := (&IPXEBootConfigCustomDefaulter{}).Default(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+	want := bootv1alpha1.IPXEBootConfigSpec{
+		SystemUUID: systemUUID,
+		SystemIPs:  []string{"10.0.0.10", "2001:db8::10", "not-an-ip"},
+		SystemMACs: []string{"52:54:00:ab:cd:ef"},
+	}
+	if !equality.Semantic.DeepEqual(config.Spec, want) {
+		t.Errorf("Default() spec = %+v, want %+v", config.Spec, want)
+	}
+}
@@ -5,34 +5,33 @@ package server
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
 	"path"
-	"path/filepath"
 	"strings"
-	"text/template"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	butaneconfig "github.com/coreos/butane/config"
-	butanecommon "github.com/coreos/butane/config/common"
 	"github.com/go-logr/logr"
 	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/ignition"
+	"github.com/ironcore-dev/boot-operator/internal/ipxe"
 	"github.com/ironcore-dev/boot-operator/internal/registry"
 	"github.com/ironcore-dev/boot-operator/internal/uki"
 )
 
-type IPXETemplateData struct {
-	KernelURL     string
-	InitrdURL     string
-	SquashfsURL   string
-	RegistryURL   string
-	IPXEServerURL string
-}
+// IPXETemplateData is the data that iPXE script templates are rendered with.
+type IPXETemplateData = ipxe.TemplateData
+
+// macPathPrefix prefixes the MAC address in the paths of iPXE script and ignition requests of
+// servers that are looked up by MAC address instead of SystemUUID.
+const macPathPrefix = "mac/"
 
 var predefinedConditions = map[string]v1.Condition{
 	"IgnitionDataFetched": {
@@ -47,59 +46,76 @@ var predefinedConditions = map[string]v1.Condition{
 		Reason:  "IPXEScriptDelivered",
 		Message: "IPXE script has been successfully delivered to the client.",
 	},
+	bootv1alpha1.OSReadyCondition: {
+		Type:    bootv1alpha1.OSReadyCondition,
+		Status:  v1.ConditionTrue,
+		Reason:  "ReadyReported",
+		Message: "The booted OS has reported readiness.",
+	},
 }
 
 func RunBootServer(
 	ipxeServerAddr string,
 	ipxeServiceURL string,
+	ipxeTemplates *ipxe.Templates,
 	k8sClient client.Client,
 	log logr.Logger,
 	registryValidator *registry.Validator,
+	credentialStore *registry.CredentialStore,
 	defaultOCIImage string,
 	defaultUKIURL string,
 	imageServerURL string,
 	architecture string,
+	signerSource *SignerSource,
+	tlsOptions *TLSOptions,
+	caBundlePath string,
+	ignitionAuth IgnitionAuthOptions,
+	sourceIP SourceIPOptions,
 ) error {
 	http.HandleFunc("/ipxe/", func(w http.ResponseWriter, r *http.Request) {
-		handleIPXE(w, r, k8sClient, log, ipxeServiceURL)
+		handleIPXE(w, r, k8sClient, log, ipxeServiceURL, ipxeTemplates, signerSource, ignitionAuth, sourceIP)
 	})
 
 	http.HandleFunc("/httpboot", func(w http.ResponseWriter, r *http.Request) {
-		handleHTTPBoot(w, r, k8sClient, log, registryValidator, defaultOCIImage, defaultUKIURL, imageServerURL, architecture)
+		handleHTTPBoot(w, r, k8sClient, log, ipxeTemplates, registryValidator, credentialStore, defaultOCIImage, defaultUKIURL, imageServerURL, architecture,
+			sourceIP.TrustedProxies)
 	})
 
 	http.HandleFunc("/ignition/", func(w http.ResponseWriter, r *http.Request) {
-		uuid := path.Base(r.URL.Path)
-		if uuid == "" {
-			http.Error(w, "Bad Request: UUID is required", http.StatusBadRequest)
+		if rawMAC, ok := strings.CutPrefix(r.URL.Path, "/ignition/"+macPathPrefix); ok {
+			handleIgnitionByMAC(w, r, k8sClient, log, rawMAC, ignitionAuth, sourceIP)
 			return
 		}
 
-		ipxeBootConfigList := &bootv1alpha1.IPXEBootConfigList{}
-		err := k8sClient.List(r.Context(), ipxeBootConfigList, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: uuid})
-		if client.IgnoreNotFound(err) != nil {
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		if r.URL.Path == "/ignition/" {
+			handleIgnitionByAddress(w, r, k8sClient, log, ignitionAuth, sourceIP)
 			return
 		}
+		handleIgnition(w, r, k8sClient, log, path.Base(r.URL.Path), ignitionAuth, sourceIP)
+	})
 
-		if len(ipxeBootConfigList.Items) == 0 {
-			err := k8sClient.List(r.Context(), ipxeBootConfigList, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: strings.ToUpper(uuid)})
-			if client.IgnoreNotFound(err) != nil {
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
-		}
+	http.HandleFunc("/ignition", func(w http.ResponseWriter, r *http.Request) {
+		handleIgnitionByAddress(w, r, k8sClient, log, ignitionAuth, sourceIP)
+	})
 
-		if len(ipxeBootConfigList.Items) == 0 {
-			log.Info("No IPXEBootConfig found with given UUID. Trying HTTPBootConfig")
-			handleIgnitionHTTPBoot(w, r, k8sClient, log, uuid)
-		} else {
-			handleIgnitionIPXEBoot(w, r, k8sClient, log, uuid)
-		}
+	http.HandleFunc(noCloudPathPrefix, func(w http.ResponseWriter, r *http.Request) {
+		handleNoCloud(w, r, k8sClient, log, ignitionAuth, sourceIP)
+	})
+
+	http.HandleFunc("/ready/", func(w http.ResponseWriter, r *http.Request) {
+		handleReady(w, r, k8sClient, log, ignitionAuth, sourceIP)
 	})
 
+	if caBundlePath != "" {
+		for _, pattern := range []string{CABundlePEMPath, CABundleDERPath} {
+			http.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+				handleCABundle(w, r, caBundlePath, log)
+			})
+		}
+	}
+
 	log.Info("Starting boot server", "address", ipxeServerAddr)
-	if err := http.ListenAndServe(ipxeServerAddr, nil); err != nil {
+	if err := listenAndServe(ipxeServerAddr, nil, tlsOptions, log); err != nil {
 		log.Error(err, "failed to start boot server")
 		return err
 	}
@@ -107,7 +123,8 @@ func RunBootServer(
 	return nil
 }
 
-func handleIPXE(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, ipxeServiceURL string) {
+func handleIPXE(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, ipxeServiceURL string,
+	ipxeTemplates *ipxe.Templates, signerSource *SignerSource, ignitionAuth IgnitionAuthOptions, sourceIP SourceIPOptions) {
 	log.Info("Processing IPXE request", "method", r.Method, "path", r.URL.Path, "clientIP", r.RemoteAddr)
 	if ipxeServiceURL == "" {
 		http.Error(w, "iPXE is disabled", http.StatusServiceUnavailable)
@@ -115,62 +132,132 @@ func handleIPXE(w http.ResponseWriter, r *http.Request, k8sClient client.Client,
 	}
 	ctx := r.Context()
 
-	uuid := strings.TrimPrefix(r.URL.Path, "/ipxe/")
+	uuid, signature := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/ipxe/"), signatureSuffix)
+	if signature && (signerSource == nil || uuid == "") {
+		http.Error(w, "Resource Not Found", http.StatusNotFound)
+		return
+	}
 	if uuid == "" {
-		serveDefaultIPXEChainTemplate(w, log, IPXETemplateData{
+		script, err := ipxeTemplates.Render(ipxe.ChainloadTemplate, IPXETemplateData{
 			IPXEServerURL: ipxeServiceURL,
+			Signed:        signerSource != nil,
 		})
+		if err != nil {
+			log.Info("Failed to render iPXE Chainload template", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if _, err := w.Write(script); err != nil {
+			log.Info("Failed to write iPXE Chainload script", "error", err)
+		}
 		return
 	}
 
 	ipxeBootConfigList := &bootv1alpha1.IPXEBootConfigList{}
-	err := k8sClient.List(ctx, ipxeBootConfigList, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: uuid})
+	var macAddress string
+	var err error
+	if rawMAC, ok := strings.CutPrefix(uuid, macPathPrefix); ok {
+		mac, parseErr := net.ParseMAC(rawMAC)
+		if parseErr != nil {
+			http.Error(w, "Bad Request: invalid MAC address", http.StatusBadRequest)
+			return
+		}
+		macAddress = mac.String()
+		err = k8sClient.List(ctx, ipxeBootConfigList, client.MatchingFields{bootv1alpha1.SystemMACIndexKey: macAddress})
+	} else {
+		err = listBySystemUUID(ctx, k8sClient, ipxeBootConfigList, uuid)
+	}
 	if client.IgnoreNotFound(err) != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	if len(ipxeBootConfigList.Items) == 0 {
-		log.Info("No IPXEBootConfig found for the given UUID")
+	ipxeBootConfigs := toPointers(ipxeBootConfigList.Items)
+	if macAddress == "" {
+		if ipxeBootConfigs, err = matchAmbiguousSystemUUID(r, log, ipxeBootConfigs, sourceIP.TrustedProxies); err != nil {
+			log.Info("Failed to match IPXEBootConfigs of an ambiguous SystemUUID", "error", err.Error())
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if len(ipxeBootConfigs) == 0 {
+		log.Info("No IPXEBootConfig found for the given UUID or MAC address")
 		http.Error(w, "Resource Not Found", http.StatusNotFound)
 		return
 	}
 
-	config, err := selectBootConfig(ctx, k8sClient, log, toPointers(ipxeBootConfigList.Items))
+	config, err := selectBootConfig(ctx, k8sClient, log, ipxeBootConfigs)
 	if err != nil {
 		log.Error(err, "Failed to select IPXEBootConfig")
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
-	if config.Spec.IPXEScriptSecretRef != nil {
-		secret := &corev1.Secret{}
-		err := k8sClient.Get(ctx, types.NamespacedName{Name: config.Spec.IPXEScriptSecretRef.Name, Namespace: config.Namespace}, secret)
-		if err != nil {
-			log.Error(err, "Failed to fetch IPXE script from secret", "SecretName", config.Spec.IPXEScriptSecretRef.Name)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
-		}
 
-		ipxeScript, exists := secret.Data[bootv1alpha1.DefaultIPXEScriptKey]
-		if !exists {
-			log.Info("IPXE script not found in the secret", "ExpectedKey", bootv1alpha1.DefaultIPXEScriptKey)
+	// The script carries the ignition token, so it is only handed out to the server itself.
+	if ignitionAuth.RequireToken {
+		sourceIP.Verify = true
+	}
+	if err := verifySourceAddress(r, sourceIP, log, config, config.Spec.SystemIPs); err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if ignitionAuth.RequireToken && !signature {
+		if err := rotateIgnitionToken(ctx, k8sClient, config, ignitionAuth.TokenTTL); err != nil {
+			log.Error(err, "Failed to rotate ignition token")
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
-
-		if _, err := w.Write(ipxeScript); err != nil {
-			log.Info("Failed to write custom IPXE script", "error", err)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		}
-		return
 	}
 
-	serveDefaultIPXETemplate(w, log, IPXETemplateData{
+	data := IPXETemplateData{
 		KernelURL:     config.Spec.KernelURL,
 		InitrdURL:     config.Spec.InitrdURL,
 		SquashfsURL:   config.Spec.SquashfsURL,
 		IPXEServerURL: ipxeServiceURL,
-	})
+		SystemUUID:    config.Spec.SystemUUID,
+		MACAddress:    macAddress,
+		KernelArgs:    config.Spec.KernelArgs,
+	}
+	if token := config.Status.IgnitionToken; token != nil {
+		data.IgnitionToken = token.Token
+	}
+	if data.NoCloud, err = usesNoCloud(ctx, k8sClient, config.Namespace, config.Spec.IgnitionSecretRef); err != nil {
+		log.Error(err, "Failed to determine the ignition format")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if signerSource != nil {
+		data.Signed = true
+		data.KernelSignatureURL = signatureURL(config.Spec.KernelURL)
+		data.InitrdSignatureURL = signatureURL(config.Spec.InitrdURL)
+	}
+
+	var ipxeScript []byte
+	switch {
+	case config.Spec.IPXEScriptSecretRef != nil:
+		ipxeScript, err = customIPXEScript(ctx, k8sClient, config.Namespace, config.Spec.IPXEScriptSecretRef.Name, data)
+	case config.Spec.IPXETemplateRef != nil:
+		ipxeScript, err = renderConfigMapIPXETemplate(ctx, k8sClient, config.Namespace, config.Spec.IPXETemplateRef.Name, data)
+	default:
+		ipxeScript, err = ipxeTemplates.Render(ipxe.ScriptTemplate, data)
+	}
+	if err != nil {
+		log.Error(err, "Failed to render iPXE script")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	// The signature is fetched right after the script, so only the script marks it as delivered.
+	if signature {
+		serveSignature(w, r, log, signerSource, ipxeScript)
+		return
+	}
+
+	if _, err := w.Write(ipxeScript); err != nil {
+		log.Info("Failed to write IPXE script", "error", err)
+		return
+	}
 
 	err = SetStatusCondition(ctx, k8sClient, log, config, "IPXEScriptFetched")
 	if err != nil {
@@ -178,74 +265,179 @@ func handleIPXE(w http.ResponseWriter, r *http.Request, k8sClient client.Client,
 	}
 }
 
-func handleIgnitionIPXEBoot(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, uuid string) {
+// handleIgnition serves the ignition data of the IPXEBootConfig or, if there is none, the
+// HTTPBootConfig with the given SystemUUID.
+func handleIgnition(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, uuid string,
+	ignitionAuth IgnitionAuthOptions, sourceIP SourceIPOptions) {
+	ipxeBootConfigList := &bootv1alpha1.IPXEBootConfigList{}
+	err := listBySystemUUID(r.Context(), k8sClient, ipxeBootConfigList, uuid)
+	if client.IgnoreNotFound(err) != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if len(ipxeBootConfigList.Items) == 0 {
+		log.Info("No IPXEBootConfig found with given UUID. Trying HTTPBootConfig")
+		handleIgnitionHTTPBoot(w, r, k8sClient, log, uuid, ignitionAuth, sourceIP)
+	} else {
+		handleIgnitionIPXEBoot(w, r, k8sClient, log, uuid, ignitionAuth, sourceIP)
+	}
+}
+
+func handleIgnitionIPXEBoot(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, uuid string,
+	ignitionAuth IgnitionAuthOptions, sourceIP SourceIPOptions) {
 	log.Info("Processing Ignition request", "method", r.Method, "path", r.URL.Path, "clientIP", r.RemoteAddr)
 	ctx := r.Context()
 
 	ipxeBootConfigList := &bootv1alpha1.IPXEBootConfigList{}
-	if err := k8sClient.List(ctx, ipxeBootConfigList, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: uuid}); err != nil {
+	if err := listBySystemUUID(ctx, k8sClient, ipxeBootConfigList, uuid); err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		log.Info("Failed to find IPXEBootConfig", "error", err.Error())
 		return
 	}
 
-	if len(ipxeBootConfigList.Items) == 0 {
-		//Some OSes standardizes SystemUUIDs to uppercase, which may cause mismatches.
-		if err := k8sClient.List(ctx, ipxeBootConfigList, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: strings.ToUpper(uuid)}); err != nil {
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			log.Info("Failed to find IPXEBootConfig", "error", err.Error())
-			return
-		}
+	ipxeBootConfigs, err := matchAmbiguousSystemUUID(r, log, toPointers(ipxeBootConfigList.Items), sourceIP.TrustedProxies)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		log.Info("Failed to match IPXEBootConfigs of an ambiguous SystemUUID", "error", err.Error())
+		return
 	}
 
-	if len(ipxeBootConfigList.Items) == 0 {
-		//Some OSes standardizes SystemUUIDs to lowercase, which may cause mismatches.
-		if err := k8sClient.List(ctx, ipxeBootConfigList, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: strings.ToLower(uuid)}); err != nil {
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			log.Info("Failed to find IPXEBootConfig", "error", err.Error())
-			return
-		}
+	if len(ipxeBootConfigs) == 0 {
+		http.Error(w, "Resource Not Found", http.StatusNotFound)
+		log.Info("No IPXEBootConfig found with given UUID")
+		return
 	}
 
-	if len(ipxeBootConfigList.Items) == 0 {
+	serveIPXEBootIgnition(w, r, k8sClient, log, ipxeBootConfigs, ignitionAuth, sourceIP)
+}
+
+// handleIgnitionByMAC serves the ignition data of the IPXEBootConfig with the given MAC address
+// among its SystemMACs, for servers whose iPXE script has been looked up by MAC address. If there is
+// none, it serves the HTTPBootConfig with the MAC address among its NetworkIdentifiers.
+func handleIgnitionByMAC(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, rawMAC string,
+	ignitionAuth IgnitionAuthOptions, sourceIP SourceIPOptions) {
+	log.Info("Processing Ignition request", "method", r.Method, "path", r.URL.Path, "clientIP", r.RemoteAddr)
+	ctx := r.Context()
+
+	mac, err := net.ParseMAC(rawMAC)
+	if err != nil {
+		http.Error(w, "Bad Request: invalid MAC address", http.StatusBadRequest)
+		return
+	}
+
+	ipxeBootConfigList := &bootv1alpha1.IPXEBootConfigList{}
+	if err := k8sClient.List(ctx, ipxeBootConfigList, client.MatchingFields{bootv1alpha1.SystemMACIndexKey: mac.String()}); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		log.Info("Failed to find IPXEBootConfig", "error", err.Error())
+		return
+	}
+
+	if len(ipxeBootConfigList.Items) > 0 {
+		serveIPXEBootIgnition(w, r, k8sClient, log, toPointers(ipxeBootConfigList.Items), ignitionAuth, sourceIP)
+		return
+	}
+
+	httpBootConfigList := &bootv1alpha1.HTTPBootConfigList{}
+	if err := k8sClient.List(ctx, httpBootConfigList, client.MatchingFields{bootv1alpha1.NetworkIdentifierIndexKey: mac.String()}); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		log.Info("Failed to find HTTPBootConfig", "error", err.Error())
+		return
+	}
+
+	if len(httpBootConfigList.Items) == 0 {
 		http.Error(w, "Resource Not Found", http.StatusNotFound)
-		log.Info("No IPXEBootConfig found with given UUID")
+		log.Info("No IPXEBootConfig or HTTPBootConfig found with given MAC address")
 		return
 	}
 
-	ipxeBootConfig, err := selectBootConfig(ctx, k8sClient, log, toPointers(ipxeBootConfigList.Items))
+	serveHTTPBootIgnition(w, r, k8sClient, log, toPointers(httpBootConfigList.Items), ignitionAuth, sourceIP)
+}
+
+// handleIgnitionByAddress serves the ignition data of the boot config of the client, for servers
+// that fetch their ignition data without a SystemUUID, e.g. from a UKI booted over UEFI HTTP boot.
+// The client is identified by its source address, which is looked up among the SystemIPs of the
+// IPXEBootConfigs and, if none matches, the NetworkIdentifiers of the HTTPBootConfigs.
+func handleIgnitionByAddress(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger,
+	ignitionAuth IgnitionAuthOptions, sourceIP SourceIPOptions) {
+	log.Info("Processing Ignition request", "method", r.Method, "path", r.URL.Path, "clientIP", r.RemoteAddr)
+	ctx := r.Context()
+
+	clientIP, err := clientAddress(r, sourceIP.TrustedProxies)
+	if err != nil {
+		log.Info("Failed to determine the client address", "error", err.Error())
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	ipxeBootConfigList := &bootv1alpha1.IPXEBootConfigList{}
+	if err := k8sClient.List(ctx, ipxeBootConfigList, client.MatchingFields{bootv1alpha1.SystemIPIndexKey: clientIP.String()}); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		log.Info("Failed to find IPXEBootConfig", "error", err.Error())
+		return
+	}
+
+	if len(ipxeBootConfigList.Items) > 0 {
+		serveIPXEBootIgnition(w, r, k8sClient, log, toPointers(ipxeBootConfigList.Items), ignitionAuth, sourceIP)
+		return
+	}
+
+	httpBootConfigList := &bootv1alpha1.HTTPBootConfigList{}
+	if err := k8sClient.List(ctx, httpBootConfigList, client.MatchingFields{bootv1alpha1.NetworkIdentifierIndexKey: clientIP.String()}); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		log.Info("Failed to find HTTPBootConfig", "error", err.Error())
+		return
+	}
+
+	if len(httpBootConfigList.Items) == 0 {
+		http.Error(w, "Resource Not Found", http.StatusNotFound)
+		log.Info("No IPXEBootConfig or HTTPBootConfig found for client IP", "clientIP", clientIP)
+		return
+	}
+
+	serveHTTPBootIgnition(w, r, k8sClient, log, toPointers(httpBootConfigList.Items), ignitionAuth, sourceIP)
+}
+
+// serveIPXEBootIgnition serves the ignition data of the preferred one of the IPXEBootConfigs
+// matching an ignition request.
+func serveIPXEBootIgnition(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger,
+	ipxeBootConfigs []*bootv1alpha1.IPXEBootConfig, ignitionAuth IgnitionAuthOptions, sourceIP SourceIPOptions) {
+	ctx := r.Context()
+
+	ipxeBootConfig, err := selectBootConfig(ctx, k8sClient, log, ipxeBootConfigs)
 	if err != nil {
 		log.Error(err, "Failed to select IPXEBootConfig")
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	ignitionSecret := corev1.Secret{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      ipxeBootConfig.Spec.IgnitionSecretRef.Name,
-			Namespace: ipxeBootConfig.Namespace,
-		},
+	if err := verifySourceAddress(r, sourceIP, log, ipxeBootConfig, ipxeBootConfig.Spec.SystemIPs); err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if err := authorizeIgnitionRequest(r, ignitionAuth, ipxeBootConfig.Status.IgnitionToken, ipxeBootConfig.Spec.SystemIPs, sourceIP.TrustedProxies, time.Now()); err != nil {
+		log.Info("Rejected ignition request", "config", client.ObjectKeyFromObject(ipxeBootConfig), "reason", err.Error())
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
 	}
-	ignitionData, ignitionFormat, err := fetchIgnitionData(ctx, k8sClient, ignitionSecret)
+
+	ignitionJSONData, contentType, status, err := renderBootConfigIgnition(ctx, k8sClient, log, ipxeBootConfig)
 	if err != nil {
-		http.Error(w, "Resource Not Found", http.StatusNotFound)
-		log.Info("Failed to fetch IgnitionData", "error", err.Error())
+		log.Info("Failed to render the ignition data", "config", client.ObjectKeyFromObject(ipxeBootConfig), "error", err.Error())
+		http.Error(w, http.StatusText(status), status)
 		return
 	}
 
-	var ignitionJSONData []byte
-	switch strings.TrimSpace(ignitionFormat) {
-	case bootv1alpha1.FCOSFormat:
-		ignitionJSONData, err = renderIgnition(ignitionData)
-		if err != nil {
-			log.Info("Failed to render the ignition data to json", "error", err)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
-		}
-	default:
-		ignitionJSONData = ignitionData
+	if status, err = enforceIgnitionPolicy(ipxeBootConfig, ipxeBootConfig.Spec.IgnitionPolicy); err != nil {
+		log.Info("Refused ignition request", "config", client.ObjectKeyFromObject(ipxeBootConfig), "reason", err.Error())
+		http.Error(w, http.StatusText(status), status)
+		return
 	}
 
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
 	w.WriteHeader(http.StatusOK)
 	_, err = w.Write(ignitionJSONData)
 	if err != nil {
@@ -254,110 +446,110 @@ func handleIgnitionIPXEBoot(w http.ResponseWriter, r *http.Request, k8sClient cl
 		return
 	}
 
-	err = SetStatusCondition(ctx, k8sClient, log, ipxeBootConfig, "IgnitionDataFetched")
+	err = recordIgnitionDelivery(ctx, k8sClient, log, ipxeBootConfig, ipxeBootConfig.Spec.IgnitionPolicy)
 	if err != nil {
 		log.Error(err, "Failed to set IgnitionDataFetched status condition")
 	}
 }
 
-func serveDefaultIPXETemplate(w http.ResponseWriter, log logr.Logger, data IPXETemplateData) {
-	tmplPath := filepath.Join("templates", "ipxe-script.tpl")
-	tmpl, err := template.ParseFiles(tmplPath)
-	if err != nil {
-		log.Info("Failed to parse iPXE script template", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
+// customIPXEScript returns the custom iPXE script stored in a Secret, rendered as a template if the
+// Secret enables it.
+func customIPXEScript(ctx context.Context, k8sClient client.Client, namespace, name string, data IPXETemplateData) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get iPXE script Secret %s: %w", name, err)
 	}
-
-	if err := tmpl.Execute(w, data); err != nil {
-		log.Info("Failed to execute template", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	script, ok := secret.Data[bootv1alpha1.DefaultIPXEScriptKey]
+	if !ok {
+		return nil, fmt.Errorf("iPXE script Secret %s has no %s key", name, bootv1alpha1.DefaultIPXEScriptKey)
+	}
+	if !ipxe.IsTemplate(secret) {
+		return script, nil
 	}
+	return ipxe.Render(string(script), data)
 }
 
-func serveDefaultIPXEChainTemplate(w http.ResponseWriter, log logr.Logger, data IPXETemplateData) {
-	tmplPath := filepath.Join("templates", "ipxe-chainload.tpl")
-	tmpl, err := template.ParseFiles(tmplPath)
-	if err != nil {
-		log.Info("Failed to parse iPXE Chainload template", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
+// renderConfigMapIPXETemplate renders the iPXE script template stored in a ConfigMap.
+func renderConfigMapIPXETemplate(ctx context.Context, k8sClient client.Client, namespace, name string, data IPXETemplateData) ([]byte, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, configMap); err != nil {
+		return nil, fmt.Errorf("failed to get iPXE template ConfigMap %s: %w", name, err)
 	}
-
-	if err := tmpl.Execute(w, data); err != nil {
-		log.Info("Failed to execute iPXE Chainload template", "error", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	text, ok := configMap.Data[bootv1alpha1.DefaultIPXETemplateKey]
+	if !ok {
+		return nil, fmt.Errorf("iPXE template ConfigMap %s has no %s key", name, bootv1alpha1.DefaultIPXETemplateKey)
 	}
+	return ipxe.Render(text, data)
 }
 
-func handleIgnitionHTTPBoot(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, uuid string) {
+func handleIgnitionHTTPBoot(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, uuid string,
+	ignitionAuth IgnitionAuthOptions, sourceIP SourceIPOptions) {
 	log.Info("Processing Ignition request", "method", r.Method, "path", r.URL.Path, "clientIP", r.RemoteAddr)
 	ctx := r.Context()
 
 	HTTPBootConfigList := &bootv1alpha1.HTTPBootConfigList{}
-	if err := k8sClient.List(ctx, HTTPBootConfigList, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: uuid}); err != nil {
+	if err := listBySystemUUID(ctx, k8sClient, HTTPBootConfigList, uuid); err != nil {
 		http.Error(w, "Resource Not Found", http.StatusNotFound)
 		log.Info("Failed to find HTTPBootConfigList", "error", err.Error())
 		return
 	}
 
-	if len(HTTPBootConfigList.Items) == 0 {
-		//Some OSes standardizes SystemUUIDs to uppercase, which may cause mismatches.
-		if err := k8sClient.List(ctx, HTTPBootConfigList, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: strings.ToUpper(uuid)}); err != nil {
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			log.Info("Failed to find HTTPBootConfigList", "error", err.Error())
-			return
-		}
-	}
-
-	if len(HTTPBootConfigList.Items) == 0 {
-		//Some OSes standardizes SystemUUIDs to lowecase, which may cause mismatches.
-		if err := k8sClient.List(ctx, HTTPBootConfigList, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: strings.ToLower(uuid)}); err != nil {
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			log.Info("Failed to find HTTPBootConfigList", "error", err.Error())
-			return
-		}
+	httpBootConfigs, err := matchAmbiguousSystemUUID(r, log, toPointers(HTTPBootConfigList.Items), sourceIP.TrustedProxies)
+	if err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		log.Info("Failed to match HTTPBootConfigs of an ambiguous SystemUUID", "error", err.Error())
+		return
 	}
 
-	if len(HTTPBootConfigList.Items) == 0 {
+	if len(httpBootConfigs) == 0 {
 		http.Error(w, "Resource Not Found", http.StatusNotFound)
 		log.Info("No HTTPBootConfig found with given UUID")
 		return
 	}
 
-	httpBootConfig, err := selectBootConfig(ctx, k8sClient, log, toPointers(HTTPBootConfigList.Items))
+	serveHTTPBootIgnition(w, r, k8sClient, log, httpBootConfigs, ignitionAuth, sourceIP)
+}
+
+// serveHTTPBootIgnition serves the ignition data of the preferred one of the HTTPBootConfigs
+// matching an ignition request.
+func serveHTTPBootIgnition(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger,
+	httpBootConfigs []*bootv1alpha1.HTTPBootConfig, ignitionAuth IgnitionAuthOptions, sourceIP SourceIPOptions) {
+	ctx := r.Context()
+
+	httpBootConfig, err := selectBootConfig(ctx, k8sClient, log, httpBootConfigs)
 	if err != nil {
 		log.Error(err, "Failed to select HTTPBootConfig")
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	ignitionSecret := corev1.Secret{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      httpBootConfig.Spec.IgnitionSecretRef.Name,
-			Namespace: httpBootConfig.Namespace,
-		},
+	if err := verifySourceAddress(r, sourceIP, log, httpBootConfig, httpBootConfig.Spec.NetworkIdentifiers); err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
 	}
-	ignitionData, ignitionFormat, err := fetchIgnitionData(ctx, k8sClient, ignitionSecret)
+
+	if err := authorizeIgnitionRequest(r, ignitionAuth, httpBootConfig.Status.IgnitionToken, httpBootConfig.Spec.NetworkIdentifiers, sourceIP.TrustedProxies, time.Now()); err != nil {
+		log.Info("Rejected ignition request", "config", client.ObjectKeyFromObject(httpBootConfig), "reason", err.Error())
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ignitionJSONData, contentType, status, err := renderBootConfigIgnition(ctx, k8sClient, log, httpBootConfig)
 	if err != nil {
-		http.Error(w, "Resource Not Found", http.StatusNotFound)
-		log.Info("Failed to fetch IgnitionData", "error", err.Error())
+		log.Info("Failed to render the ignition data", "config", client.ObjectKeyFromObject(httpBootConfig), "error", err.Error())
+		http.Error(w, http.StatusText(status), status)
 		return
 	}
 
-	var ignitionJSONData []byte
-	switch strings.TrimSpace(ignitionFormat) {
-	case bootv1alpha1.FCOSFormat:
-		ignitionJSONData, err = renderIgnition(ignitionData)
-		if err != nil {
-			log.Info("Failed to render the ignition data to json", "error", err)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
-		}
-	default:
-		ignitionJSONData = ignitionData
+	if status, err = enforceIgnitionPolicy(httpBootConfig, httpBootConfig.Spec.IgnitionPolicy); err != nil {
+		log.Info("Refused ignition request", "config", client.ObjectKeyFromObject(httpBootConfig), "reason", err.Error())
+		http.Error(w, http.StatusText(status), status)
+		return
 	}
 
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
 	w.WriteHeader(http.StatusOK)
 	_, err = w.Write(ignitionJSONData)
 	if err != nil {
@@ -366,39 +558,110 @@ func handleIgnitionHTTPBoot(w http.ResponseWriter, r *http.Request, k8sClient cl
 		return
 	}
 
-	err = SetStatusCondition(ctx, k8sClient, log, httpBootConfig, "IgnitionDataFetched")
+	err = recordIgnitionDelivery(ctx, k8sClient, log, httpBootConfig, httpBootConfig.Spec.IgnitionPolicy)
 	if err != nil {
 		log.Error(err, "Failed to set IgnitionDataFetched status condition")
 	}
 }
 
-func fetchIgnitionData(ctx context.Context, k8sClient client.Client, ignitionSecret corev1.Secret) ([]byte, string, error) {
+// renderBootConfigIgnition renders the ignition data of a boot config into the document served to
+// the booted OS, and returns the content type of the document. If the boot config has ignition
+// layers, the ignition data of its ignition Secret is merged onto them. On failure, it returns the
+// HTTP status to respond with.
+func renderBootConfigIgnition(ctx context.Context, k8sClient client.Client, log logr.Logger, config client.Object) ([]byte, string, int, error) {
+	_, _, secretRef := bootConfigIgnition(config)
+	layers := bootConfigIgnitionLayers(config)
+	if len(layers) == 0 {
+		if secretRef == nil {
+			return nil, "", http.StatusNotFound, errors.New("boot config has no ignition Secret")
+		}
+		layer, status, err := loadIgnitionLayer(ctx, k8sClient, config, secretRef.Name)
+		if err != nil {
+			return nil, "", status, err
+		}
+		rendered, contentType, err := renderIgnition(log, layer.Data, layer.Format)
+		if err != nil {
+			return nil, "", http.StatusInternalServerError, fmt.Errorf("failed to render the %q data: %w", layer.Format, err)
+		}
+		return rendered, contentType, http.StatusOK, nil
+	}
+
+	var sources []ignition.Layer
+	for _, layer := range layers {
+		source, status, err := loadIgnitionLayer(ctx, k8sClient, config, layer.SecretRef.Name)
+		if err != nil {
+			return nil, "", status, err
+		}
+		source.Replace = layer.Strategy == bootv1alpha1.IgnitionLayerStrategyReplace
+		sources = append(sources, source)
+	}
+	if secretRef != nil {
+		source, status, err := loadIgnitionLayer(ctx, k8sClient, config, secretRef.Name)
+		if err != nil {
+			return nil, "", status, err
+		}
+		sources = append(sources, source)
+	}
+	merged, err := ignition.MergeLayers(sources)
+	if err != nil {
+		return nil, "", http.StatusInternalServerError, fmt.Errorf("failed to merge the ignition layers: %w", err)
+	}
+	return merged, "application/json", http.StatusOK, nil
+}
+
+// loadIgnitionLayer returns the ignition data of a Secret of a boot config, rendered as a template
+// if the Secret enables it. On failure, it returns the HTTP status to respond with.
+func loadIgnitionLayer(ctx context.Context, k8sClient client.Client, config client.Object, secretName string) (ignition.Layer, int, error) {
+	ignitionSecret := corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: secretName, Namespace: config.GetNamespace()}}
+	data, format, templated, err := fetchIgnitionData(ctx, k8sClient, ignitionSecret)
+	if err != nil {
+		return ignition.Layer{}, http.StatusNotFound, err
+	}
+	if templated {
+		if data, err = renderIgnitionTemplate(ctx, k8sClient, config, data); err != nil {
+			return ignition.Layer{}, http.StatusInternalServerError, fmt.Errorf("failed to render the template of Secret %s: %w", secretName, err)
+		}
+	}
+	return ignition.Layer{Name: secretName, Data: data, Format: format}, http.StatusOK, nil
+}
+
+// fetchIgnitionData returns the ignition data of the ignition Secret, its format, and whether it is
+// to be rendered as a template.
+func fetchIgnitionData(ctx context.Context, k8sClient client.Client, ignitionSecret corev1.Secret) ([]byte, string, bool, error) {
 	secretObj := &corev1.Secret{}
 	if err := k8sClient.Get(ctx, client.ObjectKey{Name: ignitionSecret.Name, Namespace: ignitionSecret.Namespace}, secretObj); err != nil {
-		return nil, "", fmt.Errorf("failed to get the Ignition Secret %w", err)
+		return nil, "", false, fmt.Errorf("failed to get the Ignition Secret %w", err)
 	}
 	ignitionData, ok := secretObj.Data[bootv1alpha1.DefaultIgnitionKey]
 	if !ok {
-		return nil, "", fmt.Errorf("secret data-key:ignition not found")
+		return nil, "", false, fmt.Errorf("secret data-key:ignition not found")
 	}
-	return ignitionData, string(secretObj.Data[bootv1alpha1.DefaultFormatKey]), nil
+	return ignitionData, string(secretObj.Data[bootv1alpha1.DefaultFormatKey]), ignition.IsTemplate(secretObj), nil
 }
 
-func renderIgnition(yamlData []byte) ([]byte, error) {
-	translateOptions := butanecommon.TranslateBytesOptions{
-		Raw:    true,
-		Pretty: false,
-		TranslateOptions: butanecommon.TranslateOptions{
-			NoResourceAutoCompression: true,
-		},
+// renderIgnitionTemplate renders templated data of the ignition Secret of a boot config with the
+// TemplateData of the boot config.
+func renderIgnitionTemplate(ctx context.Context, k8sClient client.Client, config client.Object, data []byte) ([]byte, error) {
+	values, err := ignition.LoadTemplateData(ctx, k8sClient, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the template data: %w", err)
 	}
+	return ignition.RenderTemplate(data, values)
+}
 
-	jsonData, _, err := butaneconfig.TranslateBytes(yamlData, translateOptions)
+// renderIgnition renders ignition data according to its format into the document served to the
+// booted OS, and returns the content type of the document. Data of an unknown format is served as is.
+func renderIgnition(log logr.Logger, data []byte, format string) ([]byte, string, error) {
+	f, ok := ignition.LookupFormat(format)
+	if !ok {
+		log.Info("Unknown ignition format, serving the data as is", "format", format)
+		return data, "", nil
+	}
+	rendered, err := f.Render(data)
 	if err != nil {
-		return nil, fmt.Errorf("translation error from butane %w", err)
+		return nil, "", err
 	}
-
-	return jsonData, nil
+	return rendered, f.ContentType, nil
 }
 
 func handleHTTPBoot(
@@ -406,42 +669,40 @@ func handleHTTPBoot(
 	r *http.Request,
 	k8sClient client.Client,
 	log logr.Logger,
+	ipxeTemplates *ipxe.Templates,
 	registryValidator *registry.Validator,
+	credentialStore *registry.CredentialStore,
 	defaultOCIImage string,
 	defaultUKIURL string,
 	imageServerURL string,
 	architecture string,
+	trustedProxies []netip.Prefix,
 ) {
 	log.Info("Processing HTTPBoot request", "method", r.Method, "path", r.URL.Path, "clientIP", r.RemoteAddr)
 	ctx := r.Context()
 
-	var clientIPs []string
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		for _, ip := range strings.Split(xff, ",") {
-			trimmedIP := strings.TrimSpace(ip)
-			if trimmedIP != "" {
-				clientIPs = append(clientIPs, trimmedIP)
-			}
-		}
-		log.Info("X-Forwarded-For Header Found in the Request", "method", r.Method, "path", r.URL.Path, "clientIPs", clientIPs)
+	format, err := negotiateHTTPBootFormat(r)
+	if err != nil {
+		log.Info("Failed to negotiate the HTTPBoot response format", "error", err.Error())
+		http.Error(w, err.Error(), httpBootFormatStatus(err))
+		return
 	}
 
-	var httpBootConfigs bootv1alpha1.HTTPBootConfigList
-	for _, ip := range clientIPs {
-		if err := k8sClient.List(ctx, &httpBootConfigs, client.MatchingFields{bootv1alpha1.NetworkIdentifierIndexKey: ip}); err != nil {
-			log.Info("Failed to list HTTPBootConfig for IP", "IP", ip, "error", err)
-			continue
-		}
+	clientIP, err := clientAddress(r, trustedProxies)
+	if err != nil {
+		log.Info("Failed to determine the client address", "error", err.Error())
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
 
-		if len(httpBootConfigs.Items) > 0 {
-			log.Info("Found HTTPBootConfig", "IP", ip)
-			break
-		}
+	var httpBootConfigs bootv1alpha1.HTTPBootConfigList
+	if err := k8sClient.List(ctx, &httpBootConfigs, client.MatchingFields{bootv1alpha1.NetworkIdentifierIndexKey: clientIP.String()}); err != nil {
+		log.Info("Failed to list HTTPBootConfig for IP", "IP", clientIP, "error", err)
 	}
 
 	var httpBootResponseData map[string]string
 	if len(httpBootConfigs.Items) == 0 {
-		log.Info("No HTTPBootConfig found for client IP, delivering default httpboot data", "clientIPs", clientIPs)
+		log.Info("No HTTPBootConfig found for client IP, delivering default httpboot data", "clientIP", clientIP)
 		if defaultOCIImage == "" && defaultUKIURL == "" {
 			log.Error(
 				fmt.Errorf("no default UKI configured"),
@@ -453,30 +714,14 @@ func handleHTTPBoot(
 			return
 		}
 
-		ukiURL := defaultUKIURL
-		if defaultOCIImage != "" {
-			if registryValidator != nil {
-				if err := registryValidator.ValidateImageRegistry(defaultOCIImage); err != nil {
-					log.Error(err, "Default OCI image rejected by registry allowlist", "image", defaultOCIImage)
-					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-					return
-				}
-			}
-			if strings.TrimSpace(imageServerURL) == "" {
-				log.Error(fmt.Errorf("image server URL is empty"), "Default OCI image provided but image server URL is not set")
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
-			constructedURL, err := uki.ConstructUKIURLFromOCI(ctx, defaultOCIImage, imageServerURL, architecture)
-			if err != nil {
-				log.Error(err, "Failed to construct default UKI URL from OCI image", "image", defaultOCIImage)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-				return
-			}
-			ukiURL = constructedURL
+		ukiURL, err := resolveDefaultUKIURL(ctx, registryValidator, credentialStore, defaultOCIImage, defaultUKIURL, imageServerURL, architecture)
+		if err != nil {
+			log.Error(err, "Failed to resolve default UKI URL", "image", defaultOCIImage)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
 		}
 		httpBootResponseData = map[string]string{
-			"ClientIPs": strings.Join(clientIPs, ","),
+			"ClientIPs": clientIP.String(),
 			"UKIURL":    ukiURL,
 		}
 	} else {
@@ -488,7 +733,7 @@ func handleHTTPBoot(
 		}
 
 		httpBootResponseData = map[string]string{
-			"ClientIPs":  strings.Join(clientIPs, ","),
+			"ClientIPs":  clientIP.String(),
 			"UKIURL":     "",
 			"SystemUUID": "",
 		}
@@ -498,41 +743,68 @@ func handleHTTPBoot(
 		if httpBootConfig.Spec.SystemUUID != "" {
 			httpBootResponseData["SystemUUID"] = httpBootConfig.Spec.SystemUUID
 		}
+		if token := httpBootConfig.Status.IgnitionToken; token != nil {
+			httpBootResponseData["IgnitionToken"] = token.Token
+		}
 	}
 
-	response, err := json.Marshal(httpBootResponseData)
+	writeHTTPBootResponse(w, r, log, ipxeTemplates, format, httpBootResponseData)
+}
+
+// resolveDefaultUKIURL returns the UKI URL delivered to clients without a matching HTTPBootConfig:
+// the image proxy URL of the UKI layer of defaultOCIImage, or else defaultUKIURL.
+func resolveDefaultUKIURL(
+	ctx context.Context,
+	registryValidator *registry.Validator,
+	credentialStore *registry.CredentialStore,
+	defaultOCIImage string,
+	defaultUKIURL string,
+	imageServerURL string,
+	architecture string,
+) (string, error) {
+	if defaultOCIImage == "" {
+		return defaultUKIURL, nil
+	}
+	if registryValidator != nil {
+		if err := registryValidator.ValidateImageRegistry(defaultOCIImage); err != nil {
+			return "", fmt.Errorf("default OCI image rejected by registry allowlist: %w", err)
+		}
+	}
+	if strings.TrimSpace(imageServerURL) == "" {
+		return "", fmt.Errorf("default OCI image provided but image server URL is not set")
+	}
+	keychain, err := credentialStore.Keychain(ctx, defaultOCIImage, nil)
 	if err != nil {
-		log.Error(err, "Failed to marshal response data")
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		return
+		return "", fmt.Errorf("failed to get registry credentials for default OCI image: %w", err)
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	if _, err := w.Write(response); err != nil {
-		log.Error(err, "Failed to write response")
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	ukiURL, err := uki.ConstructUKIURLFromOCI(ctx, defaultOCIImage, imageServerURL, architecture, keychain)
+	if err != nil {
+		return "", fmt.Errorf("failed to construct default UKI URL from OCI image: %w", err)
 	}
+	return ukiURL, nil
 }
 
-func SetStatusCondition(ctx context.Context, k8sClient client.Client, log logr.Logger, obj client.Object, conditionType string) error {
+func SetStatusCondition(ctx context.Context, k8sClient client.Client, log logr.Logger, obj client.Object, conditionType string,
+	opts ...client.MergeFromOption) error {
 	condition, exists := predefinedConditions[conditionType]
 	if !exists {
 		log.Error(fmt.Errorf("condition type not found"), "Invalid condition type", "conditionType", conditionType)
 		return fmt.Errorf("condition type %s not found", conditionType)
 	}
+	condition.ObservedGeneration = obj.GetGeneration()
 
 	switch resource := obj.(type) {
 	case *bootv1alpha1.IPXEBootConfig:
 		base := resource.DeepCopy()
 		resource.Status.Conditions = updateCondition(resource.Status.Conditions, condition)
-		if err := k8sClient.Status().Patch(ctx, resource, client.MergeFrom(base)); err != nil {
+		if err := k8sClient.Status().Patch(ctx, resource, client.MergeFromWithOptions(base, opts...)); err != nil {
 			log.Error(err, "Failed to set the condition in the IPXEBootConfig status")
 			return err
 		}
 	case *bootv1alpha1.HTTPBootConfig:
 		base := resource.DeepCopy()
 		resource.Status.Conditions = updateCondition(resource.Status.Conditions, condition)
-		if err := k8sClient.Status().Patch(ctx, resource, client.MergeFrom(base)); err != nil {
+		if err := k8sClient.Status().Patch(ctx, resource, client.MergeFromWithOptions(base, opts...)); err != nil {
 			log.Error(err, "Failed to set the condition in the HTTPBootConfig status")
 			return err
 		}
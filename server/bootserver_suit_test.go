@@ -6,10 +6,12 @@ package server
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr"
 	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/ipxe"
 	"github.com/ironcore-dev/boot-operator/internal/registry"
 	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
@@ -44,9 +46,22 @@ func newTestClient(objs ...client.Object) client.Client {
 		WithScheme(scheme).
 		WithObjects(objs...).
 		WithStatusSubresource(&metalv1alpha1.Server{}).
+		WithIndex(&metalv1alpha1.ServerBootConfiguration{}, bootv1alpha1.ImagePullSecretIndexKey, func(obj client.Object) []string {
+			name := strings.TrimSpace(obj.GetAnnotations()[bootv1alpha1.ImagePullSecretAnnotation])
+			if name == "" {
+				return nil
+			}
+			return []string{name}
+		}).
 		Build()
 }
 
+func newTestIPXETemplates() *ipxe.Templates {
+	templates, err := ipxe.NewTemplates("", logr.Discard())
+	Expect(err).NotTo(HaveOccurred())
+	return templates
+}
+
 var _ = BeforeSuite(func() {
 	scheme := runtime.NewScheme()
 	Expect(corev1.AddToScheme(scheme)).To(Succeed())
@@ -64,13 +79,20 @@ var _ = BeforeSuite(func() {
 		errCh <- RunBootServer(
 			testServerAddr,
 			ipxeServiceURL,
+			newTestIPXETemplates(),
 			k8sClient,
 			testLog,
 			registry.NewValidator(""),
+			nil,
 			"",
 			defaultUKIURL,
 			"",
 			"amd64",
+			nil,
+			nil,
+			"",
+			IgnitionAuthOptions{},
+			SourceIPOptions{},
 		)
 	}()
 
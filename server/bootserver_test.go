@@ -8,16 +8,22 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"text/template"
 
 	"github.com/go-logr/logr"
 	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/netid"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
 	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 type httpBootResponse struct {
@@ -44,8 +50,8 @@ var _ = Describe("BootServer", func() {
 			By("returning the default UKI URL")
 			Expect(body.UKIURL).To(Equal(defaultUKIURL))
 
-			By("including the recorded client IPs")
-			Expect(body.ClientIPs).To(BeEmpty())
+			By("including the client IP taken from the socket address")
+			Expect(body.ClientIPs).To(Equal("127.0.0.1"))
 
 			By("not setting a SystemUUID in the default case")
 			Expect(body.SystemUUID).To(SatisfyAny(BeEmpty(), Equal("")))
@@ -62,15 +68,16 @@ systemd:
       enabled: true
 `)
 
-		jsonData, err := renderIgnition(butaneYAML)
+		jsonData, contentType, err := renderIgnition(logr.Discard(), butaneYAML, bootv1alpha1.FCOSFormat)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(jsonData).ToNot(BeEmpty())
 		Expect(string(jsonData)).To(ContainSubstring(`"systemd"`))
+		Expect(contentType).To(Equal("application/json"))
 	})
 
 	It("returns an error for invalid YAML", func() {
 		bad := []byte("this ::: is not yaml")
-		_, err := renderIgnition(bad)
+		_, _, err := renderIgnition(logr.Discard(), bad, bootv1alpha1.FCOSFormat)
 		Expect(err).To(HaveOccurred())
 	})
 
@@ -442,6 +449,26 @@ var _ = Describe("ConfigSelector", func() {
 			Expect(strings.Contains(script, "gl.ovl")).To(BeFalse())
 			Expect(strings.Contains(script, "gl.live")).To(BeFalse())
 		})
+
+		It("fetches ignition by UUID by default", func() {
+			script := renderIPXEScript(IPXETemplateData{IPXEServerURL: "http://example.com"})
+			Expect(script).To(ContainSubstring("ignition.config.url=${ipxe-svc}/ignition/${uuid} "))
+		})
+
+		It("fetches ignition by MAC address if the script was looked up by MAC address", func() {
+			script := renderIPXEScript(IPXETemplateData{IPXEServerURL: "http://example.com", MACAddress: "52:54:00:12:34:56"})
+			Expect(script).To(ContainSubstring("ignition.config.url=${ipxe-svc}/ignition/mac/52:54:00:12:34:56 "))
+		})
+
+		It("applies the kernel arguments of the config to the default ones", func() {
+			script := renderIPXEScript(IPXETemplateData{
+				IPXEServerURL: "http://example.com",
+				KernelArgs:    []string{"console=ttyS1,57600", "-earlyprintk", "nomodeset"},
+			})
+			Expect(script).To(ContainSubstring(" ignition.platform.id=metal consoleblank=0 console=ttyS1,57600 nomodeset\n"))
+			Expect(script).NotTo(ContainSubstring("console=ttyS0"))
+			Expect(script).NotTo(ContainSubstring("earlyprintk"))
+		})
 	})
 
 	Context("resolveServer", func() {
@@ -506,3 +533,95 @@ var _ = Describe("ConfigSelector", func() {
 		})
 	})
 })
+
+var _ = Describe("MAC address lookup", func() {
+	const (
+		systemUUID = "00000000-0000-0000-0000-000000000000"
+		macAddress = "52:54:00:ab:cd:ef"
+	)
+
+	var k8s client.Client
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8s = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&bootv1alpha1.IPXEBootConfig{
+					ObjectMeta: v1.ObjectMeta{Name: "ipxe", Namespace: "default"},
+					Spec: bootv1alpha1.IPXEBootConfigSpec{
+						SystemUUID:          systemUUID,
+						SystemMACs:          []string{macAddress},
+						IgnitionSecretRef:   &corev1.LocalObjectReference{Name: "ignition"},
+						IPXEScriptSecretRef: &corev1.LocalObjectReference{Name: "script"},
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: v1.ObjectMeta{Name: "ignition", Namespace: "default"},
+					Data:       map[string][]byte{bootv1alpha1.DefaultIgnitionKey: []byte(`{"ignition":{"version":"3.4.0"}}`)},
+				},
+				&corev1.Secret{
+					ObjectMeta: v1.ObjectMeta{Name: "script", Namespace: "default"},
+					Data:       map[string][]byte{bootv1alpha1.DefaultIPXEScriptKey: []byte("#!ipxe\nshell\n")},
+				},
+			).
+			WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+			}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemMACIndexKey, func(obj client.Object) []string {
+				return netid.CanonicalAll(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemMACs)
+			}).
+			Build()
+	})
+
+	fetchScript := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handleIPXE(rec, httptest.NewRequest(http.MethodGet, path, nil), k8s, logr.Discard(), ipxeServiceURL, newTestIPXETemplates(), nil, IgnitionAuthOptions{}, SourceIPOptions{})
+		return rec
+	}
+
+	It("serves the iPXE script of the config with the given MAC address", func() {
+		for _, mac := range []string{macAddress, "52:54:00:AB:CD:EF", "52-54-00-ab-cd-ef"} {
+			rec := fetchScript("/ipxe/mac/" + mac)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(Equal("#!ipxe\nshell\n"))
+		}
+	})
+
+	It("rejects invalid and unknown MAC addresses", func() {
+		Expect(fetchScript("/ipxe/mac/not-a-mac").Code).To(Equal(http.StatusBadRequest))
+		Expect(fetchScript("/ipxe/mac/52:54:00:ff:ff:ff").Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("serves the ignition data of the config with the given MAC address", func() {
+		rec := httptest.NewRecorder()
+		handleIgnitionByMAC(rec, httptest.NewRequest(http.MethodGet, "/ignition/mac/52-54-00-AB-CD-EF", nil), k8s, logr.Discard(),
+			"52-54-00-AB-CD-EF", IgnitionAuthOptions{}, SourceIPOptions{})
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring(`"ignition"`))
+	})
+
+	It("looks up configs stored in non-canonical form", func() {
+		Expect(k8s.Create(context.Background(), &bootv1alpha1.IPXEBootConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "legacy", Namespace: "default"},
+			Spec: bootv1alpha1.IPXEBootConfigSpec{
+				SystemUUID:          "5F2C8A1E-9B3D-4C7F-A6E1-0D4B8C2E7F91",
+				SystemMACs:          []string{"52-54-00-12-34-56"},
+				IPXEScriptSecretRef: &corev1.LocalObjectReference{Name: "script"},
+			},
+		})).To(Succeed())
+
+		for _, path := range []string{
+			"/ipxe/5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91",
+			"/ipxe/5F2C8A1E-9B3D-4C7F-A6E1-0D4B8C2E7F91",
+			"/ipxe/mac/52:54:00:12:34:56",
+		} {
+			rec := fetchScript(path)
+			Expect(rec.Code).To(Equal(http.StatusOK), path)
+			Expect(rec.Body.String()).To(Equal("#!ipxe\nshell\n"), path)
+		}
+	})
+})
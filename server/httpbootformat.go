@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/ironcore-dev/boot-operator/internal/ipxe"
+)
+
+// httpBootFormat is the format of the response of the /httpboot endpoint.
+type httpBootFormat string
+
+const (
+	// httpBootFormatJSON returns the UKI URL and the details of the boot in a JSON object. It is
+	// the default.
+	httpBootFormatJSON httpBootFormat = "json"
+	// httpBootFormatIPXE returns an iPXE script that chains the UKI.
+	httpBootFormatIPXE httpBootFormat = "ipxe"
+	// httpBootFormatRedirect redirects firmware clients to the UKI.
+	httpBootFormatRedirect httpBootFormat = "redirect"
+)
+
+// httpBootFormatParam is the query parameter that selects the format of the /httpboot response. It
+// takes precedence over the Accept header.
+const httpBootFormatParam = "format"
+
+// httpBootMediaTypes map the media types of the Accept header to the formats of the /httpboot
+// response.
+var httpBootMediaTypes = map[string]httpBootFormat{
+	"application/json": httpBootFormatJSON,
+	"text/ipxe":        httpBootFormatIPXE,
+	"application/efi":  httpBootFormatRedirect,
+	"*/*":              httpBootFormatJSON,
+}
+
+var (
+	errUnknownHTTPBootFormat = errors.New("unknown format")
+	errNotAcceptable         = errors.New("none of the accepted media types can be served")
+)
+
+// negotiateHTTPBootFormat returns the format of the /httpboot response requested by the format
+// query parameter or, if it is not set, the Accept header. Media types are preferred by their
+// quality value and then by their order.
+func negotiateHTTPBootFormat(r *http.Request) (httpBootFormat, error) {
+	if value := r.URL.Query().Get(httpBootFormatParam); value != "" {
+		switch format := httpBootFormat(value); format {
+		case httpBootFormatJSON, httpBootFormatIPXE, httpBootFormatRedirect:
+			return format, nil
+		default:
+			return "", fmt.Errorf("%w %q", errUnknownHTTPBootFormat, value)
+		}
+	}
+
+	accept := strings.TrimSpace(r.Header.Get("Accept"))
+	if accept == "" {
+		return httpBootFormatJSON, nil
+	}
+	var best httpBootFormat
+	bestQ := 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		format, ok := httpBootMediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+	// A quality value of 0 marks a media type as not acceptable.
+	if bestQ == 0 {
+		return "", errNotAcceptable
+	}
+	return best, nil
+}
+
+// httpBootFormatStatus returns the status code of the response to a request whose format cannot
+// be negotiated.
+func httpBootFormatStatus(err error) int {
+	if errors.Is(err, errNotAcceptable) {
+		return http.StatusNotAcceptable
+	}
+	return http.StatusBadRequest
+}
+
+// writeHTTPBootResponse writes the data of the /httpboot response in the given format.
+func writeHTTPBootResponse(w http.ResponseWriter, r *http.Request, log logr.Logger, ipxeTemplates *ipxe.Templates,
+	format httpBootFormat, data map[string]string) {
+	// The format may depend on the Accept header, so caches must not serve it to other clients.
+	w.Header().Add("Vary", "Accept")
+
+	if format == httpBootFormatJSON {
+		response, err := json.Marshal(data)
+		if err != nil {
+			log.Error(err, "Failed to marshal response data")
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(response); err != nil {
+			log.Error(err, "Failed to write response")
+		}
+		return
+	}
+
+	ukiURL := data["UKIURL"]
+	if ukiURL == "" {
+		log.Info("No UKI to boot", "clientIP", data["ClientIPs"], "format", format)
+		http.Error(w, "Resource Not Found", http.StatusNotFound)
+		return
+	}
+	if format == httpBootFormatRedirect {
+		http.Redirect(w, r, ukiURL, http.StatusFound)
+		return
+	}
+
+	script, err := ipxeTemplates.Render(ipxe.UKITemplate, IPXETemplateData{
+		UKIURL:        ukiURL,
+		SystemUUID:    data["SystemUUID"],
+		IgnitionToken: data["IgnitionToken"],
+	})
+	if err != nil {
+		log.Error(err, "Failed to render iPXE UKI template")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if _, err := w.Write(script); err != nil {
+		log.Error(err, "Failed to write iPXE script")
+	}
+}
@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/netid"
+	"github.com/ironcore-dev/boot-operator/internal/registry"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("HTTP boot response formats", func() {
+	const ukiURL = "http://images.example.com/uki.efi"
+
+	var k8s client.Client
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8s = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&bootv1alpha1.HTTPBootConfig{
+					ObjectMeta: v1.ObjectMeta{Name: "http", Namespace: "default"},
+					Spec: bootv1alpha1.HTTPBootConfigSpec{
+						SystemUUID:         "0d3f5b7e-1a2c-4e6b-8d9f-3c5e7a9b1d2f",
+						NetworkIdentifiers: []string{"10.0.0.20"},
+						UKIURL:             ukiURL,
+					},
+				},
+				&bootv1alpha1.HTTPBootConfig{
+					ObjectMeta: v1.ObjectMeta{Name: "no-uki", Namespace: "default"},
+					Spec:       bootv1alpha1.HTTPBootConfigSpec{NetworkIdentifiers: []string{"10.0.0.21"}},
+				},
+			).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.NetworkIdentifierIndexKey, func(obj client.Object) []string {
+				return netid.CanonicalAll(obj.(*bootv1alpha1.HTTPBootConfig).Spec.NetworkIdentifiers)
+			}).
+			Build()
+	})
+
+	httpBoot := func(remoteAddr, target, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.RemoteAddr = remoteAddr
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		handleHTTPBoot(rec, req, k8s, logr.Discard(), newTestIPXETemplates(), registry.NewValidator(""), nil, "", defaultUKIURL, "", "amd64", nil)
+		return rec
+	}
+
+	DescribeTable("negotiates the format",
+		func(target, accept string, want httpBootFormat, wantErr error) {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			if accept != "" {
+				req.Header.Set("Accept", accept)
+			}
+			format, err := negotiateHTTPBootFormat(req)
+			if wantErr != nil {
+				Expect(err).To(MatchError(wantErr))
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(format).To(Equal(want))
+		},
+		Entry("by default", "/httpboot", "", httpBootFormatJSON, nil),
+		Entry("for any media type", "/httpboot", "*/*", httpBootFormatJSON, nil),
+		Entry("by media type", "/httpboot", "text/ipxe", httpBootFormatIPXE, nil),
+		Entry("by quality value", "/httpboot", "application/json;q=0.5, application/efi", httpBootFormatRedirect, nil),
+		Entry("by order", "/httpboot", "text/html, text/ipxe, application/json", httpBootFormatIPXE, nil),
+		Entry("by query parameter", "/httpboot?format=redirect", "application/json", httpBootFormatRedirect, nil),
+		Entry("for unknown media types", "/httpboot", "text/html, application/json;q=0", httpBootFormat(""), errNotAcceptable),
+		Entry("for unknown formats", "/httpboot?format=yaml", "", httpBootFormat(""), errUnknownHTTPBootFormat),
+	)
+
+	It("returns the JSON data by default", func() {
+		rec := httpBoot("10.0.0.20:1234", "/httpboot", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(rec.Header().Get("Vary")).To(Equal("Accept"))
+		var response map[string]string
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		Expect(response).To(HaveKeyWithValue("UKIURL", ukiURL))
+	})
+
+	It("returns an iPXE script chaining the UKI", func() {
+		rec := httpBoot("10.0.0.20:1234", "/httpboot", "text/ipxe")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("#!ipxe\n\nchain --autofree " + ukiURL + "\n"))
+	})
+
+	It("redirects to the UKI", func() {
+		rec := httpBoot("10.0.0.20:1234", "/httpboot?format=redirect", "")
+		Expect(rec.Code).To(Equal(http.StatusFound))
+		Expect(rec.Header().Get("Location")).To(Equal(ukiURL))
+	})
+
+	It("redirects clients without an HTTPBootConfig to the default UKI", func() {
+		rec := httpBoot("10.0.0.30:1234", "/httpboot", "application/efi")
+		Expect(rec.Code).To(Equal(http.StatusFound))
+		Expect(rec.Header().Get("Location")).To(Equal(defaultUKIURL))
+	})
+
+	It("does not redirect if there is no UKI", func() {
+		Expect(httpBoot("10.0.0.21:1234", "/httpboot?format=redirect", "").Code).To(Equal(http.StatusNotFound))
+		Expect(httpBoot("10.0.0.21:1234", "/httpboot?format=ipxe", "").Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("rejects formats it cannot serve", func() {
+		Expect(httpBoot("10.0.0.20:1234", "/httpboot?format=yaml", "").Code).To(Equal(http.StatusBadRequest))
+		Expect(httpBoot("10.0.0.20:1234", "/httpboot", "text/html").Code).To(Equal(http.StatusNotAcceptable))
+	})
+})
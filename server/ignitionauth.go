@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"time"
+
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/ignition"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ignitionTokenParam is the query parameter of ignition requests carrying the ignition token.
+const ignitionTokenParam = "token"
+
+// IgnitionAuthOptions configures how the boot-server authenticates ignition requests.
+type IgnitionAuthOptions struct {
+	// RequireToken rejects ignition requests that do not carry the current ignition token of
+	// the boot config. It also restricts iPXE scripts, which carry the token, to the SystemIPs
+	// of their IPXEBootConfig and rotates the token whenever a script is served.
+	RequireToken bool
+	// TokenTTL is the lifetime of the tokens minted when a script is served.
+	TokenTTL time.Duration
+	// BindTokenToSourceIP additionally requires ignition requests to come from one of the
+	// SystemIPs of an IPXEBootConfig or the NetworkIdentifiers of an HTTPBootConfig.
+	BindTokenToSourceIP bool
+}
+
+// authorizeIgnitionRequest checks an ignition request against the ignition token and the known
+// addresses of its boot config.
+func authorizeIgnitionRequest(
+	r *http.Request,
+	opts IgnitionAuthOptions,
+	token *bootv1alpha1.IgnitionToken,
+	networkIdentifiers []string,
+	trustedProxies []netip.Prefix,
+	now time.Time,
+) error {
+	if !opts.RequireToken {
+		return nil
+	}
+
+	presented := r.URL.Query().Get(ignitionTokenParam)
+	switch {
+	case token == nil:
+		return errors.New("no ignition token has been issued")
+	case presented == "":
+		return errors.New("ignition token missing")
+	case subtle.ConstantTimeCompare([]byte(presented), []byte(token.Token)) != 1:
+		return errors.New("ignition token invalid")
+	case !now.Before(token.ExpirationTime.Time):
+		return fmt.Errorf("ignition token expired at %s", token.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+
+	if opts.BindTokenToSourceIP {
+		addr, err := clientAddress(r, trustedProxies)
+		if err != nil {
+			return err
+		}
+		if !isKnownAddress(addr.String(), networkIdentifiers) {
+			return fmt.Errorf("source address %s is not an address of the server", addr)
+		}
+	}
+	return nil
+}
+
+// ignitionTokenSyncTimeout bounds how long rotateIgnitionToken waits for the client to observe
+// the rotated token.
+const ignitionTokenSyncTimeout = 5 * time.Second
+
+// rotateIgnitionToken replaces the ignition token of an IPXEBootConfig by a new one, so that the
+// token embedded into an iPXE script is only valid for the boot that fetched the script. It waits
+// until the client observes the new token, as the signature of the script is rendered from the
+// stored token by a subsequent request.
+func rotateIgnitionToken(ctx context.Context, k8sClient client.Client, config *bootv1alpha1.IPXEBootConfig,
+	ttl time.Duration) error {
+	token, err := ignition.NewToken(config.Generation, ttl, time.Now())
+	if err != nil {
+		return err
+	}
+
+	base := config.DeepCopy()
+	config.Status.IgnitionToken = token
+	if err := k8sClient.Status().Patch(ctx, config, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("failed to rotate ignition token: %w", err)
+	}
+
+	key := client.ObjectKeyFromObject(config)
+	return wait.PollUntilContextTimeout(ctx, 50*time.Millisecond, ignitionTokenSyncTimeout, true,
+		func(ctx context.Context) (bool, error) {
+			current := &bootv1alpha1.IPXEBootConfig{}
+			if err := k8sClient.Get(ctx, key, current); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			return current.Status.IgnitionToken != nil && current.Status.IgnitionToken.Token == token.Token, nil
+		})
+}
+
+// isKnownAddress reports whether addr is one of the IP addresses among networkIdentifiers, which
+// may also contain MAC addresses.
+func isKnownAddress(addr string, networkIdentifiers []string) bool {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, id := range networkIdentifiers {
+		if known, err := netip.ParseAddr(id); err == nil && known.Unmap() == ip {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/netid"
+	"github.com/ironcore-dev/boot-operator/internal/registry"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Ignition authentication", func() {
+	const (
+		systemUUID = "2f6e3a4c-8d1b-4e6f-9a2c-5b7d9e1f3a5c"
+		token      = "s3cr3t-t0k3n"
+		ignition   = `{"ignition":{"version":"3.4.0"}}`
+	)
+
+	var (
+		k8s  client.Client
+		auth IgnitionAuthOptions
+	)
+
+	newClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(metalv1alpha1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}, &bootv1alpha1.HTTPBootConfig{}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+			}).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.HTTPBootConfig).Spec.SystemUUID)}
+			}).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.NetworkIdentifierIndexKey, func(obj client.Object) []string {
+				return netid.CanonicalAll(obj.(*bootv1alpha1.HTTPBootConfig).Spec.NetworkIdentifiers)
+			}).
+			Build()
+	}
+
+	ignitionToken := func(expiresIn time.Duration) *bootv1alpha1.IgnitionToken {
+		return &bootv1alpha1.IgnitionToken{Token: token, ExpirationTime: v1.NewTime(time.Now().Add(expiresIn)), ObservedGeneration: 1}
+	}
+
+	BeforeEach(func() {
+		auth = IgnitionAuthOptions{RequireToken: true}
+		ignitionSecret := &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "ignition", Namespace: "default"},
+			Data:       map[string][]byte{bootv1alpha1.DefaultIgnitionKey: []byte(ignition)},
+		}
+		ipxeBootConfig := &bootv1alpha1.IPXEBootConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "ipxe", Namespace: "default"},
+			Spec: bootv1alpha1.IPXEBootConfigSpec{
+				SystemUUID:        systemUUID,
+				SystemIPs:         []string{"10.0.0.10"},
+				IgnitionSecretRef: &corev1.LocalObjectReference{Name: "ignition"},
+			},
+			Status: bootv1alpha1.IPXEBootConfigStatus{IgnitionToken: ignitionToken(time.Hour)},
+		}
+		k8s = newClient(ignitionSecret, ipxeBootConfig)
+	})
+
+	fetchIgnition := func(query, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ignition/"+systemUUID+query, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handleIgnitionIPXEBoot(rec, req, k8s, logr.Discard(), systemUUID, auth, SourceIPOptions{})
+		return rec
+	}
+
+	It("serves ignition data for the current token", func() {
+		rec := fetchIgnition("?token="+token, "192.0.2.1:1234")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(ignition))
+	})
+
+	DescribeTable("rejects requests without the current token",
+		func(query string) {
+			rec := fetchIgnition(query, "192.0.2.1:1234")
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			Expect(rec.Body.String()).NotTo(ContainSubstring("ignition"))
+		},
+		Entry("missing token", ""),
+		Entry("wrong token", "?token=guessed"),
+	)
+
+	It("rejects expired tokens", func() {
+		config := &bootv1alpha1.IPXEBootConfig{}
+		Expect(k8s.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "ipxe"}, config)).To(Succeed())
+		config.Status.IgnitionToken = ignitionToken(-time.Second)
+		Expect(k8s.Status().Update(context.Background(), config)).To(Succeed())
+
+		Expect(fetchIgnition("?token="+token, "192.0.2.1:1234").Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("does not require a token unless enabled", func() {
+		auth = IgnitionAuthOptions{}
+		Expect(fetchIgnition("", "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+	})
+
+	It("binds the token to the system IPs if enabled", func() {
+		auth.BindTokenToSourceIP = true
+		Expect(fetchIgnition("?token="+token, "192.0.2.1:1234").Code).To(Equal(http.StatusUnauthorized))
+		Expect(fetchIgnition("?token="+token, "10.0.0.10:1234").Code).To(Equal(http.StatusOK))
+		Expect(fetchIgnition("?token="+token, "[::ffff:10.0.0.10]:1234").Code).To(Equal(http.StatusOK))
+	})
+
+	Context("when serving iPXE scripts", func() {
+		fetchScript := func(remoteAddr string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/ipxe/"+systemUUID, nil)
+			req.RemoteAddr = remoteAddr
+			rec := httptest.NewRecorder()
+			handleIPXE(rec, req, k8s, logr.Discard(), "http://boot.example.com", newTestIPXETemplates(), nil,
+				IgnitionAuthOptions{RequireToken: true, TokenTTL: time.Hour}, SourceIPOptions{})
+			return rec
+		}
+		currentToken := func() string {
+			config := &bootv1alpha1.IPXEBootConfig{}
+			Expect(k8s.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "ipxe"}, config)).To(Succeed())
+			Expect(config.Status.IgnitionToken).NotTo(BeNil())
+			return config.Status.IgnitionToken.Token
+		}
+
+		It("rotates the token on every script fetch", func() {
+			rec := fetchScript("10.0.0.10:1234")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			first := currentToken()
+			Expect(first).NotTo(Equal(token))
+			Expect(rec.Body.String()).To(ContainSubstring("?token=" + first + " "))
+			Expect(fetchIgnition("?token="+token, "10.0.0.10:1234").Code).To(Equal(http.StatusUnauthorized))
+			Expect(fetchIgnition("?token="+first, "10.0.0.10:1234").Code).To(Equal(http.StatusOK))
+
+			Expect(fetchScript("10.0.0.10:1234").Code).To(Equal(http.StatusOK))
+			Expect(currentToken()).NotTo(Equal(first))
+			Expect(fetchIgnition("?token="+first, "10.0.0.10:1234").Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("only serves scripts to the system IPs", func() {
+			rec := fetchScript("192.0.2.1:1234")
+			Expect(rec.Code).To(Equal(http.StatusForbidden))
+			Expect(rec.Body.String()).NotTo(ContainSubstring(token))
+			Expect(currentToken()).To(Equal(token))
+		})
+	})
+
+	It("hands out the token with the HTTP boot response", func() {
+		httpBootConfig := &bootv1alpha1.HTTPBootConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "http", Namespace: "default"},
+			Spec: bootv1alpha1.HTTPBootConfigSpec{
+				SystemUUID:         systemUUID,
+				NetworkIdentifiers: []string{"10.0.0.20"},
+				UKIURL:             "http://images.example.com/uki.efi",
+			},
+			Status: bootv1alpha1.HTTPBootConfigStatus{IgnitionToken: ignitionToken(time.Hour)},
+		}
+		k8s = newClient(httpBootConfig)
+
+		req := httptest.NewRequest(http.MethodGet, "/httpboot", nil)
+		req.RemoteAddr = "10.0.0.20:1234"
+		rec := httptest.NewRecorder()
+		handleHTTPBoot(rec, req, k8s, logr.Discard(), newTestIPXETemplates(), registry.NewValidator(""), nil, "", defaultUKIURL, "", "amd64", nil)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var response map[string]string
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		Expect(response).To(HaveKeyWithValue("IgnitionToken", token))
+	})
+
+	It("embeds the token into the ignition URL of the default iPXE script", func() {
+		tmpl, err := template.ParseFiles("../templates/ipxe-script.tpl")
+		Expect(err).NotTo(HaveOccurred())
+		var script bytes.Buffer
+		Expect(tmpl.Execute(&script, IPXETemplateData{
+			KernelURL:     "http://example.com/kernel",
+			InitrdURL:     "http://example.com/initrd",
+			IPXEServerURL: "http://example.com",
+			IgnitionToken: token,
+		})).To(Succeed())
+		Expect(script.String()).To(ContainSubstring("ignition.config.url=${ipxe-svc}/ignition/${uuid}?token=" + token + " "))
+	})
+})
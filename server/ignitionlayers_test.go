@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Ignition layers", func() {
+	const systemUUID = "0d3f5b7e-1a2c-4e6b-8d9f-3c5e7a9b1d2f"
+
+	var layers []bootv1alpha1.IgnitionLayer
+
+	newSecret := func(name, format, data string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+			Data: map[string][]byte{
+				bootv1alpha1.DefaultFormatKey:   []byte(format),
+				bootv1alpha1.DefaultIgnitionKey: []byte(data),
+			},
+		}
+	}
+
+	fetchIgnition := func() *httptest.ResponseRecorder {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8s := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				newSecret("hardening", "", `{"ignition":{"version":"3.3.0"},"storage":{"files":[{"path":"/etc/motd","contents":{"source":"data:,hardened"}}]}}`),
+				newSecret("site", bootv1alpha1.FCOSFormat, "variant: fcos\nversion: 1.5.0\nstorage:\n  files:\n  - path: /etc/motd\n    contents:\n      inline: site\n"),
+				newSecret("machine", "", `{"ignition":{"version":"3.4.0"},"storage":{"files":[{"path":"/etc/hostname","contents":{"source":"data:,node-1"}}]}}`),
+				newSecret("cloud-init", bootv1alpha1.CloudInitFormat, "#cloud-config\n"),
+				&bootv1alpha1.HTTPBootConfig{
+					ObjectMeta: v1.ObjectMeta{Name: "http", Namespace: "default"},
+					Spec: bootv1alpha1.HTTPBootConfigSpec{
+						SystemUUID:        systemUUID,
+						IgnitionSecretRef: &corev1.LocalObjectReference{Name: "machine"},
+						IgnitionLayers:    layers,
+					},
+				},
+			).
+			WithStatusSubresource(&bootv1alpha1.HTTPBootConfig{}).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.HTTPBootConfig).Spec.SystemUUID)}
+			}).
+			Build()
+
+		req := httptest.NewRequest(http.MethodGet, "/ignition/"+systemUUID, nil)
+		rec := httptest.NewRecorder()
+		handleIgnitionHTTPBoot(rec, req, k8s, logr.Discard(), systemUUID, IgnitionAuthOptions{}, SourceIPOptions{})
+		return rec
+	}
+
+	layer := func(name string, strategy bootv1alpha1.IgnitionLayerStrategy) bootv1alpha1.IgnitionLayer {
+		return bootv1alpha1.IgnitionLayer{SecretRef: corev1.LocalObjectReference{Name: name}, Strategy: strategy}
+	}
+
+	files := func(rec *httptest.ResponseRecorder) map[string]string {
+		var config struct {
+			Storage struct {
+				Files []struct {
+					Path     string `json:"path"`
+					Contents struct {
+						Source string `json:"source"`
+					} `json:"contents"`
+				} `json:"files"`
+			} `json:"storage"`
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &config)).To(Succeed())
+		files := map[string]string{}
+		for _, file := range config.Storage.Files {
+			files[file.Path] = file.Contents.Source
+		}
+		return files
+	}
+
+	It("merges the ignition Secret onto the layers in order", func() {
+		layers = []bootv1alpha1.IgnitionLayer{layer("hardening", ""), layer("site", "")}
+		rec := fetchIgnition()
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(rec.Body.String()).To(ContainSubstring(`"version":"3.4.0"`))
+		Expect(files(rec)).To(HaveLen(2))
+		Expect(files(rec)).To(HaveKeyWithValue("/etc/hostname", "data:,node-1"))
+		Expect(files(rec)["/etc/motd"]).To(ContainSubstring("site"))
+	})
+
+	It("discards the layers before a replacing layer", func() {
+		layers = []bootv1alpha1.IgnitionLayer{layer("site", ""), layer("hardening", bootv1alpha1.IgnitionLayerStrategyReplace)}
+		rec := fetchIgnition()
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(files(rec)).To(HaveKeyWithValue("/etc/motd", "data:,hardened"))
+	})
+
+	It("serves the ignition Secret as is without layers", func() {
+		layers = nil
+		rec := fetchIgnition()
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(`{"ignition":{"version":"3.4.0"},"storage":{"files":[{"path":"/etc/hostname","contents":{"source":"data:,node-1"}}]}}`))
+	})
+
+	It("fails for layers that cannot be merged", func() {
+		layers = []bootv1alpha1.IgnitionLayer{layer("cloud-init", "")}
+		Expect(fetchIgnition().Code).To(Equal(http.StatusInternalServerError))
+
+		layers = []bootv1alpha1.IgnitionLayer{layer("missing", "")}
+		Expect(fetchIgnition().Code).To(Equal(http.StatusNotFound))
+	})
+})
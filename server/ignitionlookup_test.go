@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/netid"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Ignition lookup", func() {
+	const (
+		ipxeUUID = "5f2c8a1e-9b3d-4c7f-a6e1-0d4b8c2e7f91"
+		httpUUID = "0d3f5b7e-1a2c-4e6b-8d9f-3c5e7a9b1d2f"
+	)
+
+	var k8s client.Client
+
+	BeforeEach(func() {
+		newSecret := func(name, hostname string) *corev1.Secret {
+			return &corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+				Data: map[string][]byte{
+					bootv1alpha1.DefaultIgnitionKey: []byte(`{"ignition":{"version":"3.4.0"},"storage":{"files":[{"path":"/etc/hostname","contents":{"source":"data:,` + hostname + `"}}]}}`),
+				},
+			}
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8s = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				newSecret("ipxe-ignition", "ipxe-node"),
+				newSecret("http-ignition", "http-node"),
+				&bootv1alpha1.IPXEBootConfig{
+					ObjectMeta: v1.ObjectMeta{Name: "ipxe", Namespace: "default"},
+					Spec: bootv1alpha1.IPXEBootConfigSpec{
+						SystemUUID:        ipxeUUID,
+						SystemIPs:         []string{"10.0.0.10"},
+						IgnitionSecretRef: &corev1.LocalObjectReference{Name: "ipxe-ignition"},
+					},
+				},
+				&bootv1alpha1.HTTPBootConfig{
+					ObjectMeta: v1.ObjectMeta{Name: "http", Namespace: "default"},
+					Spec: bootv1alpha1.HTTPBootConfigSpec{
+						SystemUUID:         httpUUID,
+						NetworkIdentifiers: []string{"10.0.0.20", "52:54:00:ab:cd:ef"},
+						IgnitionSecretRef:  &corev1.LocalObjectReference{Name: "http-ignition"},
+					},
+				},
+			).
+			WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}, &bootv1alpha1.HTTPBootConfig{}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+			}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemIPIndexKey, func(obj client.Object) []string {
+				return netid.CanonicalAll(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemIPs)
+			}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemMACIndexKey, func(obj client.Object) []string {
+				return netid.CanonicalAll(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemMACs)
+			}).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.HTTPBootConfig).Spec.SystemUUID)}
+			}).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.NetworkIdentifierIndexKey, func(obj client.Object) []string {
+				return netid.CanonicalAll(obj.(*bootv1alpha1.HTTPBootConfig).Spec.NetworkIdentifiers)
+			}).
+			Build()
+	})
+
+	fetchByAddress := func(remoteAddr string, header http.Header, sourceIP SourceIPOptions) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ignition", nil)
+		req.RemoteAddr = remoteAddr
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		rec := httptest.NewRecorder()
+		handleIgnitionByAddress(rec, req, k8s, logr.Discard(), IgnitionAuthOptions{}, sourceIP)
+		return rec
+	}
+
+	DescribeTable("serves the ignition data of the boot config with the source address of the client",
+		func(remoteAddr, hostname string) {
+			rec := fetchByAddress(remoteAddr, nil, SourceIPOptions{})
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(ContainSubstring("data:," + hostname))
+		},
+		Entry("HTTPBootConfig", "10.0.0.20:1234", "http-node"),
+		Entry("HTTPBootConfig by IPv4-mapped address", "[::ffff:10.0.0.20]:1234", "http-node"),
+		Entry("IPXEBootConfig", "10.0.0.10:1234", "ipxe-node"),
+	)
+
+	It("resolves the source address through trusted proxies", func() {
+		header := http.Header{"X-Forwarded-For": {"10.0.0.20"}}
+		sourceIP := SourceIPOptions{TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.1.0.0/16")}}
+		rec := fetchByAddress("10.1.0.1:1234", header, sourceIP)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("data:,http-node"))
+
+		Expect(fetchByAddress("10.1.0.1:1234", header, SourceIPOptions{}).Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("does not serve unknown addresses", func() {
+		Expect(fetchByAddress("10.0.0.30:1234", nil, SourceIPOptions{}).Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("serves the ignition data of the HTTPBootConfig with the given MAC address", func() {
+		rec := httptest.NewRecorder()
+		handleIgnitionByMAC(rec, httptest.NewRequest(http.MethodGet, "/ignition/mac/52-54-00-AB-CD-EF", nil), k8s, logr.Discard(),
+			"52-54-00-AB-CD-EF", IgnitionAuthOptions{}, SourceIPOptions{})
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("data:,http-node"))
+	})
+
+	DescribeTable("matches the mixed-endian variant of the SystemUUID",
+		func(uuid, hostname string) {
+			Expect(systemuuid.Swapped(uuid)).NotTo(Equal(uuid))
+			rec := httptest.NewRecorder()
+			handleIgnition(rec, httptest.NewRequest(http.MethodGet, "/ignition/"+systemuuid.Swapped(uuid), nil), k8s, logr.Discard(),
+				systemuuid.Swapped(uuid), IgnitionAuthOptions{}, SourceIPOptions{})
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(ContainSubstring("data:," + hostname))
+		},
+		Entry("IPXEBootConfig", ipxeUUID, "ipxe-node"),
+		Entry("HTTPBootConfig", httpUUID, "http-node"),
+	)
+
+	It("finds the boot config of the mixed-endian variant of the SystemUUID for readiness reports", func() {
+		req := httptest.NewRequest(http.MethodPost, "/ready/"+systemuuid.Swapped(httpUUID), nil)
+		config, err := findBootConfig(req, k8s, logr.Discard(), systemuuid.Swapped(httpUUID), nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config).NotTo(BeNil())
+		Expect(config.GetName()).To(Equal("http"))
+	})
+})
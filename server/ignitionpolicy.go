@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"path"
+	"time"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// enforceIgnitionPolicy checks whether the ignition data of a boot config may be served under its
+// IgnitionPolicy. It returns the HTTP status to respond with if the request is refused.
+func enforceIgnitionPolicy(config client.Object, policy bootv1alpha1.IgnitionPolicy) (int, error) {
+	conditions, err := bootConfigConditions(config)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	switch policy {
+	case "", bootv1alpha1.IgnitionPolicyAlways:
+		return 0, nil
+	case bootv1alpha1.IgnitionPolicyUntilReady:
+		if isCurrentCondition(conditions, bootv1alpha1.OSReadyCondition, config.GetGeneration()) {
+			return http.StatusGone, errors.New("the booted OS has already reported readiness")
+		}
+		return 0, nil
+	case bootv1alpha1.IgnitionPolicyOnce:
+		if isCurrentCondition(conditions, bootv1alpha1.IgnitionDataFetchedCondition, config.GetGeneration()) {
+			return http.StatusGone, errors.New("ignition data has already been delivered")
+		}
+		return 0, nil
+	default:
+		return http.StatusInternalServerError, fmt.Errorf("unknown ignition policy %q", policy)
+	}
+}
+
+// recordIgnitionDelivery sets the IgnitionDataFetched condition of a boot config once its ignition
+// data has been written to the client, so that a failed write does not use up the delivery.
+//
+// For the Once policy the condition is set with an optimistic lock. A conflict means that the
+// boot config changed since it was read, possibly because a concurrent request delivered the data
+// as well, which is reported as an error.
+func recordIgnitionDelivery(
+	ctx context.Context,
+	k8sClient client.Client,
+	log logr.Logger,
+	config client.Object,
+	policy bootv1alpha1.IgnitionPolicy,
+) error {
+	var opts []client.MergeFromOption
+	if policy == bootv1alpha1.IgnitionPolicyOnce {
+		opts = append(opts, client.MergeFromWithOptimisticLock{})
+	}
+	err := SetStatusCondition(ctx, k8sClient, log, config, bootv1alpha1.IgnitionDataFetchedCondition, opts...)
+	if apierrors.IsConflict(err) {
+		return fmt.Errorf("ignition data may have been delivered concurrently: %w", err)
+	}
+	return err
+}
+
+// isCurrentCondition reports whether the condition of the given type is true and has been set for
+// the given generation of its boot config. Changing the spec of a boot config thus re-arms it.
+func isCurrentCondition(conditions []v1.Condition, conditionType string, generation int64) bool {
+	condition := apimeta.FindStatusCondition(conditions, conditionType)
+	return condition != nil && condition.Status == v1.ConditionTrue && condition.ObservedGeneration == generation
+}
+
+func bootConfigConditions(config client.Object) ([]v1.Condition, error) {
+	switch resource := config.(type) {
+	case *bootv1alpha1.IPXEBootConfig:
+		return resource.Status.Conditions, nil
+	case *bootv1alpha1.HTTPBootConfig:
+		return resource.Status.Conditions, nil
+	default:
+		return nil, fmt.Errorf("unsupported resource type %T", config)
+	}
+}
+
+// handleReady records that the OS booted with a boot config has come up, which ends the delivery of
+// its ignition data under the UntilReady policy. It is authenticated like ignition requests.
+func handleReady(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, ignitionAuth IgnitionAuthOptions,
+	sourceIP SourceIPOptions) {
+	log.Info("Processing ready report", "method", r.Method, "path", r.URL.Path, "clientIP", r.RemoteAddr)
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := r.Context()
+
+	uuid := path.Base(r.URL.Path)
+	if uuid == "" || uuid == "ready" {
+		http.Error(w, "Bad Request: UUID is required", http.StatusBadRequest)
+		return
+	}
+
+	config, err := findBootConfig(r, k8sClient, log, uuid, sourceIP.TrustedProxies)
+	if err != nil {
+		log.Error(err, "Failed to find boot config")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if config == nil {
+		log.Info("No boot config found with given UUID")
+		http.Error(w, "Resource Not Found", http.StatusNotFound)
+		return
+	}
+
+	token, networkIdentifiers, _ := bootConfigIgnition(config)
+	if err := verifySourceAddress(r, sourceIP, log, config, networkIdentifiers); err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if err := authorizeIgnitionRequest(r, ignitionAuth, token, networkIdentifiers, sourceIP.TrustedProxies, time.Now()); err != nil {
+		log.Info("Rejected ready report", "config", client.ObjectKeyFromObject(config), "reason", err.Error())
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := SetStatusCondition(ctx, k8sClient, log, config, bootv1alpha1.OSReadyCondition); err != nil {
+		log.Error(err, "Failed to set OSReady status condition")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findBootConfig returns the IPXEBootConfig or, if there is none, the HTTPBootConfig of the system
+// with the given UUID that sent r. It returns nil if neither exists.
+func findBootConfig(r *http.Request, k8sClient client.Client, log logr.Logger, uuid string, trustedProxies []netip.Prefix) (client.Object, error) {
+	ctx := r.Context()
+	ipxeBootConfigList := &bootv1alpha1.IPXEBootConfigList{}
+	if err := listBySystemUUID(ctx, k8sClient, ipxeBootConfigList, uuid); err != nil {
+		return nil, fmt.Errorf("failed to list IPXEBootConfigs: %w", err)
+	}
+	if len(ipxeBootConfigList.Items) > 0 {
+		configs, err := matchAmbiguousSystemUUID(r, log, toPointers(ipxeBootConfigList.Items), trustedProxies)
+		if err != nil || len(configs) == 0 {
+			return nil, err
+		}
+		return selectBootConfig(ctx, k8sClient, log, configs)
+	}
+
+	httpBootConfigList := &bootv1alpha1.HTTPBootConfigList{}
+	if err := listBySystemUUID(ctx, k8sClient, httpBootConfigList, uuid); err != nil {
+		return nil, fmt.Errorf("failed to list HTTPBootConfigs: %w", err)
+	}
+	if len(httpBootConfigList.Items) > 0 {
+		configs, err := matchAmbiguousSystemUUID(r, log, toPointers(httpBootConfigList.Items), trustedProxies)
+		if err != nil || len(configs) == 0 {
+			return nil, err
+		}
+		return selectBootConfig(ctx, k8sClient, log, configs)
+	}
+	return nil, nil
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("Ignition policy", func() {
+	const (
+		systemUUID = "7c1d9e2a-4b3f-4a6e-8d5c-2e9f1a3b5c7d"
+		ignition   = `{"ignition":{"version":"3.4.0"}}`
+	)
+
+	var k8s client.Client
+
+	newConfig := func(policy bootv1alpha1.IgnitionPolicy) *bootv1alpha1.IPXEBootConfig {
+		return &bootv1alpha1.IPXEBootConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "ipxe", Namespace: "default", Generation: 1},
+			Spec: bootv1alpha1.IPXEBootConfigSpec{
+				SystemUUID:        systemUUID,
+				SystemIPs:         []string{"10.0.0.10"},
+				IgnitionSecretRef: &corev1.LocalObjectReference{Name: "ignition"},
+				IgnitionPolicy:    policy,
+			},
+		}
+	}
+
+	setup := func(config *bootv1alpha1.IPXEBootConfig) {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8s = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(config, &corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{Name: "ignition", Namespace: "default"},
+				Data:       map[string][]byte{bootv1alpha1.DefaultIgnitionKey: []byte(ignition)},
+			}).
+			WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}, &bootv1alpha1.HTTPBootConfig{}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+			}).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.HTTPBootConfig).Spec.SystemUUID)}
+			}).
+			Build()
+	}
+
+	fetchIgnition := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handleIgnitionIPXEBoot(rec, httptest.NewRequest(http.MethodGet, "/ignition/"+systemUUID, nil), k8s, logr.Discard(), systemUUID, IgnitionAuthOptions{}, SourceIPOptions{})
+		return rec
+	}
+
+	reportReady := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handleReady(rec, httptest.NewRequest(method, "/ready/"+systemUUID, nil), k8s, logr.Discard(), IgnitionAuthOptions{}, SourceIPOptions{})
+		return rec
+	}
+
+	storedConfig := func() *bootv1alpha1.IPXEBootConfig {
+		config := &bootv1alpha1.IPXEBootConfig{}
+		Expect(k8s.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "ipxe"}, config)).To(Succeed())
+		return config
+	}
+
+	It("serves ignition data repeatedly by default", func() {
+		setup(newConfig(""))
+		for range 3 {
+			Expect(fetchIgnition().Code).To(Equal(http.StatusOK))
+		}
+		condition := apimeta.FindStatusCondition(storedConfig().Status.Conditions, bootv1alpha1.IgnitionDataFetchedCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.ObservedGeneration).To(Equal(int64(1)))
+	})
+
+	It("serves ignition data only once with the Once policy", func() {
+		setup(newConfig(bootv1alpha1.IgnitionPolicyOnce))
+		rec := fetchIgnition()
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(ignition))
+
+		rec = fetchIgnition()
+		Expect(rec.Code).To(Equal(http.StatusGone))
+		Expect(rec.Body.String()).NotTo(ContainSubstring("ignition"))
+	})
+
+	It("serves ignition data again for a new generation", func() {
+		config := newConfig(bootv1alpha1.IgnitionPolicyOnce)
+		config.Status.Conditions = []v1.Condition{{
+			Type:               bootv1alpha1.IgnitionDataFetchedCondition,
+			Status:             v1.ConditionTrue,
+			Reason:             "IgnitionDataDelivered",
+			ObservedGeneration: 0,
+		}}
+		setup(config)
+		Expect(fetchIgnition().Code).To(Equal(http.StatusOK))
+		Expect(fetchIgnition().Code).To(Equal(http.StatusGone))
+	})
+
+	It("does not record the delivery if writing the ignition data fails", func() {
+		setup(newConfig(bootv1alpha1.IgnitionPolicyOnce))
+		rec := &failingResponseWriter{ResponseRecorder: httptest.NewRecorder()}
+		handleIgnitionIPXEBoot(rec, httptest.NewRequest(http.MethodGet, "/ignition/"+systemUUID, nil), k8s, logr.Discard(), systemUUID, IgnitionAuthOptions{}, SourceIPOptions{})
+		Expect(apimeta.FindStatusCondition(storedConfig().Status.Conditions, bootv1alpha1.IgnitionDataFetchedCondition)).To(BeNil())
+
+		Expect(fetchIgnition().Code).To(Equal(http.StatusOK))
+		Expect(fetchIgnition().Code).To(Equal(http.StatusGone))
+	})
+
+	It("serves ignition data even if the delivery cannot be recorded due to a conflict", func() {
+		setup(newConfig(bootv1alpha1.IgnitionPolicyOnce))
+		k8s = interceptor.NewClient(k8s.(client.WithWatch), interceptor.Funcs{
+			SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+				return apierrors.NewConflict(bootv1alpha1.GroupVersion.WithResource("ipxebootconfigs").GroupResource(), "ipxe", errors.New("object was modified"))
+			},
+		})
+		rec := fetchIgnition()
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(ignition))
+	})
+
+	It("records the delivery with an optimistic lock", func() {
+		setup(newConfig(bootv1alpha1.IgnitionPolicyOnce))
+		stale := storedConfig()
+		Expect(fetchIgnition().Code).To(Equal(http.StatusOK))
+
+		err := recordIgnitionDelivery(context.Background(), k8s, logr.Discard(), stale, bootv1alpha1.IgnitionPolicyOnce)
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+	})
+
+	It("serves ignition data until the OS reports readiness with the UntilReady policy", func() {
+		setup(newConfig(bootv1alpha1.IgnitionPolicyUntilReady))
+		Expect(fetchIgnition().Code).To(Equal(http.StatusOK))
+		Expect(fetchIgnition().Code).To(Equal(http.StatusOK))
+
+		Expect(reportReady(http.MethodGet).Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(reportReady(http.MethodPost).Code).To(Equal(http.StatusNoContent))
+		Expect(apimeta.IsStatusConditionTrue(storedConfig().Status.Conditions, bootv1alpha1.OSReadyCondition)).To(BeTrue())
+
+		Expect(fetchIgnition().Code).To(Equal(http.StatusGone))
+	})
+
+	It("does not accept ready reports for unknown systems", func() {
+		setup(newConfig(bootv1alpha1.IgnitionPolicyUntilReady))
+		rec := httptest.NewRecorder()
+		handleReady(rec, httptest.NewRequest(http.MethodPost, "/ready/unknown", nil), k8s, logr.Discard(), IgnitionAuthOptions{}, SourceIPOptions{})
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+})
+
+// failingResponseWriter is a ResponseWriter whose connection broke before the body was written.
+type failingResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w *failingResponseWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
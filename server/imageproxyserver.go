@@ -4,19 +4,27 @@
 package server
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/distribution/reference"
 	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/blobcache"
 	"github.com/ironcore-dev/boot-operator/internal/registry"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	"github.com/opencontainers/go-digest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -24,6 +32,7 @@ const (
 	imageKey       = "imageName"
 	layerDigestKey = "layerDigest"
 	versionKey     = "version"
+	pullSecretKey  = "pullSecret"
 	MediaTypeUKI   = "application/vnd.ironcore.image.uki"
 )
 
@@ -32,6 +41,7 @@ type AuthMethod int
 const (
 	AuthNone   AuthMethod = iota // Anonymous access
 	AuthBearer                   // Bearer token via /token endpoint
+	AuthBasic                    // HTTP basic auth with pull secret credentials
 )
 
 type RegistryInfo struct {
@@ -53,6 +63,9 @@ type ImageDetails struct {
 	RepositoryName string
 	LayerDigest    string
 	Version        string
+	// PullSecret overrides the global registry pull secret. It must be referenced by a
+	// ServerBootConfiguration booting an image from the same repository.
+	PullSecret *client.ObjectKey
 }
 
 // registryCacheEntry holds registry info with expiration timestamp
@@ -93,6 +106,40 @@ var httpClient = &http.Client{
 	}(),
 }
 
+// Client for filling the blob cache. It shares the transport of httpClient and follows
+// redirects to blob storage itself, applying the same credential stripping rules as
+// the reverse proxy.
+var blobClient = &http.Client{
+	Transport: httpClient.Transport,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		prev := via[len(via)-1]
+		isSchemeDowngrade := prev.URL.Scheme == "https" && req.URL.Scheme == "http"
+		if !isSameHost(prev.URL, req.URL) || isSchemeDowngrade {
+			for _, header := range []string{"Authorization", "Cookie", "Proxy-Authorization"} {
+				req.Header.Del(header)
+			}
+		}
+		return nil
+	},
+}
+
+// upstreamStatusError is returned when the registry answers a blob request with
+// a non-success status, so that the status can be passed on to the client.
+type upstreamStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *upstreamStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("blob request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("blob request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
 // Parse WWW-Authenticate parameter value
 func extractParam(header, param string) string {
 	start := strings.Index(header, param+"=\"")
@@ -155,6 +202,10 @@ func detectRegistryAuth(registryDomain, repository string) (*RegistryInfo, error
 			info.TokenURL = extractTokenURL(authHeader, repository)
 			return info, nil
 		}
+		if len(authHeader) >= 5 && strings.EqualFold(authHeader[:5], "basic") {
+			info.AuthMethod = AuthBasic
+			return info, nil
+		}
 
 		return nil, fmt.Errorf("unsupported auth: %s", authHeader)
 
@@ -194,9 +245,15 @@ func getOrDetectRegistry(registry, repository string) (*RegistryInfo, error) {
 	return info, nil
 }
 
-// Get bearer token from token URL
-func getBearerToken(tokenURL string) (string, error) {
-	resp, err := httpClient.Get(tokenURL)
+// Get bearer token from token URL. If creds is set, the token is requested with the
+// pull secret credentials: username/password via basic auth, or an identity token via
+// the OAuth2 refresh token grant. Otherwise an anonymous token is requested.
+func getBearerToken(tokenURL string, creds *registry.Credentials) (string, error) {
+	req, err := newTokenRequest(tokenURL, creds)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -233,6 +290,41 @@ func getBearerToken(tokenURL string) (string, error) {
 	return "", fmt.Errorf("token response missing both 'token' and 'access_token' fields")
 }
 
+// newTokenRequest builds the token endpoint request for the given credentials.
+func newTokenRequest(tokenURL string, creds *registry.Credentials) (*http.Request, error) {
+	if creds == nil || creds.IdentityToken == "" {
+		req, err := http.NewRequest(http.MethodGet, tokenURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if creds != nil && creds.Username != "" {
+			req.SetBasicAuth(creds.Username, creds.Password)
+		}
+		return req, nil
+	}
+
+	// Identity tokens are exchanged via POST to the realm, carrying the
+	// service and scope from the token URL query as form parameters.
+	parsed, err := url.Parse(tokenURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token URL: %w", err)
+	}
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", creds.IdentityToken)
+	form.Set("client_id", "boot-operator")
+	form.Set("service", parsed.Query().Get("service"))
+	form.Set("scope", parsed.Query().Get("scope"))
+	parsed.RawQuery = ""
+
+	req, err := http.NewRequest(http.MethodPost, parsed.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
 // cleanupExpiredCacheEntries periodically removes expired entries from the registry cache
 // to prevent unbounded memory growth. Runs every 5 minutes.
 func cleanupExpiredCacheEntries(log logr.Logger) {
@@ -263,7 +355,16 @@ func cleanupExpiredCacheEntries(log logr.Logger) {
 	}
 }
 
-func RunImageProxyServer(imageProxyServerAddr string, k8sClient client.Client, validator *registry.Validator, log logr.Logger) {
+func RunImageProxyServer(
+	imageProxyServerAddr string,
+	k8sClient client.Client,
+	validator *registry.Validator,
+	credentialStore *registry.CredentialStore,
+	cache *blobcache.Cache,
+	signerSource *SignerSource,
+	tlsOptions *TLSOptions,
+	log logr.Logger,
+) {
 	// Start background cleanup of expired cache entries
 	go cleanupExpiredCacheEntries(log)
 
@@ -275,24 +376,40 @@ func RunImageProxyServer(imageProxyServerAddr string, k8sClient client.Client, v
 			return
 		}
 
-		handleDockerRegistry(w, r, &imageDetails, validator, log)
+		handleDockerRegistry(w, r, &imageDetails, k8sClient, validator, credentialStore, cache, log)
 	})
 
+	if signerSource != nil {
+		http.HandleFunc("/image"+signatureSuffix, func(w http.ResponseWriter, r *http.Request) {
+			imageDetails, err := parseImageURL(r.URL.Query())
+			if err != nil {
+				http.Error(w, "Resource Not Found", http.StatusNotFound)
+				log.Info("Error: Failed to parse the image url", "URL", r.URL.Path, "Error", err)
+				return
+			}
+
+			serveImageSignature(w, r, k8sClient, log, signerSource, &imageDetails)
+		})
+	}
+
 	http.HandleFunc("/httpboot/", func(w http.ResponseWriter, r *http.Request) {
 		log.Info("Processing HTTPBoot request", "method", r.Method, "path", r.URL.Path, "clientIP", r.RemoteAddr)
 
 		imageDetails, err := parseHttpBootImagePath(r.URL.Path)
+		if err == nil {
+			imageDetails.PullSecret, err = parsePullSecret(r.URL.Query())
+		}
 		if err != nil {
 			http.Error(w, "Resource Not Found", http.StatusNotFound)
 			log.Info("Error: Failed to parse the image path", "URL", r.URL.Path, "Error", err)
 			return
 		}
 
-		handleDockerRegistry(w, r, &imageDetails, validator, log)
+		handleDockerRegistry(w, r, &imageDetails, k8sClient, validator, credentialStore, cache, log)
 	})
 
 	log.Info("Starting image proxy server", "address", imageProxyServerAddr)
-	if err := http.ListenAndServe(imageProxyServerAddr, nil); err != nil {
+	if err := listenAndServe(imageProxyServerAddr, nil, tlsOptions, log); err != nil {
 		log.Error(err, "failed to start image proxy server")
 		panic(err)
 	}
@@ -334,7 +451,16 @@ func parseHttpBootImagePath(path string) (ImageDetails, error) {
 	}, nil
 }
 
-func handleDockerRegistry(w http.ResponseWriter, r *http.Request, imageDetails *ImageDetails, validator *registry.Validator, log logr.Logger) {
+func handleDockerRegistry(
+	w http.ResponseWriter,
+	r *http.Request,
+	imageDetails *ImageDetails,
+	k8sClient client.Client,
+	validator *registry.Validator,
+	credentialStore *registry.CredentialStore,
+	cache *blobcache.Cache,
+	log logr.Logger,
+) {
 	registryDomain := imageDetails.RegistryDomain
 	repository := imageDetails.RepositoryName
 
@@ -346,6 +472,23 @@ func handleDockerRegistry(w http.ResponseWriter, r *http.Request, imageDetails *
 		return
 	}
 
+	// Only honor pull secret overrides that a ServerBootConfiguration requested for this image
+	if imageDetails.PullSecret != nil {
+		if err := authorizePullSecret(r.Context(), k8sClient, imageDetails); err != nil {
+			http.Error(w, "Forbidden: Pull secret not allowed", http.StatusForbidden)
+			log.Info("Pull secret rejected", "pullSecret", imageDetails.PullSecret, "image", imageDetails.OCIImageName, "reason", err.Error())
+			return
+		}
+	}
+
+	// Serve blobs already granted to this repository and pull secret without contacting the registry
+	cacheDigest, cacheable := cacheableBlob(r, cache, imageDetails)
+	scope := blobScope(imageDetails)
+	if cacheable && cache.Granted(cacheDigest, scope) && serveCachedBlob(w, r, cache, cacheDigest, log) {
+		log.V(1).Info("Served blob from cache", "digest", cacheDigest)
+		return
+	}
+
 	// Auto-detect auth method (with caching)
 	registryInfo, err := getOrDetectRegistry(registryDomain, repository)
 	if err != nil {
@@ -354,31 +497,56 @@ func handleDockerRegistry(w http.ResponseWriter, r *http.Request, imageDetails *
 		return
 	}
 
-	// Get auth token if needed
-	var authToken string
+	// Look up pull secret credentials for the registry, if any are configured
+	keychain, err := credentialStore.Keychain(r.Context(), imageDetails.OCIImageName, imageDetails.PullSecret)
+	if err != nil {
+		http.Error(w, "Registry credentials unavailable", http.StatusBadGateway)
+		log.Error(err, "Failed to get registry credentials", "registry", registryDomain)
+		return
+	}
+	var creds *registry.Credentials
+	if c, ok := keychain.Lookup(registryDomain); ok {
+		creds = &c
+	}
+
+	// Get auth header if needed
+	var authHeader string
 	switch registryInfo.AuthMethod {
 	case AuthBearer:
-		authToken, err = getBearerToken(registryInfo.TokenURL)
+		authToken, err := getBearerToken(registryInfo.TokenURL, creds)
 		if err != nil {
 			http.Error(w, "Authentication failed", http.StatusUnauthorized)
 			log.Error(err, "Failed to get bearer token", "tokenURL", registryInfo.TokenURL)
 			return
 		}
-		log.V(1).Info("Obtained bearer token", "registry", registryDomain)
+		authHeader = "Bearer " + authToken
+		log.V(1).Info("Obtained bearer token", "registry", registryDomain, "authenticated", creds != nil)
+	case AuthBasic:
+		if creds == nil || creds.Username == "" {
+			http.Error(w, "Authentication failed", http.StatusUnauthorized)
+			log.Info("Registry requires basic auth but no pull secret credentials are configured", "registry", registryDomain)
+			return
+		}
+		authHeader = "Basic " + base64.StdEncoding.EncodeToString([]byte(creds.Username+":"+creds.Password))
 	case AuthNone:
 		log.V(1).Info("Registry allows anonymous access", "registry", registryDomain)
 	}
 
+	if cacheable && serveBlobThroughCache(w, r, cache, cacheDigest, scope, registryDomain, repository, authHeader, log) {
+		return
+	}
+
 	// Proxy the blob request
-	digest := imageDetails.LayerDigest
+	layerDigest := imageDetails.LayerDigest
 	proxyURL := &url.URL{
 		Scheme: "https",
 		Host:   registryDomain,
-		Path:   fmt.Sprintf("/v2/%s/blobs/%s", repository, digest),
+		Path:   fmt.Sprintf("/v2/%s/blobs/%s", repository, layerDigest),
 	}
 
 	proxy := &httputil.ReverseProxy{
-		Director:       buildDirector(proxyURL, authToken, repository, digest),
+		Transport:      httpClient.Transport,
+		Director:       buildDirector(proxyURL, authHeader, repository, layerDigest),
 		ModifyResponse: buildModifyResponse(),
 	}
 
@@ -390,13 +558,193 @@ func handleDockerRegistry(w http.ResponseWriter, r *http.Request, imageDetails *
 	proxy.ServeHTTP(w, r)
 }
 
-func buildDirector(proxyURL *url.URL, bearerToken string, repository string, digest string) func(*http.Request) {
+// cacheableBlob reports whether the request can be answered from the blob cache.
+// Only GET and HEAD requests for well-formed digests are cached.
+func cacheableBlob(r *http.Request, cache *blobcache.Cache, imageDetails *ImageDetails) (digest.Digest, bool) {
+	if cache == nil || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return "", false
+	}
+	dgst, err := digest.Parse(imageDetails.LayerDigest)
+	if err != nil {
+		return "", false
+	}
+	return dgst, true
+}
+
+// blobScope identifies the repository and credentials a blob is requested with. A cached
+// blob is only served to a scope after the registry has granted that scope access to it.
+func blobScope(imageDetails *ImageDetails) string {
+	scope := imageDetails.RegistryDomain + "/" + imageDetails.RepositoryName
+	if imageDetails.PullSecret != nil {
+		scope += "#" + imageDetails.PullSecret.String()
+	}
+	return scope
+}
+
+// serveBlobThroughCache answers a request for a blob not yet granted to the scope. The
+// registry is asked whether the scope may access the blob; if so, a cached blob is served
+// directly, and a missing blob is downloaded into the cache while it is streamed to the
+// client. It returns false if the request should be proxied instead.
+func serveBlobThroughCache(
+	w http.ResponseWriter,
+	r *http.Request,
+	cache *blobcache.Cache,
+	dgst digest.Digest,
+	scope, registryDomain, repository, authHeader string,
+	log logr.Logger,
+) bool {
+	if err := checkBlobAccess(r.Context(), registryDomain, repository, dgst, authHeader); err != nil {
+		writeUpstreamError(w, err, log, "Registry denied access to blob", "registry", registryDomain, "digest", dgst)
+		return true
+	}
+
+	if cache.Has(dgst) {
+		cache.Grant(dgst, scope)
+		if serveCachedBlob(w, r, cache, dgst, log) {
+			log.V(1).Info("Served blob from cache", "digest", dgst)
+			return true
+		}
+	}
+
+	fetch := blobFetcher(registryDomain, repository, dgst, authHeader)
+	if r.Method != http.MethodGet || r.Header.Get("Range") != "" {
+		// Partial requests are proxied; fill the cache in the background for later requests.
+		if r.Method == http.MethodGet {
+			go func() {
+				if err := cache.Fill(context.WithoutCancel(r.Context()), dgst, fetch); err != nil {
+					log.Error(err, "Failed to fill blob cache", "digest", dgst)
+				}
+			}()
+		}
+		return false
+	}
+
+	body, size, err := cache.Stream(r.Context(), dgst, fetch)
+	if errors.Is(err, blobcache.ErrTooLarge) {
+		log.Info("Blob exceeds cache size limit, proxying without caching", "digest", dgst)
+		return false
+	}
+	if err != nil {
+		writeUpstreamError(w, err, log, "Failed to fill blob cache", "registry", registryDomain, "digest", dgst)
+		return true
+	}
+	defer func() { _ = body.Close() }()
+
+	setBlobHeaders(w, r, dgst)
+	if size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		// The client must not mistake a truncated or unverified blob for a complete one.
+		log.Error(err, "Failed to stream blob", "digest", dgst)
+		panic(http.ErrAbortHandler)
+	}
+	cache.Grant(dgst, scope)
+	log.Info("Streamed blob into cache", "digest", dgst)
+	return true
+}
+
+// writeUpstreamError passes registry rejections on to the client and reports other
+// failures as a bad gateway.
+func writeUpstreamError(w http.ResponseWriter, err error, log logr.Logger, msg string, keysAndValues ...any) {
+	var statusErr *upstreamStatusError
+	if errors.As(err, &statusErr) {
+		http.Error(w, http.StatusText(statusErr.StatusCode), statusErr.StatusCode)
+	} else {
+		http.Error(w, "Failed to fetch blob", http.StatusBadGateway)
+	}
+	log.Error(err, msg, keysAndValues...)
+}
+
+// checkBlobAccess asks the registry whether the blob may be pulled with the given credentials.
+func checkBlobAccess(ctx context.Context, registryDomain, repository string, dgst digest.Digest, authHeader string) error {
+	blobURL := fmt.Sprintf("https://%s/v2/%s/blobs/%s", registryDomain, repository, dgst)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, blobURL, nil)
+	if err != nil {
+		return err
+	}
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+	resp, err := blobClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return &upstreamStatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// serveCachedBlob writes a cached blob to the response, honoring Range and
+// conditional requests. It returns false on a cache miss.
+func serveCachedBlob(w http.ResponseWriter, r *http.Request, cache *blobcache.Cache, dgst digest.Digest, log logr.Logger) bool {
+	file, ok, err := cache.Open(dgst)
+	if err != nil {
+		log.Error(err, "Failed to open cached blob", "digest", dgst)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		log.Error(err, "Failed to stat cached blob", "digest", dgst)
+		return false
+	}
+
+	setBlobHeaders(w, r, dgst)
+	http.ServeContent(w, r, "", info.ModTime(), file)
+	return true
+}
+
+func setBlobHeaders(w http.ResponseWriter, r *http.Request, dgst digest.Digest) {
+	contentType := "application/octet-stream"
+	if strings.HasPrefix(r.URL.Path, "/httpboot/") {
+		contentType = "application/efi"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Docker-Content-Digest", dgst.String())
+	// Blobs are immutable, so the digest is a strong validator for If-Range requests.
+	w.Header().Set("ETag", `"`+dgst.String()+`"`)
+}
+
+// blobFetcher returns a FetchFunc downloading a blob from the registry.
+func blobFetcher(registryDomain, repository string, dgst digest.Digest, authHeader string) blobcache.FetchFunc {
+	return func(ctx context.Context) (io.ReadCloser, int64, error) {
+		blobURL := fmt.Sprintf("https://%s/v2/%s/blobs/%s", registryDomain, repository, dgst)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, blobURL, nil)
+		if err != nil {
+			return nil, 0, err
+		}
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+
+		resp, err := blobClient.Do(req)
+		if err != nil {
+			return nil, 0, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			defer func() { _ = resp.Body.Close() }()
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorResponseSize))
+			return nil, 0, &upstreamStatusError{StatusCode: resp.StatusCode, Body: string(body)}
+		}
+		return resp.Body, resp.ContentLength, nil
+	}
+}
+
+func buildDirector(proxyURL *url.URL, authHeader string, repository string, layerDigest string) func(*http.Request) {
 	return func(req *http.Request) {
 		req.URL.Scheme = proxyURL.Scheme
 		req.URL.Host = proxyURL.Host
-		req.URL.Path = fmt.Sprintf("/v2/%s/blobs/%s", repository, digest)
-		if bearerToken != "" {
-			req.Header.Set("Authorization", "Bearer "+bearerToken)
+		req.URL.Path = fmt.Sprintf("/v2/%s/blobs/%s", repository, layerDigest)
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
 		}
 	}
 }
@@ -540,11 +888,52 @@ func parseImageURL(queries url.Values) (imageDetails ImageDetails, err error) {
 	}
 	repositoryName := reference.Path(named)
 
+	pullSecret, err := parsePullSecret(queries)
+	if err != nil {
+		return ImageDetails{}, err
+	}
+
 	return ImageDetails{
 		OCIImageName:   ociImageName,
 		RegistryDomain: registryDomain,
 		RepositoryName: repositoryName,
 		LayerDigest:    layerDigest,
 		Version:        version,
+		PullSecret:     pullSecret,
 	}, nil
 }
+
+// parsePullSecret parses the optional namespace/name pull secret reference of a request.
+func parsePullSecret(queries url.Values) (*client.ObjectKey, error) {
+	value := queries.Get(pullSecretKey)
+	if value == "" {
+		return nil, nil
+	}
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid pull secret %q, expected namespace/name", value)
+	}
+	return &client.ObjectKey{Namespace: namespace, Name: name}, nil
+}
+
+// authorizePullSecret checks that a ServerBootConfiguration in the pull secret's namespace
+// references the Secret for an image of the requested repository. Without this check, any
+// client could make the proxy pull with credentials it was never meant to use.
+func authorizePullSecret(ctx context.Context, k8sClient client.Client, imageDetails *ImageDetails) error {
+	repository, err := registry.RepositoryKey(imageDetails.OCIImageName)
+	if err != nil {
+		return err
+	}
+
+	bootConfigs := &metalv1alpha1.ServerBootConfigurationList{}
+	if err := k8sClient.List(ctx, bootConfigs, client.InNamespace(imageDetails.PullSecret.Namespace),
+		client.MatchingFields{bootv1alpha1.ImagePullSecretIndexKey: imageDetails.PullSecret.Name}); err != nil {
+		return fmt.Errorf("failed to list ServerBootConfigurations: %w", err)
+	}
+	for _, bootConfig := range bootConfigs.Items {
+		if configRepository, err := registry.RepositoryKey(bootConfig.Spec.Image); err == nil && configRepository == repository {
+			return nil
+		}
+	}
+	return fmt.Errorf("no ServerBootConfiguration references the pull secret for repository %s", repository)
+}
@@ -0,0 +1,272 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/blobcache"
+	"github.com/ironcore-dev/boot-operator/internal/registry"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ImageProxyServer", func() {
+	Context("getBearerToken", func() {
+		var (
+			tokenServer *httptest.Server
+			lastRequest *http.Request
+			lastForm    map[string][]string
+		)
+
+		BeforeEach(func() {
+			tokenServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.ParseForm()).To(Succeed())
+				lastRequest = r
+				lastForm = r.PostForm
+				_ = json.NewEncoder(w).Encode(TokenResponse{Token: "issued"})
+			}))
+			DeferCleanup(tokenServer.Close)
+		})
+
+		It("requests an anonymous token without credentials", func() {
+			token, err := getBearerToken(tokenServer.URL+"?service=registry&scope=repository:foo:pull", nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("issued"))
+			Expect(lastRequest.Method).To(Equal(http.MethodGet))
+			Expect(lastRequest.Header.Get("Authorization")).To(BeEmpty())
+		})
+
+		It("uses basic auth for username/password credentials", func() {
+			_, err := getBearerToken(tokenServer.URL+"?service=registry&scope=repository:foo:pull",
+				&registry.Credentials{Username: "robot", Password: "s3cret"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastRequest.Method).To(Equal(http.MethodGet))
+			user, pass, ok := lastRequest.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(user).To(Equal("robot"))
+			Expect(pass).To(Equal("s3cret"))
+		})
+
+		It("exchanges an identity token via the refresh token grant", func() {
+			_, err := getBearerToken(tokenServer.URL+"?service=registry&scope=repository:foo:pull",
+				&registry.Credentials{IdentityToken: "refresh"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastRequest.Method).To(Equal(http.MethodPost))
+			Expect(lastForm).To(HaveKeyWithValue("grant_type", []string{"refresh_token"}))
+			Expect(lastForm).To(HaveKeyWithValue("refresh_token", []string{"refresh"}))
+			Expect(lastForm).To(HaveKeyWithValue("service", []string{"registry"}))
+			Expect(lastForm).To(HaveKeyWithValue("scope", []string{"repository:foo:pull"}))
+		})
+	})
+	Context("serveCachedBlob", func() {
+		var (
+			cache *blobcache.Cache
+			data  = []byte("0123456789")
+			dgst  = digest.FromBytes(data)
+		)
+
+		BeforeEach(func() {
+			var err error
+			cache, err = blobcache.New(GinkgoT().TempDir(), 1024)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cache.Fill(context.Background(), dgst, func(context.Context) (io.ReadCloser, int64, error) {
+				return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+			})).To(Succeed())
+		})
+
+		It("serves a cached blob", func() {
+			req := httptest.NewRequest(http.MethodGet, "/httpboot/ghcr.io/foo/sha256-abc.efi", nil)
+			rec := httptest.NewRecorder()
+			Expect(serveCachedBlob(rec, req, cache, dgst, logr.Discard())).To(BeTrue())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(Equal("0123456789"))
+			Expect(rec.Header().Get("Content-Type")).To(Equal("application/efi"))
+			Expect(rec.Header().Get("Docker-Content-Digest")).To(Equal(dgst.String()))
+		})
+
+		It("serves byte ranges", func() {
+			req := httptest.NewRequest(http.MethodGet, "/image", nil)
+			req.Header.Set("Range", "bytes=2-5")
+			rec := httptest.NewRecorder()
+			Expect(serveCachedBlob(rec, req, cache, dgst, logr.Discard())).To(BeTrue())
+			Expect(rec.Code).To(Equal(http.StatusPartialContent))
+			Expect(rec.Body.String()).To(Equal("2345"))
+			Expect(rec.Header().Get("Content-Range")).To(Equal("bytes 2-5/10"))
+		})
+
+		It("reports a cache miss", func() {
+			req := httptest.NewRequest(http.MethodGet, "/image", nil)
+			rec := httptest.NewRecorder()
+			Expect(serveCachedBlob(rec, req, cache, digest.FromString("missing"), logr.Discard())).To(BeFalse())
+		})
+	})
+	Context("authorizePullSecret", func() {
+		newBootConfig := func(name, image, pullSecret string) *metalv1alpha1.ServerBootConfiguration {
+			return &metalv1alpha1.ServerBootConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "tenant",
+					Name:        name,
+					Annotations: map[string]string{bootv1alpha1.ImagePullSecretAnnotation: pullSecret},
+				},
+				Spec: metalv1alpha1.ServerBootConfigurationSpec{Image: image},
+			}
+		}
+
+		var c client.Client
+		BeforeEach(func() {
+			c = newTestClient(newBootConfig("config", "harbor.example.com/os/gardenlinux:1.0", "harbor-credentials"))
+		})
+
+		It("allows a pull secret referenced for the same repository", func() {
+			details, err := parseImageURL(url.Values{
+				imageKey:       {"harbor.example.com/os/gardenlinux"},
+				versionKey:     {"2.0"},
+				layerDigestKey: {"sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+				pullSecretKey:  {"tenant/harbor-credentials"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(details.PullSecret).To(Equal(&client.ObjectKey{Namespace: "tenant", Name: "harbor-credentials"}))
+			Expect(authorizePullSecret(context.Background(), c, &details)).To(Succeed())
+		})
+
+		It("rejects a pull secret referenced for another repository", func() {
+			details := ImageDetails{
+				OCIImageName: "harbor.example.com/os/other",
+				PullSecret:   &client.ObjectKey{Namespace: "tenant", Name: "harbor-credentials"},
+			}
+			Expect(authorizePullSecret(context.Background(), c, &details)).NotTo(Succeed())
+		})
+
+		It("rejects a pull secret from another namespace", func() {
+			details := ImageDetails{
+				OCIImageName: "harbor.example.com/os/gardenlinux",
+				PullSecret:   &client.ObjectKey{Namespace: "other", Name: "harbor-credentials"},
+			}
+			Expect(authorizePullSecret(context.Background(), c, &details)).NotTo(Succeed())
+		})
+
+		It("rejects malformed pull secret references", func() {
+			_, err := parsePullSecret(url.Values{pullSecretKey: {"harbor-credentials"}})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+	Context("handleDockerRegistry with a blob cache", func() {
+		var (
+			registryDomain string
+			blobGets       atomic.Int32
+			kernel         = []byte("kernel content")
+			kernelDigest   = digest.FromBytes(kernel)
+			squashfs       = []byte("squashfs content exceeding the cache size")
+			squashfsDigest = digest.FromBytes(squashfs)
+		)
+
+		BeforeEach(func() {
+			blobGets.Store(0)
+			blobs := map[string][]byte{
+				"/v2/os/gardenlinux/blobs/" + kernelDigest.String():   kernel,
+				"/v2/os/gardenlinux/blobs/" + squashfsDigest.String(): squashfs,
+			}
+			registryServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/v2/":
+					w.WriteHeader(http.StatusOK)
+				case strings.HasPrefix(r.URL.Path, "/v2/os/private/"):
+					w.WriteHeader(http.StatusUnauthorized)
+				case blobs[r.URL.Path] != nil:
+					if r.Method == http.MethodGet {
+						blobGets.Add(1)
+					}
+					http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(blobs[r.URL.Path]))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			DeferCleanup(registryServer.Close)
+			registryDomain = registryServer.Listener.Addr().String()
+
+			// Trust the test registry's certificate.
+			originalClient, originalBlobTransport := httpClient, blobClient.Transport
+			httpClient = registryServer.Client()
+			blobClient.Transport = registryServer.Client().Transport
+			DeferCleanup(func() {
+				httpClient = originalClient
+				blobClient.Transport = originalBlobTransport
+			})
+		})
+
+		serve := func(cache *blobcache.Cache, repository string, dgst digest.Digest, header http.Header) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/image", nil)
+			for name, values := range header {
+				req.Header[name] = values
+			}
+			rec := httptest.NewRecorder()
+			handleDockerRegistry(rec, req, &ImageDetails{
+				OCIImageName:   registryDomain + "/" + repository,
+				RegistryDomain: registryDomain,
+				RepositoryName: repository,
+				LayerDigest:    dgst.String(),
+			}, newTestClient(), registry.NewValidator(registryDomain), nil, cache, logr.Discard())
+			return rec
+		}
+
+		newCache := func(maxSize int64) *blobcache.Cache {
+			cache, err := blobcache.New(GinkgoT().TempDir(), maxSize)
+			Expect(err).NotTo(HaveOccurred())
+			return cache
+		}
+
+		It("fills the cache on a miss and serves later requests from it", func() {
+			cache := newCache(1024)
+
+			rec := serve(cache, "os/gardenlinux", kernelDigest, nil)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.Bytes()).To(Equal(kernel))
+			Expect(cache.Has(kernelDigest)).To(BeTrue())
+
+			rec = serve(cache, "os/gardenlinux", kernelDigest, http.Header{"Range": {"bytes=0-5"}})
+			Expect(rec.Code).To(Equal(http.StatusPartialContent))
+			Expect(rec.Body.String()).To(Equal("kernel"))
+			Expect(blobGets.Load()).To(BeEquivalentTo(1))
+		})
+
+		It("does not serve a cached blob to a repository the registry denies", func() {
+			cache := newCache(1024)
+			Expect(serve(cache, "os/gardenlinux", kernelDigest, nil).Code).To(Equal(http.StatusOK))
+
+			rec := serve(cache, "os/other", kernelDigest, nil)
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+			Expect(rec.Body.Bytes()).NotTo(ContainSubstring(string(kernel)))
+		})
+
+		It("passes on registry authorization failures", func() {
+			rec := serve(newCache(1024), "os/private", kernelDigest, nil)
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("proxies blobs exceeding the cache size without caching them", func() {
+			cache := newCache(16)
+
+			rec := serve(cache, "os/gardenlinux", squashfsDigest, nil)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.Bytes()).To(Equal(squashfs))
+			Expect(cache.Has(squashfsDigest)).To(BeFalse())
+		})
+	})
+})
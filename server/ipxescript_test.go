@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Custom iPXE scripts", func() {
+	const (
+		systemUUID     = "8e1f3a5c-7b9d-4e2f-a1c3-5d7e9f1b3a5c"
+		ipxeServiceURL = "http://boot.example.com"
+		script         = "#!ipxe\nkernel {{.KernelURL}} ignition.config.url={{.IPXEServerURL}}/ignition/{{.SystemUUID}}\ninitrd {{.InitrdURL}}\nboot\n"
+	)
+
+	var (
+		k8s    client.Client
+		secret *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "custom-script", Namespace: "default"},
+			Data:       map[string][]byte{bootv1alpha1.DefaultIPXEScriptKey: []byte(script)},
+		}
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8s = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				secret,
+				&bootv1alpha1.IPXEBootConfig{
+					ObjectMeta: v1.ObjectMeta{Name: "ipxe", Namespace: "default"},
+					Spec: bootv1alpha1.IPXEBootConfigSpec{
+						SystemUUID:          systemUUID,
+						KernelURL:           "http://images.example.com/kernel",
+						InitrdURL:           "http://images.example.com/initrd",
+						IPXEScriptSecretRef: &corev1.LocalObjectReference{Name: "custom-script"},
+					},
+				},
+			).
+			WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+			}).
+			Build()
+	})
+
+	fetchScript := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handleIPXE(rec, httptest.NewRequest(http.MethodGet, "/ipxe/"+systemUUID, nil), k8s, logr.Discard(), ipxeServiceURL,
+			newTestIPXETemplates(), nil, IgnitionAuthOptions{}, SourceIPOptions{})
+		return rec
+	}
+
+	fetchedCondition := func() *v1.Condition {
+		config := &bootv1alpha1.IPXEBootConfig{}
+		Expect(k8s.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "ipxe"}, config)).To(Succeed())
+		return apimeta.FindStatusCondition(config.Status.Conditions, "IPXEScriptFetched")
+	}
+
+	It("serves the script verbatim and records the fetch", func() {
+		rec := fetchScript()
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(script))
+		Expect(fetchedCondition()).To(HaveField("Status", v1.ConditionTrue))
+	})
+
+	Context("with a templated script", func() {
+		BeforeEach(func() {
+			secret.Data[bootv1alpha1.DefaultTemplateKey] = []byte("true")
+		})
+
+		It("renders the script with the values of the boot config and records the fetch", func() {
+			rec := fetchScript()
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(Equal("#!ipxe\nkernel http://images.example.com/kernel " +
+				"ignition.config.url=http://boot.example.com/ignition/" + systemUUID + "\ninitrd http://images.example.com/initrd\nboot\n"))
+			Expect(fetchedCondition()).To(HaveField("Status", v1.ConditionTrue))
+		})
+
+		It("fails for invalid templates", func() {
+			secret.Data[bootv1alpha1.DefaultIPXEScriptKey] = []byte("#!ipxe\nkernel {{.Kernel}}\n")
+			Expect(k8s.Update(context.Background(), secret)).To(Succeed())
+			Expect(fetchScript().Code).To(Equal(http.StatusInternalServerError))
+			Expect(fetchedCondition()).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("iPXE templates", func() {
+	const (
+		systemUUID     = "2b6d8f1a-4c3e-4a5b-9d7c-1e0f2a3b4c5d"
+		ipxeServiceURL = "http://boot.example.com"
+	)
+
+	var k8s client.Client
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8s = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&corev1.ConfigMap{
+					ObjectMeta: v1.ObjectMeta{Name: "serial-console", Namespace: "default"},
+					Data: map[string]string{
+						bootv1alpha1.DefaultIPXETemplateKey: "#!ipxe\nkernel {{.KernelURL}} console=ttyS1,9600 ignition.config.url={{.IPXEServerURL}}/ignition/${uuid}\ninitrd {{.InitrdURL}}\nboot\n",
+					},
+				},
+				&bootv1alpha1.IPXEBootConfig{
+					ObjectMeta: v1.ObjectMeta{Name: "ipxe", Namespace: "default"},
+					Spec: bootv1alpha1.IPXEBootConfigSpec{
+						SystemUUID:      systemUUID,
+						KernelURL:       "http://images.example.com/kernel",
+						InitrdURL:       "http://images.example.com/initrd",
+						IPXETemplateRef: &corev1.LocalObjectReference{Name: "serial-console"},
+					},
+				},
+			).
+			WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+			}).
+			Build()
+	})
+
+	fetchScript := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handleIPXE(rec, httptest.NewRequest(http.MethodGet, "/ipxe/"+systemUUID, nil), k8s, logr.Discard(), ipxeServiceURL, newTestIPXETemplates(), nil, IgnitionAuthOptions{}, SourceIPOptions{})
+		return rec
+	}
+
+	It("renders the iPXE script from the template of the ConfigMap", func() {
+		rec := fetchScript()
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("#!ipxe\nkernel http://images.example.com/kernel console=ttyS1,9600 " +
+			"ignition.config.url=http://boot.example.com/ignition/${uuid}\ninitrd http://images.example.com/initrd\nboot\n"))
+	})
+
+	It("fails if the ConfigMap is missing", func() {
+		Expect(k8s.Delete(context.Background(), &corev1.ConfigMap{ObjectMeta: v1.ObjectMeta{Name: "serial-console", Namespace: "default"}})).To(Succeed())
+		Expect(fetchScript().Code).To(Equal(http.StatusInternalServerError))
+	})
+})
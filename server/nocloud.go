@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/ignition"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// noCloudPathPrefix prefixes the paths of the NoCloud data source of cloud-init, which is seeded
+// with /nocloud/<uuid>/ or /nocloud/mac/<mac>/. cloud-init appends the file names to the seed URL,
+// so the ignition token, if any, is passed as a path segment: /nocloud/<uuid>/<token>/user-data.
+const noCloudPathPrefix = "/nocloud/"
+
+const (
+	noCloudUserData   = "user-data"
+	noCloudMetaData   = "meta-data"
+	noCloudVendorData = "vendor-data"
+)
+
+// handleNoCloud serves the files of the NoCloud data source. The user-data is the rendered ignition
+// data of the boot config and is served like ignition data. The meta-data and vendor-data are taken
+// from the ignition Secret, with the meta-data defaulting to the UID of the boot config as
+// instance-id.
+func handleNoCloud(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger,
+	ignitionAuth IgnitionAuthOptions, sourceIP SourceIPOptions) {
+	log.Info("Processing NoCloud request", "method", r.Method, "path", r.URL.Path, "clientIP", r.RemoteAddr)
+
+	var uuid, rawMAC string
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, noCloudPathPrefix), "/")
+	if len(segments) > 2 && segments[0]+"/" == macPathPrefix {
+		rawMAC, segments = segments[1], segments[2:]
+	} else {
+		uuid, segments = segments[0], segments[1:]
+	}
+	if (uuid == "" && rawMAC == "") || len(segments) == 0 || len(segments) > 2 {
+		http.Error(w, "Resource Not Found", http.StatusNotFound)
+		return
+	}
+	if len(segments) == 2 {
+		r = withIgnitionToken(r, segments[0])
+	}
+
+	switch file := segments[len(segments)-1]; file {
+	case noCloudUserData:
+		if rawMAC != "" {
+			handleIgnitionByMAC(w, r, k8sClient, log, rawMAC, ignitionAuth, sourceIP)
+		} else {
+			handleIgnition(w, r, k8sClient, log, uuid, ignitionAuth, sourceIP)
+		}
+	case noCloudMetaData, noCloudVendorData:
+		serveNoCloudData(w, r, k8sClient, log, uuid, rawMAC, file, ignitionAuth, sourceIP)
+	default:
+		http.Error(w, "Resource Not Found", http.StatusNotFound)
+	}
+}
+
+// withIgnitionToken returns a copy of r that carries the ignition token in its query.
+func withIgnitionToken(r *http.Request, token string) *http.Request {
+	r = r.Clone(r.Context())
+	query := r.URL.Query()
+	query.Set(ignitionTokenParam, token)
+	r.URL.RawQuery = query.Encode()
+	return r
+}
+
+// serveNoCloudData serves the meta-data or vendor-data of the boot config with the given SystemUUID
+// or MAC address.
+func serveNoCloudData(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, uuid, rawMAC, file string,
+	ignitionAuth IgnitionAuthOptions, sourceIP SourceIPOptions) {
+	ctx := r.Context()
+
+	var config client.Object
+	var err error
+	if rawMAC != "" {
+		mac, parseErr := net.ParseMAC(rawMAC)
+		if parseErr != nil {
+			http.Error(w, "Bad Request: invalid MAC address", http.StatusBadRequest)
+			return
+		}
+		config, err = findBootConfigByMAC(ctx, k8sClient, log, mac)
+	} else {
+		config, err = findBootConfig(r, k8sClient, log, uuid, sourceIP.TrustedProxies)
+	}
+	if err != nil {
+		log.Error(err, "Failed to find boot config")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if config == nil {
+		log.Info("No boot config found for NoCloud request")
+		http.Error(w, "Resource Not Found", http.StatusNotFound)
+		return
+	}
+
+	token, networkIdentifiers, secretRef := bootConfigIgnition(config)
+	if err := verifySourceAddress(r, sourceIP, log, config, networkIdentifiers); err != nil {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if err := authorizeIgnitionRequest(r, ignitionAuth, token, networkIdentifiers, sourceIP.TrustedProxies, time.Now()); err != nil {
+		log.Info("Rejected NoCloud request", "config", client.ObjectKeyFromObject(config), "reason", err.Error())
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	secret := &corev1.Secret{}
+	if secretRef != nil {
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: config.GetNamespace(), Name: secretRef.Name}, secret); err != nil {
+			log.Info("Failed to get the ignition Secret", "error", err.Error())
+			http.Error(w, "Resource Not Found", http.StatusNotFound)
+			return
+		}
+	}
+
+	var data []byte
+	switch file {
+	case noCloudMetaData:
+		var ok bool
+		if data, ok = secret.Data[bootv1alpha1.DefaultMetaDataKey]; !ok {
+			data = fmt.Appendf(nil, "instance-id: %s\n", config.GetUID())
+		}
+	case noCloudVendorData:
+		data = secret.Data[bootv1alpha1.DefaultVendorDataKey]
+	}
+	if len(data) > 0 && ignition.IsTemplate(secret) {
+		if data, err = renderIgnitionTemplate(ctx, k8sClient, config, data); err != nil {
+			log.Info("Failed to render the NoCloud template", "config", client.ObjectKeyFromObject(config), "file", file, "error", err.Error())
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := w.Write(data); err != nil {
+		log.Info("Failed to write the NoCloud response", "error", err)
+	}
+}
+
+// findBootConfigByMAC returns the preferred IPXEBootConfig with the given MAC address among its
+// SystemMACs or, if there is none, the preferred HTTPBootConfig with it among its
+// NetworkIdentifiers. It returns nil if neither exists.
+func findBootConfigByMAC(ctx context.Context, k8sClient client.Client, log logr.Logger, mac net.HardwareAddr) (client.Object, error) {
+	ipxeBootConfigList := &bootv1alpha1.IPXEBootConfigList{}
+	if err := k8sClient.List(ctx, ipxeBootConfigList, client.MatchingFields{bootv1alpha1.SystemMACIndexKey: mac.String()}); err != nil {
+		return nil, fmt.Errorf("failed to list IPXEBootConfigs: %w", err)
+	}
+	if len(ipxeBootConfigList.Items) > 0 {
+		return selectBootConfig(ctx, k8sClient, log, toPointers(ipxeBootConfigList.Items))
+	}
+
+	httpBootConfigList := &bootv1alpha1.HTTPBootConfigList{}
+	if err := k8sClient.List(ctx, httpBootConfigList, client.MatchingFields{bootv1alpha1.NetworkIdentifierIndexKey: mac.String()}); err != nil {
+		return nil, fmt.Errorf("failed to list HTTPBootConfigs: %w", err)
+	}
+	if len(httpBootConfigList.Items) > 0 {
+		return selectBootConfig(ctx, k8sClient, log, toPointers(httpBootConfigList.Items))
+	}
+	return nil, nil
+}
+
+// bootConfigIgnition returns the ignition token, the network identifiers and the ignition Secret of
+// a boot config.
+func bootConfigIgnition(config client.Object) (*bootv1alpha1.IgnitionToken, []string, *corev1.LocalObjectReference) {
+	switch resource := config.(type) {
+	case *bootv1alpha1.IPXEBootConfig:
+		return resource.Status.IgnitionToken, resource.Spec.SystemIPs, resource.Spec.IgnitionSecretRef
+	case *bootv1alpha1.HTTPBootConfig:
+		return resource.Status.IgnitionToken, resource.Spec.NetworkIdentifiers, resource.Spec.IgnitionSecretRef
+	default:
+		return nil, nil, nil
+	}
+}
+
+// bootConfigIgnitionLayers returns the ignition layers of a boot config.
+func bootConfigIgnitionLayers(config client.Object) []bootv1alpha1.IgnitionLayer {
+	switch resource := config.(type) {
+	case *bootv1alpha1.IPXEBootConfig:
+		return resource.Spec.IgnitionLayers
+	case *bootv1alpha1.HTTPBootConfig:
+		return resource.Spec.IgnitionLayers
+	default:
+		return nil
+	}
+}
+
+// usesNoCloud reports whether the ignition Secret of a boot config holds data of a cloud-init
+// format, which the booted OS fetches from the NoCloud data source.
+func usesNoCloud(ctx context.Context, k8sClient client.Client, namespace string, secretRef *corev1.LocalObjectReference) (bool, error) {
+	if secretRef == nil {
+		return false, nil
+	}
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: secretRef.Name}, secret); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	format, ok := ignition.LookupFormat(string(secret.Data[bootv1alpha1.DefaultFormatKey]))
+	return ok && format.CloudInit, nil
+}
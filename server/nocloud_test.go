@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/netid"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("NoCloud data source", func() {
+	const (
+		systemUUID = "7c4e1a9b-3d2f-4b8e-a5c6-9e0d1f2a3b4c"
+		systemMAC  = "52:54:00:12:34:56"
+		token      = "n0cl0ud-t0k3n"
+		userData   = "#cloud-config\nhostname: node-1\n"
+		vendorData = "#cloud-config\npackages: [curl]\n"
+	)
+
+	var (
+		k8s    client.Client
+		auth   IgnitionAuthOptions
+		secret *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		auth = IgnitionAuthOptions{}
+		secret = &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "cloud-init", Namespace: "default"},
+			Data: map[string][]byte{
+				bootv1alpha1.DefaultFormatKey:     []byte(bootv1alpha1.CloudInitFormat),
+				bootv1alpha1.DefaultIgnitionKey:   []byte(userData),
+				bootv1alpha1.DefaultVendorDataKey: []byte(vendorData),
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		ipxeBootConfig := &bootv1alpha1.IPXEBootConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "ipxe", Namespace: "default", UID: types.UID("ipxe-uid")},
+			Spec: bootv1alpha1.IPXEBootConfigSpec{
+				SystemUUID:        systemUUID,
+				SystemIPs:         []string{"10.0.0.10"},
+				SystemMACs:        []string{systemMAC},
+				IgnitionSecretRef: &corev1.LocalObjectReference{Name: "cloud-init"},
+			},
+			Status: bootv1alpha1.IPXEBootConfigStatus{IgnitionToken: &bootv1alpha1.IgnitionToken{
+				Token:              token,
+				ExpirationTime:     v1.NewTime(time.Now().Add(time.Hour)),
+				ObservedGeneration: 1,
+			}},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8s = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(secret, ipxeBootConfig).
+			WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+			}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemMACIndexKey, func(obj client.Object) []string {
+				return netid.CanonicalAll(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemMACs)
+			}).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.HTTPBootConfig).Spec.SystemUUID)}
+			}).
+			Build()
+	})
+
+	fetch := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "10.0.0.10:1234"
+		rec := httptest.NewRecorder()
+		handleNoCloud(rec, req, k8s, logr.Discard(), auth, SourceIPOptions{})
+		return rec
+	}
+
+	DescribeTable("serves the NoCloud files",
+		func(path, want string) {
+			rec := fetch(path)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(Equal(want))
+		},
+		Entry("user-data", "/nocloud/"+systemUUID+"/user-data", userData),
+		Entry("meta-data", "/nocloud/"+systemUUID+"/meta-data", "instance-id: ipxe-uid\n"),
+		Entry("vendor-data", "/nocloud/"+systemUUID+"/vendor-data", vendorData),
+		Entry("user-data by MAC address", "/nocloud/mac/"+systemMAC+"/user-data", userData),
+		Entry("meta-data by MAC address", "/nocloud/mac/"+systemMAC+"/meta-data", "instance-id: ipxe-uid\n"),
+	)
+
+	It("does not serve unknown files", func() {
+		Expect(fetch("/nocloud/" + systemUUID + "/network-config").Code).To(Equal(http.StatusNotFound))
+		Expect(fetch("/nocloud/" + systemUUID).Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("takes the ignition token from the path", func() {
+		auth = IgnitionAuthOptions{RequireToken: true}
+		Expect(fetch("/nocloud/" + systemUUID + "/user-data").Code).To(Equal(http.StatusUnauthorized))
+		Expect(fetch("/nocloud/" + systemUUID + "/meta-data").Code).To(Equal(http.StatusUnauthorized))
+		Expect(fetch("/nocloud/" + systemUUID + "/guessed/meta-data").Code).To(Equal(http.StatusUnauthorized))
+		Expect(fetch("/nocloud/" + systemUUID + "/" + token + "/user-data").Code).To(Equal(http.StatusOK))
+		Expect(fetch("/nocloud/" + systemUUID + "/" + token + "/meta-data").Code).To(Equal(http.StatusOK))
+	})
+
+	Context("with autoinstall data", func() {
+		BeforeEach(func() {
+			secret.Data = map[string][]byte{
+				bootv1alpha1.DefaultFormatKey:   []byte(bootv1alpha1.AutoinstallFormat),
+				bootv1alpha1.DefaultIgnitionKey: []byte("version: 1\nidentity:\n  hostname: node-1\n"),
+				bootv1alpha1.DefaultMetaDataKey: []byte("instance-id: node-1\n"),
+			}
+		})
+
+		It("wraps the autoinstall config into cloud-config user-data", func() {
+			rec := fetch("/nocloud/" + systemUUID + "/user-data")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(HavePrefix("#cloud-config\nautoinstall:\n"))
+			Expect(rec.Body.String()).To(ContainSubstring("hostname: node-1"))
+		})
+
+		It("serves the meta-data of the Secret", func() {
+			rec := fetch("/nocloud/" + systemUUID + "/meta-data")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(Equal("instance-id: node-1\n"))
+		})
+	})
+
+	Context("with templated data", func() {
+		BeforeEach(func() {
+			secret.Data = map[string][]byte{
+				bootv1alpha1.DefaultFormatKey:   []byte(bootv1alpha1.CloudInitFormat),
+				bootv1alpha1.DefaultTemplateKey: []byte("true"),
+				bootv1alpha1.DefaultIgnitionKey: []byte("#cloud-config\nwrite_files:\n- path: /etc/macs\n  content: {{join \",\" .MACs}}\n"),
+				bootv1alpha1.DefaultMetaDataKey: []byte("instance-id: {{.SystemUUID}}\n"),
+			}
+		})
+
+		It("renders the data with the values of the boot config", func() {
+			rec := fetch("/nocloud/" + systemUUID + "/user-data")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(ContainSubstring("content: " + systemMAC + "\n"))
+
+			rec = fetch("/nocloud/" + systemUUID + "/meta-data")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(Equal("instance-id: " + systemUUID + "\n"))
+		})
+
+		It("fails for invalid templates", func() {
+			secret.Data[bootv1alpha1.DefaultIgnitionKey] = []byte("#cloud-config\n{{.Labels.missing}}\n")
+			Expect(k8s.Update(context.Background(), secret)).To(Succeed())
+			Expect(fetch("/nocloud/" + systemUUID + "/user-data").Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	It("seeds the NoCloud data source in the default iPXE script", func() {
+		tmpl, err := template.ParseFiles("../templates/ipxe-script.tpl")
+		Expect(err).NotTo(HaveOccurred())
+		var script bytes.Buffer
+		Expect(tmpl.Execute(&script, IPXETemplateData{
+			KernelURL:     "http://example.com/kernel",
+			InitrdURL:     "http://example.com/initrd",
+			IPXEServerURL: "http://example.com",
+			IgnitionToken: token,
+			NoCloud:       true,
+		})).To(Succeed())
+		Expect(script.String()).To(ContainSubstring(" ds=nocloud;s=${ipxe-svc}/nocloud/${uuid}/" + token + "/ "))
+	})
+})
@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/dhcp"
+	"github.com/ironcore-dev/boot-operator/internal/registry"
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// dhcpServerPort receives broadcast DHCPDISCOVERs, alongside the site's DHCP server.
+	dhcpServerPort = 67
+	dhcpClientPort = 68
+	// pxeProxyPort receives DHCPREQUESTs PXE clients send to the ProxyDHCP server.
+	pxeProxyPort = 4011
+)
+
+var errUnsupportedClient = errors.New("unsupported client architecture")
+
+// ProxyDHCPOptions configures the ProxyDHCP responder.
+type ProxyDHCPOptions struct {
+	// ServerIP is announced as server identifier and as TFTP server for PXE clients.
+	ServerIP net.IP
+	// IPXEServiceURL is the boot-server URL iPXE clients chain to.
+	IPXEServiceURL string
+
+	RegistryValidator *registry.Validator
+	CredentialStore   *registry.CredentialStore
+	DefaultOCIImage   string
+	DefaultUKIURL     string
+	ImageServerURL    string
+	Architecture      string
+}
+
+// RunProxyDHCPServer answers PXE and UEFI HTTP boot clients with the boot file to load, leaving
+// address assignment to the site's authoritative DHCP server. PXE firmware is pointed at the
+// iPXE binaries of the TFTP server, iPXE at the boot-server, and UEFI HTTP boot clients at the
+// UKI of their HTTPBootConfig.
+func RunProxyDHCPServer(k8sClient client.Client, log logr.Logger, opts ProxyDHCPOptions) error {
+	if opts.ServerIP.To4() == nil {
+		return fmt.Errorf("ProxyDHCP server IP %q is not an IPv4 address", opts.ServerIP)
+	}
+	s := &proxyDHCP{
+		ProxyDHCPOptions: opts,
+		k8sClient:        k8sClient,
+		log:              log,
+	}
+
+	conns := make(map[int]net.PacketConn)
+	for _, port := range []int{dhcpServerPort, pxeProxyPort} {
+		conn, err := listenBroadcastUDP(context.Background(), fmt.Sprintf(":%d", port))
+		if err != nil {
+			for _, conn := range conns {
+				_ = conn.Close()
+			}
+			return fmt.Errorf("failed to listen on UDP port %d: %w", port, err)
+		}
+		conns[port] = conn
+	}
+
+	group, ctx := errgroup.WithContext(context.Background())
+	for port, conn := range conns {
+		group.Go(func() error {
+			// Stop the other listener if one fails.
+			stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+			defer stop()
+			return s.serve(ctx, conn, port == pxeProxyPort)
+		})
+	}
+
+	log.Info("Starting ProxyDHCP server", "serverIP", opts.ServerIP)
+	if err := group.Wait(); err != nil {
+		log.Error(err, "ProxyDHCP server failed")
+		return err
+	}
+	return nil
+}
+
+type proxyDHCP struct {
+	ProxyDHCPOptions
+	k8sClient client.Client
+	log       logr.Logger
+}
+
+func (s *proxyDHCP) serve(ctx context.Context, conn net.PacketConn, proxyPort bool) error {
+	buf := make([]byte, 1500)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			return fmt.Errorf("failed to read DHCP packet: %w", err)
+		}
+		req, err := dhcp.Parse(buf[:n])
+		if err != nil {
+			s.log.V(1).Info("Ignoring malformed DHCP packet", "from", from, "error", err)
+			continue
+		}
+		go s.handle(ctx, conn, from, req, proxyPort)
+	}
+}
+
+func (s *proxyDHCP) handle(ctx context.Context, conn net.PacketConn, from net.Addr, req *dhcp.Packet, proxyPort bool) {
+	log := s.log.WithValues("mac", req.CHAddr.String(), "xid", req.XID)
+	reply, err := s.reply(ctx, req, proxyPort)
+	if err != nil {
+		log.Info("Not answering boot client", "reason", err)
+		return
+	}
+	if reply == nil {
+		return
+	}
+
+	dest := from
+	if !proxyPort {
+		// Clients without an address only receive broadcasts, unless a relay forwarded the request.
+		dest = &net.UDPAddr{IP: net.IPv4bcast, Port: dhcpClientPort}
+		if giaddr := req.GIAddr.To4(); giaddr != nil && !giaddr.IsUnspecified() {
+			dest = &net.UDPAddr{IP: giaddr, Port: dhcpServerPort}
+		}
+	}
+	if _, err := conn.WriteTo(reply.Marshal(), dest); err != nil {
+		log.Error(err, "Failed to send ProxyDHCP reply", "destination", dest)
+		return
+	}
+	log.Info("Sent ProxyDHCP reply", "destination", dest, "bootfile", reply.Options[dhcp.OptionBootfileName])
+}
+
+// reply returns the answer to a boot client's request, or nil if the request is not for us.
+func (s *proxyDHCP) reply(ctx context.Context, req *dhcp.Packet, proxyPort bool) (*dhcp.Packet, error) {
+	vendorClass := req.VendorClass()
+	if req.Op != dhcp.OpRequest || vendorClass == "" {
+		return nil, nil
+	}
+
+	var messageType dhcp.MessageType
+	switch {
+	case !proxyPort && req.MessageType() == dhcp.MessageTypeDiscover:
+		messageType = dhcp.MessageTypeOffer
+	case proxyPort && (req.MessageType() == dhcp.MessageTypeRequest || req.MessageType() == dhcp.MessageTypeInform):
+		messageType = dhcp.MessageTypeAck
+	default:
+		// Requests on port 67 are for the authoritative DHCP server.
+		return nil, nil
+	}
+
+	bootfile, err := s.bootfile(ctx, req, vendorClass)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := dhcp.NewReply(req, messageType)
+	reply.Options[dhcp.OptionServerIdentifier] = s.ServerIP.To4()
+	reply.Options[dhcp.OptionVendorClassIdentifier] = []byte(vendorClass)
+	reply.Options[dhcp.OptionBootfileName] = []byte(bootfile)
+	if guid, ok := req.Options[dhcp.OptionClientMachineID]; ok {
+		reply.Options[dhcp.OptionClientMachineID] = guid
+	}
+	if len(bootfile) < 128 {
+		reply.File = bootfile
+	}
+	if vendorClass == dhcp.VendorClassPXEClient {
+		reply.SIAddr = s.ServerIP
+		reply.Options[dhcp.OptionVendorSpecific] = dhcp.PXEVendorOptions()
+	}
+	return reply, nil
+}
+
+// bootfile returns the boot file for the client: an iPXE binary on the TFTP server for PXE
+// firmware, the boot-server's /ipxe/ endpoint for iPXE and the UKI URL for UEFI HTTP boot.
+func (s *proxyDHCP) bootfile(ctx context.Context, req *dhcp.Packet, vendorClass string) (string, error) {
+	// PXE clients that do not send option 93 are legacy BIOS clients.
+	arch, _ := req.ClientArchitecture()
+
+	if vendorClass == dhcp.VendorClassHTTPClient {
+		if arch != dhcp.ArchX64UEFIHTTP && arch != dhcp.ArchARM64UEFIHTTP {
+			return "", fmt.Errorf("%w %d", errUnsupportedClient, arch)
+		}
+		return s.ukiURL(ctx, req.CHAddr)
+	}
+
+	if req.IsIPXE() {
+		if s.IPXEServiceURL == "" {
+			return "", errors.New("iPXE is disabled")
+		}
+		return strings.TrimSuffix(s.IPXEServiceURL, "/") + "/ipxe/", nil
+	}
+	switch arch {
+	case dhcp.ArchX86BIOS:
+		return "amd64/undionly.kpxe", nil
+	case dhcp.ArchX64UEFI, dhcp.ArchEFIBC:
+		return "amd64/ipxe.efi", nil
+	case dhcp.ArchARM64UEFI:
+		return "arm64/ipxe.efi", nil
+	default:
+		return "", fmt.Errorf("%w %d", errUnsupportedClient, arch)
+	}
+}
+
+// ukiURL returns the UKI URL of the HTTPBootConfig matching the client's MAC address, or the
+// default UKI if there is none.
+func (s *proxyDHCP) ukiURL(ctx context.Context, mac net.HardwareAddr) (string, error) {
+	var httpBootConfigs bootv1alpha1.HTTPBootConfigList
+	if err := s.k8sClient.List(ctx, &httpBootConfigs, client.MatchingFields{bootv1alpha1.NetworkIdentifierIndexKey: mac.String()}); err != nil {
+		return "", fmt.Errorf("failed to list HTTPBootConfigs: %w", err)
+	}
+
+	if len(httpBootConfigs.Items) == 0 {
+		ukiURL, err := resolveDefaultUKIURL(ctx, s.RegistryValidator, s.CredentialStore, s.DefaultOCIImage, s.DefaultUKIURL,
+			s.ImageServerURL, s.Architecture)
+		if err != nil {
+			return "", err
+		}
+		if ukiURL == "" {
+			return "", errors.New("no HTTPBootConfig found and no default UKI configured")
+		}
+		return ukiURL, nil
+	}
+
+	httpBootConfig, err := selectBootConfig(ctx, s.k8sClient, s.log, toPointers(httpBootConfigs.Items))
+	if err != nil {
+		return "", fmt.Errorf("failed to select HTTPBootConfig: %w", err)
+	}
+	if httpBootConfig.Spec.UKIURL == "" {
+		return "", fmt.Errorf("HTTPBootConfig %s has no UKI URL yet", client.ObjectKeyFromObject(httpBootConfig))
+	}
+	return httpBootConfig.Spec.UKIURL, nil
+}
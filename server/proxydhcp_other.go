@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !unix
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+func listenBroadcastUDP(context.Context, string) (net.PacketConn, error) {
+	return nil, errors.New("ProxyDHCP is only supported on unix systems")
+}
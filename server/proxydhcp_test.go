@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/dhcp"
+	"github.com/ironcore-dev/boot-operator/internal/netid"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ProxyDHCP", func() {
+	var (
+		proxy *proxyDHCP
+		mac   = net.HardwareAddr{0x52, 0x54, 0x00, 0x12, 0x34, 0x56}
+		guid  = []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	)
+
+	newProxy := func(objs ...client.Object) *proxyDHCP {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(metalv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.NetworkIdentifierIndexKey, func(obj client.Object) []string {
+				return netid.CanonicalAll(obj.(*bootv1alpha1.HTTPBootConfig).Spec.NetworkIdentifiers)
+			}).
+			Build()
+		return &proxyDHCP{
+			ProxyDHCPOptions: ProxyDHCPOptions{
+				ServerIP:       net.IPv4(10, 0, 0, 1),
+				IPXEServiceURL: "http://boot.example.com",
+				DefaultUKIURL:  defaultUKIURL,
+			},
+			k8sClient: k8sClient,
+			log:       logr.Discard(),
+		}
+	}
+
+	request := func(messageType dhcp.MessageType, vendorClass string, arch uint16) *dhcp.Packet {
+		p := &dhcp.Packet{
+			Op:     dhcp.OpRequest,
+			HType:  1,
+			HLen:   6,
+			XID:    42,
+			CHAddr: mac,
+			Options: map[byte][]byte{
+				dhcp.OptionMessageType:           {byte(messageType)},
+				dhcp.OptionVendorClassIdentifier: []byte(vendorClass + ":Arch:00000:UNDI:002001"),
+				dhcp.OptionClientArchitecture:    binary.BigEndian.AppendUint16(nil, arch),
+				dhcp.OptionClientMachineID:       guid,
+			},
+		}
+		return p
+	}
+
+	BeforeEach(func() {
+		proxy = newProxy()
+	})
+
+	DescribeTable("offers the iPXE binary of the client architecture to PXE firmware",
+		func(arch uint16, bootfile string) {
+			reply, err := proxy.reply(context.Background(), request(dhcp.MessageTypeDiscover, dhcp.VendorClassPXEClient, arch), false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reply.MessageType()).To(Equal(dhcp.MessageTypeOffer))
+			Expect(reply.XID).To(BeEquivalentTo(42))
+			Expect(reply.YIAddr).To(BeNil(), "a ProxyDHCP offer must not assign an address")
+			Expect(reply.SIAddr.Equal(proxy.ServerIP)).To(BeTrue())
+			Expect(reply.File).To(Equal(bootfile))
+			Expect(string(reply.Options[dhcp.OptionBootfileName])).To(Equal(bootfile))
+			Expect(string(reply.Options[dhcp.OptionVendorClassIdentifier])).To(Equal(dhcp.VendorClassPXEClient))
+			Expect(reply.Options[dhcp.OptionClientMachineID]).To(Equal(guid))
+			Expect(reply.Options).To(HaveKey(dhcp.OptionVendorSpecific))
+		},
+		Entry("legacy BIOS", dhcp.ArchX86BIOS, "amd64/undionly.kpxe"),
+		Entry("x64 UEFI", dhcp.ArchX64UEFI, "amd64/ipxe.efi"),
+		Entry("x64 UEFI (EFI BC)", dhcp.ArchEFIBC, "amd64/ipxe.efi"),
+		Entry("arm64 UEFI", dhcp.ArchARM64UEFI, "arm64/ipxe.efi"),
+	)
+
+	It("acknowledges requests on the PXE proxy port", func() {
+		reply, err := proxy.reply(context.Background(), request(dhcp.MessageTypeRequest, dhcp.VendorClassPXEClient, dhcp.ArchX64UEFI), true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reply.MessageType()).To(Equal(dhcp.MessageTypeAck))
+		Expect(reply.File).To(Equal("amd64/ipxe.efi"))
+	})
+
+	It("points iPXE at the boot-server", func() {
+		req := request(dhcp.MessageTypeDiscover, dhcp.VendorClassPXEClient, dhcp.ArchX64UEFI)
+		req.Options[dhcp.OptionUserClass] = []byte("iPXE")
+		reply, err := proxy.reply(context.Background(), req, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reply.File).To(Equal("http://boot.example.com/ipxe/"))
+	})
+
+	It("points UEFI HTTP clients at the UKI of their HTTPBootConfig", func() {
+		proxy = newProxy(&bootv1alpha1.HTTPBootConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "server", Namespace: "default"},
+			Spec: bootv1alpha1.HTTPBootConfigSpec{
+				NetworkIdentifiers: []string{"10.0.0.10", mac.String()},
+				UKIURL:             "http://images.example.com/uki.efi",
+			},
+		})
+		reply, err := proxy.reply(context.Background(), request(dhcp.MessageTypeDiscover, dhcp.VendorClassHTTPClient, dhcp.ArchX64UEFIHTTP), false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reply.File).To(Equal("http://images.example.com/uki.efi"))
+		Expect(string(reply.Options[dhcp.OptionVendorClassIdentifier])).To(Equal(dhcp.VendorClassHTTPClient))
+		Expect(reply.SIAddr).To(BeNil())
+		Expect(reply.Options).NotTo(HaveKey(dhcp.OptionVendorSpecific))
+	})
+
+	It("points unknown UEFI HTTP clients at the default UKI", func() {
+		reply, err := proxy.reply(context.Background(), request(dhcp.MessageTypeDiscover, dhcp.VendorClassHTTPClient, dhcp.ArchARM64UEFIHTTP), false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reply.File).To(Equal(defaultUKIURL))
+	})
+
+	It("does not answer UEFI HTTP clients without a UKI", func() {
+		proxy.DefaultUKIURL = ""
+		_, err := proxy.reply(context.Background(), request(dhcp.MessageTypeDiscover, dhcp.VendorClassHTTPClient, dhcp.ArchX64UEFIHTTP), false)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects unsupported architectures", func() {
+		_, err := proxy.reply(context.Background(), request(dhcp.MessageTypeDiscover, dhcp.VendorClassPXEClient, 6), false)
+		Expect(err).To(MatchError(errUnsupportedClient))
+	})
+
+	DescribeTable("ignores requests that are not for the ProxyDHCP server",
+		func(req *dhcp.Packet, proxyPort bool) {
+			reply, err := proxy.reply(context.Background(), req, proxyPort)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reply).To(BeNil())
+		},
+		Entry("non-PXE client", request(dhcp.MessageTypeDiscover, "MSFT 5.0", 0), false),
+		Entry("request on the DHCP port", request(dhcp.MessageTypeRequest, dhcp.VendorClassPXEClient, 0), false),
+		Entry("discover on the proxy port", request(dhcp.MessageTypeDiscover, dhcp.VendorClassPXEClient, 0), true),
+	)
+})
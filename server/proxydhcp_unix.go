@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unix
+
+package server
+
+import (
+	"context"
+	"net"
+	"syscall"
+)
+
+// listenBroadcastUDP listens on the IPv4 UDP address addr with SO_BROADCAST set, so that
+// replies can be broadcast to clients that have no address yet.
+func listenBroadcastUDP(ctx context.Context, addr string) (net.PacketConn, error) {
+	lc := net.ListenConfig{
+		Control: func(_, _ string, c syscall.RawConn) error {
+			var sockErr error
+			if err := c.Control(func(fd uintptr) {
+				sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_BROADCAST, 1)
+			}); err != nil {
+				return err
+			}
+			return sockErr
+		},
+	}
+	return lc.ListenPacket(ctx, "udp4", addr)
+}
@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/cms"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// signatureSuffix is appended to the path of a script or image to request its detached
+// signature, the convention used for iPXE's imgverify.
+const signatureSuffix = ".sig"
+
+// SignerSource loads the iPXE code signing certificate and key from a kubernetes.io/tls
+// Secret, reloading them whenever the Secret changes.
+type SignerSource struct {
+	k8sClient client.Client
+	secretKey client.ObjectKey
+
+	mu              sync.Mutex
+	resourceVersion string
+	signer          *cms.Signer
+}
+
+// NewSignerSource returns a SignerSource reading the given Secret.
+func NewSignerSource(k8sClient client.Client, secretKey client.ObjectKey) *SignerSource {
+	return &SignerSource{
+		k8sClient: k8sClient,
+		secretKey: secretKey,
+	}
+}
+
+// Signer returns the signer of the current Secret revision.
+func (s *SignerSource) Signer(ctx context.Context) (*cms.Signer, error) {
+	secret := &corev1.Secret{}
+	if err := s.k8sClient.Get(ctx, s.secretKey, secret); err != nil {
+		return nil, fmt.Errorf("failed to get signing secret %s: %w", s.secretKey, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.signer != nil && s.resourceVersion == secret.ResourceVersion {
+		return s.signer, nil
+	}
+	signer, err := cms.NewSigner(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing secret %s: %w", s.secretKey, err)
+	}
+	s.signer, s.resourceVersion = signer, secret.ResourceVersion
+	return signer, nil
+}
+
+// signatureURL returns the URL of the detached signature of the artifact at rawURL.
+func signatureURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || rawURL == "" {
+		return ""
+	}
+	u.Path += signatureSuffix
+	return u.String()
+}
+
+// serveSignature writes the detached signature of content.
+func serveSignature(w http.ResponseWriter, r *http.Request, log logr.Logger, signerSource *SignerSource, content []byte) {
+	signer, err := signerSource.Signer(r.Context())
+	if err != nil {
+		log.Error(err, "Failed to load signing certificate")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	signature, err := signer.Sign(bytes.NewReader(content))
+	if err != nil {
+		log.Error(err, "Failed to sign content")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	writeSignature(w, log, signature)
+}
+
+// serveImageSignature writes the detached signature of an image layer. Only layers referenced
+// by a boot config are signed, so that a signature vouches for an artifact the operator chose to
+// boot rather than for anything reachable in an allowed registry.
+func serveImageSignature(w http.ResponseWriter, r *http.Request, k8sClient client.Client, log logr.Logger, signerSource *SignerSource,
+	imageDetails *ImageDetails) {
+	hexDigest, ok := strings.CutPrefix(imageDetails.LayerDigest, "sha256:")
+	digest, err := hex.DecodeString(hexDigest)
+	if !ok || err != nil {
+		http.Error(w, "Bad Request: only sha256 layers can be signed", http.StatusBadRequest)
+		return
+	}
+
+	referenced, err := isLayerReferenced(r.Context(), k8sClient, imageDetails)
+	if err != nil {
+		log.Error(err, "Failed to look up boot configs referencing layer", "digest", imageDetails.LayerDigest)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if !referenced {
+		log.Info("Refusing to sign layer not referenced by any boot config", "image", imageDetails.OCIImageName,
+			"digest", imageDetails.LayerDigest)
+		http.Error(w, "Resource Not Found", http.StatusNotFound)
+		return
+	}
+
+	signer, err := signerSource.Signer(r.Context())
+	if err != nil {
+		log.Error(err, "Failed to load signing certificate")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	// The layer digest is the SHA-256 of the blob served by /image, so the blob need not be fetched.
+	signature, err := signer.SignDigest(digest)
+	if err != nil {
+		log.Error(err, "Failed to sign layer", "digest", imageDetails.LayerDigest)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	writeSignature(w, log, signature)
+}
+
+func writeSignature(w http.ResponseWriter, log logr.Logger, signature []byte) {
+	w.Header().Set("Content-Type", "application/pkcs7-signature")
+	if _, err := w.Write(signature); err != nil {
+		log.Info("Failed to write signature", "error", err)
+	}
+}
+
+// isLayerReferenced reports whether the kernel, initrd or squashfs of an IPXEBootConfig points
+// at the layer of the image.
+func isLayerReferenced(ctx context.Context, k8sClient client.Client, imageDetails *ImageDetails) (bool, error) {
+	ipxeBootConfigs := &bootv1alpha1.IPXEBootConfigList{}
+	if err := k8sClient.List(ctx, ipxeBootConfigs, client.MatchingFields{bootv1alpha1.LayerDigestIndexKey: imageDetails.LayerDigest}); err != nil {
+		return false, fmt.Errorf("failed to list IPXEBootConfigs: %w", err)
+	}
+	for _, config := range ipxeBootConfigs.Items {
+		for _, query := range artifactQueries(&config) {
+			if query.Get(layerDigestKey) == imageDetails.LayerDigest &&
+				strings.TrimSuffix(query.Get(imageKey), ".efi") == imageDetails.OCIImageName {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// ArtifactLayerDigests returns the digests of the image layers that the kernel, initrd and squashfs
+// URLs of an IPXEBootConfig point at through the image proxy server.
+func ArtifactLayerDigests(config *bootv1alpha1.IPXEBootConfig) []string {
+	var digests []string
+	for _, query := range artifactQueries(config) {
+		if digest := query.Get(layerDigestKey); digest != "" {
+			digests = append(digests, digest)
+		}
+	}
+	return digests
+}
+
+// artifactQueries returns the query parameters of the kernel, initrd and squashfs URLs of an
+// IPXEBootConfig.
+func artifactQueries(config *bootv1alpha1.IPXEBootConfig) []url.Values {
+	var queries []url.Values
+	for _, artifactURL := range []string{config.Spec.KernelURL, config.Spec.InitrdURL, config.Spec.SquashfsURL} {
+		u, err := url.Parse(artifactURL)
+		if err != nil {
+			continue
+		}
+		queries = append(queries, u.Query())
+	}
+	return queries
+}
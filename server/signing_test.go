@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"text/template"
+	"time"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	metalv1alpha1 "github.com/ironcore-dev/metal-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Signing", func() {
+	const (
+		systemUUID = "e7ee6d8a-5b42-4b3c-9c1f-1b1f3f1e2a10"
+		kernelURL  = "http://images.example.com/image?imageName=ghcr.io/ironcore-dev/os&version=1.0&layerDigest=sha256:" +
+			"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	)
+
+	var (
+		signingSecret *corev1.Secret
+		signerSource  *SignerSource
+		k8s           client.Client
+	)
+
+	newSigningSecret := func() *corev1.Secret {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "boot-operator"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).NotTo(HaveOccurred())
+		return &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "ipxe-signing", Namespace: "default"},
+			Type:       corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+				corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+			},
+		}
+	}
+
+	newClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(metalv1alpha1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objs...).
+			WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+			}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.LayerDigestIndexKey, func(obj client.Object) []string {
+				return ArtifactLayerDigests(obj.(*bootv1alpha1.IPXEBootConfig))
+			}).
+			Build()
+	}
+
+	// expectSignatureOf checks that a response carries the signature of content. RSA PKCS #1 v1.5
+	// signatures are deterministic, so it can be compared to a freshly computed one.
+	expectSignatureOf := func(rec *httptest.ResponseRecorder, content []byte) {
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/pkcs7-signature"))
+		signer, err := signerSource.Signer(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		expected, err := signer.Sign(bytes.NewReader(content))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rec.Body.Bytes()).To(Equal(expected))
+	}
+
+	BeforeEach(func() {
+		signingSecret = newSigningSecret()
+		scriptSecret := &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{Name: "ipxe-script", Namespace: "default"},
+			Data:       map[string][]byte{bootv1alpha1.DefaultIPXEScriptKey: []byte("#!ipxe\nshell\n")},
+		}
+		config := &bootv1alpha1.IPXEBootConfig{
+			ObjectMeta: v1.ObjectMeta{Name: "config", Namespace: "default"},
+			Spec: bootv1alpha1.IPXEBootConfigSpec{
+				SystemUUID:          systemUUID,
+				KernelURL:           kernelURL,
+				IPXEScriptSecretRef: &corev1.LocalObjectReference{Name: "ipxe-script"},
+			},
+		}
+		k8s = newClient(signingSecret, scriptSecret, config)
+		signerSource = NewSignerSource(k8s, client.ObjectKeyFromObject(signingSecret))
+	})
+
+	It("signs the iPXE script served for a system", func() {
+		rec := httptest.NewRecorder()
+		handleIPXE(rec, httptest.NewRequest(http.MethodGet, "/ipxe/"+systemUUID+".sig", nil), k8s, logr.Discard(),
+			ipxeServiceURL, newTestIPXETemplates(), signerSource, IgnitionAuthOptions{}, SourceIPOptions{})
+		expectSignatureOf(rec, []byte("#!ipxe\nshell\n"))
+
+		config := &bootv1alpha1.IPXEBootConfig{}
+		Expect(k8s.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "config"}, config)).To(Succeed())
+		Expect(config.Status.Conditions).To(BeEmpty(), "fetching the signature does not deliver the script")
+	})
+
+	It("does not serve signatures if signing is disabled", func() {
+		rec := httptest.NewRecorder()
+		handleIPXE(rec, httptest.NewRequest(http.MethodGet, "/ipxe/"+systemUUID+".sig", nil), k8s, logr.Discard(),
+			ipxeServiceURL, newTestIPXETemplates(), nil, IgnitionAuthOptions{}, SourceIPOptions{})
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("picks up a rotated signing certificate", func() {
+		first, err := signerSource.Signer(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		rotated := newSigningSecret()
+		signingSecret.Data = rotated.Data
+		Expect(k8s.Update(context.Background(), signingSecret)).To(Succeed())
+
+		second, err := signerSource.Signer(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.Certificate().Equal(first.Certificate())).To(BeFalse())
+	})
+
+	Context("image signatures", func() {
+		serveImageSig := func(rawURL string) *httptest.ResponseRecorder {
+			u, err := url.Parse(rawURL)
+			Expect(err).NotTo(HaveOccurred())
+			imageDetails, err := parseImageURL(u.Query())
+			Expect(err).NotTo(HaveOccurred())
+			rec := httptest.NewRecorder()
+			serveImageSignature(rec, httptest.NewRequest(http.MethodGet, signatureURL(rawURL), nil), k8s, logr.Discard(),
+				signerSource, &imageDetails)
+			return rec
+		}
+
+		It("signs the digest of layers referenced by an IPXEBootConfig", func() {
+			rec := serveImageSig(kernelURL)
+			Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+
+			digest, err := hex.DecodeString("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+			Expect(err).NotTo(HaveOccurred())
+			signer, err := signerSource.Signer(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			expected, err := signer.SignDigest(digest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rec.Body.Bytes()).To(Equal(expected))
+		})
+
+		It("refuses to sign layers no IPXEBootConfig references", func() {
+			other := sha256.Sum256([]byte("not a kernel"))
+			rec := serveImageSig("http://images.example.com/image?imageName=ghcr.io/ironcore-dev/os&version=1.0&layerDigest=sha256:" +
+				hex.EncodeToString(other[:]))
+			Expect(rec.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("refuses to sign non-sha256 layers", func() {
+			rec := serveImageSig("http://images.example.com/image?imageName=ghcr.io/ironcore-dev/os&version=1.0&layerDigest=sha512:abc")
+			Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	It("derives signature URLs by suffixing the path", func() {
+		Expect(signatureURL("http://example.com/image?layerDigest=sha256:abc")).To(Equal("http://example.com/image.sig?layerDigest=sha256:abc"))
+		Expect(signatureURL("http://example.com/vmlinuz")).To(Equal("http://example.com/vmlinuz.sig"))
+	})
+
+	It("verifies the kernel and initrd in the default iPXE script", func() {
+		tmpl, err := template.ParseFiles("../templates/ipxe-script.tpl")
+		Expect(err).NotTo(HaveOccurred())
+		var buf bytes.Buffer
+		Expect(tmpl.Execute(&buf, IPXETemplateData{
+			KernelURL:          "http://example.com/kernel",
+			InitrdURL:          "http://example.com/initrd",
+			IPXEServerURL:      "http://example.com",
+			Signed:             true,
+			KernelSignatureURL: "http://example.com/kernel.sig",
+			InitrdSignatureURL: "http://example.com/initrd.sig",
+		})).To(Succeed())
+		script := buf.String()
+		Expect(script).To(ContainSubstring("imgtrust\n"))
+		Expect(script).To(ContainSubstring("kernel --name kernel ${kernel-url} initrd=initrd"))
+		Expect(script).To(ContainSubstring("imgverify kernel http://example.com/kernel.sig\n"))
+		Expect(script).To(ContainSubstring("initrd --name initrd ${initrd-url}\nimgverify initrd http://example.com/initrd.sig\n"))
+	})
+})
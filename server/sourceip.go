@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
+// SourceIPOptions configures how the boot-server determines and verifies the addresses of its
+// clients.
+type SourceIPOptions struct {
+	// TrustedProxies are the networks of reverse proxies whose Forwarded and X-Forwarded-For
+	// headers are trusted to carry the address of the client.
+	TrustedProxies []netip.Prefix
+	// Verify rejects iPXE script and ignition requests whose source address is not one of the
+	// SystemIPs of an IPXEBootConfig or the NetworkIdentifiers of an HTTPBootConfig.
+	Verify bool
+	// Recorder reports rejected requests as events on the boot config.
+	Recorder events.EventRecorder
+}
+
+// ParseTrustedProxies parses a comma-separated list of CIDRs and IP addresses.
+func ParseTrustedProxies(s string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// clientAddress returns the address of the client that sent r. If the request came from a trusted
+// proxy, the hops recorded in the Forwarded header (RFC 7239), or in its absence the
+// X-Forwarded-For header, are walked from the right and the first address that is not a trusted
+// proxy is returned.
+func clientAddress(r *http.Request, trustedProxies []netip.Prefix) (netip.Addr, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid remote address %q: %w", r.RemoteAddr, err)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid remote address %q: %w", r.RemoteAddr, err)
+	}
+	addr = addr.Unmap()
+
+	hops := forwardedHops(r.Header)
+	for i := len(hops) - 1; i >= 0 && isTrustedProxy(addr, trustedProxies); i-- {
+		if addr, err = parseForwardedNode(hops[i]); err != nil {
+			return netip.Addr{}, err
+		}
+	}
+	return addr, nil
+}
+
+// forwardedHops returns the client addresses recorded by proxies in the order of the request path:
+// the for parameters of the Forwarded header, or the entries of the X-Forwarded-For header if
+// there is no Forwarded header.
+func forwardedHops(header http.Header) []string {
+	var hops []string
+	if forwarded := header.Values("Forwarded"); len(forwarded) > 0 {
+		for _, value := range forwarded {
+			for _, element := range strings.Split(value, ",") {
+				node := ""
+				for _, pair := range strings.Split(element, ";") {
+					if key, value, ok := strings.Cut(strings.TrimSpace(pair), "="); ok && strings.EqualFold(key, "for") {
+						node = value
+					}
+				}
+				hops = append(hops, node)
+			}
+		}
+		return hops
+	}
+
+	for _, value := range header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+	return hops
+}
+
+// parseForwardedNode parses the address of a hop recorded by a proxy, which may be quoted and carry
+// a port. Obfuscated and unknown nodes are rejected.
+func parseForwardedNode(node string) (netip.Addr, error) {
+	host := strings.Trim(strings.TrimSpace(node), `"`)
+	if rest, ok := strings.CutPrefix(host, "["); ok {
+		host, _, _ = strings.Cut(rest, "]")
+	} else if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid forwarded client address %q", strings.TrimSpace(node))
+	}
+	return addr.Unmap(), nil
+}
+
+func isTrustedProxy(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifySourceAddress checks that a request for a boot config comes from one of its known
+// addresses if source address verification is enabled. Mismatches are logged and reported as an
+// event on the boot config.
+func verifySourceAddress(
+	r *http.Request,
+	opts SourceIPOptions,
+	log logr.Logger,
+	config client.Object,
+	networkIdentifiers []string,
+) error {
+	if !opts.Verify {
+		return nil
+	}
+
+	addr, err := clientAddress(r, opts.TrustedProxies)
+	if err == nil {
+		if isKnownAddress(addr.String(), networkIdentifiers) {
+			return nil
+		}
+		err = fmt.Errorf("source address %s is not an address of the server", addr)
+	}
+
+	log.Info("Rejected request from unknown source address", "config", client.ObjectKeyFromObject(config),
+		"path", r.URL.Path, "remoteAddr", r.RemoteAddr, "reason", err.Error())
+	if opts.Recorder != nil {
+		opts.Recorder.Eventf(config, nil, corev1.EventTypeWarning, "SourceAddressMismatch", "VerifySourceAddress",
+			"Rejected request for %s: %v", r.URL.Path, err)
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/netid"
+	"github.com/ironcore-dev/boot-operator/internal/registry"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Source IP verification", func() {
+	const systemUUID = "9e4b2c1a-3d5f-4e7a-8b6c-1f2a3b4c5d6e"
+
+	trustedProxies := []netip.Prefix{netip.MustParsePrefix("10.1.0.0/16"), netip.MustParsePrefix("fd00::/64")}
+
+	It("parses trusted proxies", func() {
+		prefixes, err := ParseTrustedProxies(" 10.1.2.3/16, 192.0.2.1,fd00::/64,")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(prefixes).To(Equal([]netip.Prefix{
+			netip.MustParsePrefix("10.1.0.0/16"),
+			netip.MustParsePrefix("192.0.2.1/32"),
+			netip.MustParsePrefix("fd00::/64"),
+		}))
+
+		_, err = ParseTrustedProxies("10.1.0.0/33")
+		Expect(err).To(HaveOccurred())
+	})
+
+	xff := func(values ...string) http.Header { return http.Header{"X-Forwarded-For": values} }
+	forwarded := func(values ...string) http.Header { return http.Header{"Forwarded": values} }
+
+	DescribeTable("determines the client address",
+		func(remoteAddr string, header http.Header, expected string) {
+			req := httptest.NewRequest(http.MethodGet, "/ipxe/"+systemUUID, nil)
+			req.RemoteAddr = remoteAddr
+			req.Header = header
+			addr, err := clientAddress(req, trustedProxies)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(addr).To(Equal(netip.MustParseAddr(expected)))
+		},
+		Entry("without proxy", "192.0.2.1:1234", nil, "192.0.2.1"),
+		Entry("ignoring X-Forwarded-For from untrusted clients", "192.0.2.1:1234", xff("10.0.0.10"), "192.0.2.1"),
+		Entry("ignoring Forwarded from untrusted clients", "192.0.2.1:1234", forwarded("for=10.0.0.10"), "192.0.2.1"),
+		Entry("from a trusted proxy", "10.1.0.1:1234", xff("10.0.0.10"), "10.0.0.10"),
+		Entry("skipping trusted proxies", "10.1.0.1:1234", xff("10.0.0.10, 10.1.0.2"), "10.0.0.10"),
+		Entry("across multiple headers", "10.1.0.1:1234", xff("10.0.0.10", "10.1.0.2"), "10.0.0.10"),
+		Entry("stopping at the first untrusted hop", "10.1.0.1:1234", xff("unknown, 10.0.0.10, 192.0.2.1"), "192.0.2.1"),
+		Entry("over IPv6", "[fd00::1]:1234", xff("2001:db8::10"), "2001:db8::10"),
+		Entry("unmapping IPv4-mapped addresses", "[::ffff:192.0.2.1]:1234", nil, "192.0.2.1"),
+		Entry("from the Forwarded header", "10.1.0.1:1234", forwarded("for=10.0.0.10;proto=http;by=10.1.0.1"), "10.0.0.10"),
+		Entry("from quoted Forwarded nodes with ports", "10.1.0.1:1234",
+			forwarded(`for="[2001:db8::10]:4711", For="10.1.0.2:80"`), "2001:db8::10"),
+		Entry("preferring Forwarded over X-Forwarded-For", "10.1.0.1:1234",
+			http.Header{"Forwarded": {"for=10.0.0.10"}, "X-Forwarded-For": {"10.0.0.20"}}, "10.0.0.10"),
+	)
+
+	DescribeTable("rejects invalid hops recorded by trusted proxies",
+		func(header http.Header) {
+			req := httptest.NewRequest(http.MethodGet, "/ipxe/"+systemUUID, nil)
+			req.RemoteAddr = "10.1.0.1:1234"
+			req.Header = header
+			_, err := clientAddress(req, trustedProxies)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unknown X-Forwarded-For entry", xff("unknown")),
+		Entry("obfuscated Forwarded node", forwarded("for=_hidden")),
+		Entry("Forwarded element without node", forwarded("proto=https")),
+	)
+
+	It("looks up HTTP boot configs by the client address", func() {
+		scheme := runtime.NewScheme()
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8s := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(&bootv1alpha1.HTTPBootConfig{
+				ObjectMeta: v1.ObjectMeta{Name: "http", Namespace: "default"},
+				Spec: bootv1alpha1.HTTPBootConfigSpec{
+					SystemUUID:         systemUUID,
+					NetworkIdentifiers: []string{"10.0.0.20"},
+					UKIURL:             "http://images.example.com/uki.efi",
+				},
+			}).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.NetworkIdentifierIndexKey, func(obj client.Object) []string {
+				return netid.CanonicalAll(obj.(*bootv1alpha1.HTTPBootConfig).Spec.NetworkIdentifiers)
+			}).
+			Build()
+		httpBoot := func(remoteAddr string, header http.Header) map[string]string {
+			req := httptest.NewRequest(http.MethodGet, "/httpboot", nil)
+			req.RemoteAddr = remoteAddr
+			req.Header = header
+			rec := httptest.NewRecorder()
+			handleHTTPBoot(rec, req, k8s, logr.Discard(), newTestIPXETemplates(), registry.NewValidator(""), nil, "", defaultUKIURL, "", "amd64", trustedProxies)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			var response map[string]string
+			Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+			return response
+		}
+
+		By("ignoring headers of untrusted clients")
+		Expect(httpBoot("192.0.2.1:1234", xff("10.0.0.20"))).To(HaveKeyWithValue("UKIURL", defaultUKIURL))
+		Expect(httpBoot("192.0.2.1:1234", forwarded("for=10.0.0.20"))).To(HaveKeyWithValue("UKIURL", defaultUKIURL))
+
+		By("using the socket address of direct clients")
+		Expect(httpBoot("10.0.0.20:1234", nil)).To(HaveKeyWithValue("SystemUUID", systemUUID))
+
+		By("using the hops recorded by trusted proxies")
+		Expect(httpBoot("10.1.0.1:1234", forwarded("for=10.0.0.20"))).To(HaveKeyWithValue("SystemUUID", systemUUID))
+		Expect(httpBoot("10.1.0.1:1234", xff("10.0.0.20"))).To(HaveKeyWithValue("SystemUUID", systemUUID))
+	})
+
+	Context("with verification enabled", func() {
+		var (
+			k8s      client.Client
+			recorder *events.FakeRecorder
+			sourceIP SourceIPOptions
+		)
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+			k8s = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(
+					&bootv1alpha1.IPXEBootConfig{
+						ObjectMeta: v1.ObjectMeta{Name: "ipxe", Namespace: "default"},
+						Spec: bootv1alpha1.IPXEBootConfigSpec{
+							SystemUUID:        systemUUID,
+							SystemIPs:         []string{"10.0.0.10"},
+							KernelURL:         "http://example.com/kernel",
+							InitrdURL:         "http://example.com/initrd",
+							IgnitionSecretRef: &corev1.LocalObjectReference{Name: "ignition"},
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: v1.ObjectMeta{Name: "ignition", Namespace: "default"},
+						Data:       map[string][]byte{bootv1alpha1.DefaultIgnitionKey: []byte(`{"ignition":{"version":"3.4.0"}}`)},
+					},
+				).
+				WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}).
+				WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+					return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+				}).
+				Build()
+			recorder = events.NewFakeRecorder(10)
+			sourceIP = SourceIPOptions{TrustedProxies: trustedProxies, Verify: true, Recorder: recorder}
+		})
+
+		fetchIgnition := func(remoteAddr, xff string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/ignition/"+systemUUID, nil)
+			req.RemoteAddr = remoteAddr
+			if xff != "" {
+				req.Header.Set("X-Forwarded-For", xff)
+			}
+			rec := httptest.NewRecorder()
+			handleIgnitionIPXEBoot(rec, req, k8s, logr.Discard(), systemUUID, IgnitionAuthOptions{}, sourceIP)
+			return rec
+		}
+
+		It("serves ignition data to known addresses", func() {
+			Expect(fetchIgnition("10.0.0.10:1234", "").Code).To(Equal(http.StatusOK))
+			Expect(fetchIgnition("10.1.0.1:1234", "10.0.0.10").Code).To(Equal(http.StatusOK))
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("rejects and reports requests from unknown addresses", func() {
+			rec := fetchIgnition("192.0.2.1:1234", "10.0.0.10")
+			Expect(rec.Code).To(Equal(http.StatusForbidden))
+			Expect(rec.Body.String()).NotTo(ContainSubstring("ignition"))
+			Expect(recorder.Events).To(Receive(And(ContainSubstring("SourceAddressMismatch"), ContainSubstring("192.0.2.1"))))
+		})
+
+		It("rejects iPXE script requests from unknown addresses", func() {
+			req := httptest.NewRequest(http.MethodGet, "/ipxe/"+systemUUID, nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			rec := httptest.NewRecorder()
+			handleIPXE(rec, req, k8s, logr.Discard(), "http://boot.example.com", newTestIPXETemplates(), nil, IgnitionAuthOptions{}, sourceIP)
+			Expect(rec.Code).To(Equal(http.StatusForbidden))
+			Expect(recorder.Events).To(Receive(ContainSubstring("SourceAddressMismatch")))
+		})
+	})
+})
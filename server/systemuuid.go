@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// listBySystemUUID lists the boot configs with the given SystemUUID. If there are none, it lists the
+// boot configs with the mixed-endian variant of the SystemUUID instead, since the early-boot
+// environment of some servers reports the SMBIOS UUID in a different byte order than Redfish.
+func listBySystemUUID(ctx context.Context, k8sClient client.Client, list client.ObjectList, uuid string) error {
+	if err := k8sClient.List(ctx, list, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: systemuuid.Canonical(uuid)}); err != nil {
+		return err
+	}
+	swapped := systemuuid.Swapped(uuid)
+	if apimeta.LenList(list) > 0 || swapped == "" || swapped == systemuuid.Canonical(uuid) {
+		return nil
+	}
+	return k8sClient.List(ctx, list, client.MatchingFields{bootv1alpha1.SystemUUIDIndexKey: swapped})
+}
+
+// matchAmbiguousSystemUUID narrows down boot configs that have been looked up by SystemUUID. If
+// any of them is flagged with the SystemUUIDAmbiguous condition, the SystemUUID does not identify
+// the client, and only the boot configs matching the address of the client are returned.
+func matchAmbiguousSystemUUID[T client.Object](r *http.Request, log logr.Logger, items []T, trustedProxies []netip.Prefix) ([]T, error) {
+	ambiguous := false
+	for _, item := range items {
+		conditions, err := bootConfigConditions(item)
+		if err != nil {
+			return nil, err
+		}
+		if apimeta.IsStatusConditionTrue(conditions, bootv1alpha1.SystemUUIDAmbiguousCondition) {
+			ambiguous = true
+			break
+		}
+	}
+	if !ambiguous {
+		return items, nil
+	}
+
+	addr, err := clientAddress(r, trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	var matching []T
+	for _, item := range items {
+		if isKnownAddress(addr.String(), bootConfigNetworkIdentifiers(item)) {
+			matching = append(matching, item)
+		}
+	}
+	log.Info("SystemUUID is ambiguous, matched boot configs by client address", "clientIP", addr, "count", len(items), "matching", len(matching))
+	return matching, nil
+}
+
+// bootConfigNetworkIdentifiers returns the addresses of the server of a boot config.
+func bootConfigNetworkIdentifiers(config client.Object) []string {
+	switch resource := config.(type) {
+	case *bootv1alpha1.IPXEBootConfig:
+		return resource.Spec.SystemIPs
+	case *bootv1alpha1.HTTPBootConfig:
+		return resource.Spec.NetworkIdentifiers
+	default:
+		return nil
+	}
+}
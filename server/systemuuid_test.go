@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	bootv1alpha1 "github.com/ironcore-dev/boot-operator/api/v1alpha1"
+	"github.com/ironcore-dev/boot-operator/internal/systemuuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Ambiguous SystemUUID", func() {
+	const placeholderUUID = "03000200-0400-0500-0006-000700080009"
+
+	var k8s client.Client
+
+	BeforeEach(func() {
+		ambiguous := []v1.Condition{{
+			Type:   bootv1alpha1.SystemUUIDAmbiguousCondition,
+			Status: v1.ConditionTrue,
+			Reason: bootv1alpha1.PlaceholderSystemUUIDReason,
+		}}
+		objects := []client.Object{}
+		for name, systemIP := range map[string]string{"server-a": "10.0.0.10", "server-b": "10.0.0.11"} {
+			objects = append(objects,
+				&bootv1alpha1.IPXEBootConfig{
+					ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec: bootv1alpha1.IPXEBootConfigSpec{
+						SystemUUID:          placeholderUUID,
+						SystemIPs:           []string{systemIP},
+						IPXEScriptSecretRef: &corev1.LocalObjectReference{Name: name},
+						IgnitionSecretRef:   &corev1.LocalObjectReference{Name: name},
+					},
+					Status: bootv1alpha1.IPXEBootConfigStatus{Conditions: ambiguous},
+				},
+				&corev1.Secret{
+					ObjectMeta: v1.ObjectMeta{Name: name, Namespace: "default"},
+					Data: map[string][]byte{
+						bootv1alpha1.DefaultIPXEScriptKey: []byte("#!ipxe\necho " + name),
+						bootv1alpha1.DefaultIgnitionKey:   []byte(`{"ignition":{"version":"3.4.0"},"name":"` + name + `"}`),
+					},
+				},
+			)
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(bootv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8s = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(objects...).
+			WithStatusSubresource(&bootv1alpha1.IPXEBootConfig{}).
+			WithIndex(&bootv1alpha1.IPXEBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.IPXEBootConfig).Spec.SystemUUID)}
+			}).
+			WithIndex(&bootv1alpha1.HTTPBootConfig{}, bootv1alpha1.SystemUUIDIndexKey, func(obj client.Object) []string {
+				return []string{systemuuid.Canonical(obj.(*bootv1alpha1.HTTPBootConfig).Spec.SystemUUID)}
+			}).
+			Build()
+	})
+
+	fetchScript := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ipxe/"+placeholderUUID, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handleIPXE(rec, req, k8s, logr.Discard(), "http://boot.example.com", newTestIPXETemplates(), nil, IgnitionAuthOptions{}, SourceIPOptions{})
+		return rec
+	}
+
+	fetchIgnition := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ignition/"+placeholderUUID, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handleIgnitionIPXEBoot(rec, req, k8s, logr.Discard(), placeholderUUID, IgnitionAuthOptions{}, SourceIPOptions{})
+		return rec
+	}
+
+	It("serves the iPXE script of the config matching the client address", func() {
+		rec := fetchScript("10.0.0.11:1234")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("echo server-b"))
+
+		rec = fetchScript("10.0.0.10:1234")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("echo server-a"))
+	})
+
+	It("serves the ignition data of the config matching the client address", func() {
+		rec := fetchIgnition("10.0.0.11:1234")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("server-b"))
+	})
+
+	It("does not serve clients with unknown addresses", func() {
+		Expect(fetchScript("192.0.2.1:1234").Code).To(Equal(http.StatusNotFound))
+		Expect(fetchIgnition("192.0.2.1:1234").Code).To(Equal(http.StatusNotFound))
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/ready/"+placeholderUUID, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		handleReady(rec, req, k8s, logr.Discard(), IgnitionAuthOptions{}, SourceIPOptions{})
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+})
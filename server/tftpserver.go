@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"github.com/ironcore-dev/boot-operator/internal/ipxe"
+	"github.com/ironcore-dev/boot-operator/internal/tftp"
+)
+
+// IPXEChainScriptName is the script the bundled iPXE binaries fetch from the TFTP server
+// after acquiring an address. It chains to the boot server's /ipxe/ endpoint.
+const IPXEChainScriptName = "boot.ipxe"
+
+// ipxeBinaries are the iPXE binaries served over TFTP: undionly.kpxe for legacy BIOS,
+// ipxe.efi and snp.efi for UEFI firmware.
+var ipxeBinaries = map[string]bool{
+	"undionly.kpxe": true,
+	"ipxe.efi":      true,
+	"snp.efi":       true,
+}
+
+// ipxeArchitectures are the architectures iPXE binaries are bundled for.
+var ipxeArchitectures = map[string]bool{
+	"amd64": true,
+	"arm64": true,
+}
+
+// RunTFTPServer serves the bundled iPXE binaries from ipxeDir over TFTP, so that PXE firmware
+// can be chainloaded into iPXE without an external TFTP service. If signed is set, the chain
+// script only boots boot-server scripts that pass imgverify.
+func RunTFTPServer(tftpServerAddr, ipxeDir, ipxeServiceURL string, ipxeTemplates *ipxe.Templates, architecture string, signed bool,
+	log logr.Logger) error {
+	files := &tftpFiles{
+		ipxeDir:        ipxeDir,
+		ipxeTemplates:  ipxeTemplates,
+		ipxeServiceURL: ipxeServiceURL,
+		architecture:   architecture,
+		signed:         signed,
+	}
+	server := &tftp.Server{
+		ReadFunc: files.open,
+		Log:      log,
+	}
+
+	log.Info("Starting TFTP server", "address", tftpServerAddr, "ipxeDir", ipxeDir)
+	if err := server.ListenAndServe(context.Background(), tftpServerAddr); err != nil {
+		log.Error(err, "failed to start TFTP server")
+		return err
+	}
+	return nil
+}
+
+// tftpFiles resolves the files requested over TFTP.
+//
+// Binaries are looked up as <ipxeDir>/<arch>/<name>. Clients request them either by name
+// alone, which selects the manager's architecture, or prefixed with the architecture,
+// e.g. "arm64/snp.efi".
+type tftpFiles struct {
+	ipxeDir        string
+	ipxeTemplates  *ipxe.Templates
+	ipxeServiceURL string
+	architecture   string
+	signed         bool
+}
+
+func (f *tftpFiles) open(filename string, _ net.Addr) (io.ReadCloser, int64, error) {
+	name := path.Clean("/" + filename)[1:]
+	if name == IPXEChainScriptName {
+		return f.chainScript()
+	}
+
+	arch, binary := path.Split(name)
+	arch = path.Clean(arch)
+	if arch == "." {
+		arch = f.architecture
+	}
+	if !ipxeArchitectures[arch] || !ipxeBinaries[binary] {
+		return nil, 0, tftp.ErrNotFound
+	}
+
+	file, err := os.Open(filepath.Join(f.ipxeDir, arch, binary))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, tftp.ErrNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, 0, err
+	}
+	return file, info.Size(), nil
+}
+
+// chainScript renders the iPXE chainload template pointing at the boot server.
+func (f *tftpFiles) chainScript() (io.ReadCloser, int64, error) {
+	if f.ipxeServiceURL == "" {
+		return nil, 0, tftp.ErrNotFound
+	}
+	script, err := f.ipxeTemplates.Render(ipxe.ChainloadTemplate, IPXETemplateData{IPXEServerURL: f.ipxeServiceURL, Signed: f.signed})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to render iPXE chainload template: %w", err)
+	}
+	return io.NopCloser(bytes.NewReader(script)), int64(len(script)), nil
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ironcore-dev/boot-operator/internal/tftp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TFTP Server", func() {
+	var files *tftpFiles
+
+	BeforeEach(func() {
+		ipxeDir := GinkgoT().TempDir()
+		for _, binary := range []string{"amd64/undionly.kpxe", "amd64/snp.efi", "arm64/snp.efi"} {
+			Expect(os.MkdirAll(filepath.Join(ipxeDir, filepath.Dir(binary)), 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(ipxeDir, binary), []byte(binary), 0o644)).To(Succeed())
+		}
+		// Files next to the binaries must not be reachable.
+		Expect(os.WriteFile(filepath.Join(ipxeDir, "amd64", "secret"), []byte("secret"), 0o644)).To(Succeed())
+
+		files = &tftpFiles{
+			ipxeDir:        ipxeDir,
+			ipxeTemplates:  newTestIPXETemplates(),
+			ipxeServiceURL: "http://boot.example.com",
+			architecture:   "amd64",
+		}
+	})
+
+	read := func(filename string) (string, error) {
+		r, size, err := files.open(filename, nil)
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = r.Close() }()
+		data, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(size).To(BeEquivalentTo(len(data)))
+		return string(data), nil
+	}
+
+	It("serves binaries of the manager's architecture by name", func() {
+		Expect(read("undionly.kpxe")).To(Equal("amd64/undionly.kpxe"))
+		Expect(read("/snp.efi")).To(Equal("amd64/snp.efi"))
+	})
+
+	It("serves binaries of other architectures by path", func() {
+		Expect(read("arm64/snp.efi")).To(Equal("arm64/snp.efi"))
+	})
+
+	It("renders the chainload script pointing at the boot server", func() {
+		script, err := read(IPXEChainScriptName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(script).To(HavePrefix("#!ipxe"))
+		Expect(script).To(ContainSubstring("set ipxe-svc http://boot.example.com"))
+	})
+
+	It("verifies the boot server script before chaining to it if signing is enabled", func() {
+		files.signed = true
+		script, err := read(IPXEChainScriptName)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(script).To(ContainSubstring("imgtrust\n"))
+		Expect(script).To(ContainSubstring("imgverify script ${base-url}/${system-id}.sig\n"))
+		Expect(script).To(ContainSubstring("chain --replace --autofree script"))
+	})
+
+	It("does not serve the chainload script if iPXE is disabled", func() {
+		files.ipxeServiceURL = ""
+		_, err := read(IPXEChainScriptName)
+		Expect(err).To(MatchError(tftp.ErrNotFound))
+	})
+
+	DescribeTable("rejects files that are not bundled iPXE binaries",
+		func(filename string) {
+			_, err := read(filename)
+			Expect(err).To(MatchError(tftp.ErrNotFound))
+		},
+		Entry("unknown file", "amd64/secret"),
+		Entry("path traversal", "../amd64/secret"),
+		Entry("unknown architecture", "riscv64/snp.efi"),
+		Entry("missing binary", "arm64/ipxe.efi"),
+	)
+})
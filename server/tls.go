@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// CABundlePEMPath serves the CA bundle of the HTTPS listeners in PEM format.
+	CABundlePEMPath = "/.well-known/ca.pem"
+	// CABundleDERPath serves the first certificate of the CA bundle in DER format, the form
+	// UEFI firmware enrolls TLS CA certificates in.
+	CABundleDERPath = "/.well-known/ca.der"
+)
+
+// TLSOptions configures an HTTPS listener served in addition to the plain HTTP listener of a
+// server. Clients that cannot do TLS, like PXE firmware, keep using the plain HTTP listener.
+type TLSOptions struct {
+	// Address is the address the HTTPS listener binds to.
+	Address string
+	// TLSOpts customize the TLS configuration. They must provide a certificate, e.g. by setting
+	// GetCertificate to that of a certwatcher.
+	TLSOpts []func(*tls.Config)
+}
+
+// listenAndServe serves handler over HTTP on addr and, if tlsOptions are given, over HTTPS on
+// their address. If one listener fails, the other one is closed and the error is returned.
+func listenAndServe(addr string, handler http.Handler, tlsOptions *TLSOptions, log logr.Logger) error {
+	servers := []*http.Server{{Addr: addr, Handler: handler}}
+	if tlsOptions != nil {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		for _, opt := range tlsOptions.TLSOpts {
+			opt(tlsConfig)
+		}
+		servers = append(servers, &http.Server{Addr: tlsOptions.Address, Handler: handler, TLSConfig: tlsConfig})
+	}
+
+	group, ctx := errgroup.WithContext(context.Background())
+	for _, server := range servers {
+		group.Go(func() error {
+			stop := context.AfterFunc(ctx, func() { _ = server.Close() })
+			defer stop()
+
+			var err error
+			if server.TLSConfig != nil {
+				log.Info("Serving HTTPS", "address", server.Addr)
+				err = server.ListenAndServeTLS("", "")
+			} else {
+				err = server.ListenAndServe()
+			}
+			if errors.Is(err, http.ErrServerClosed) && ctx.Err() != nil {
+				// Closed because the other listener failed.
+				return nil
+			}
+			return err
+		})
+	}
+	return group.Wait()
+}
+
+// handleCABundle serves the CA bundle at caBundlePath so that firmware and iPXE can enroll the
+// CA the HTTPS listeners' certificates are issued by. The file is read on every request, so a
+// rotated bundle is served without a restart.
+func handleCABundle(w http.ResponseWriter, r *http.Request, caBundlePath string, log logr.Logger) {
+	bundle, err := os.ReadFile(caBundlePath)
+	if err != nil {
+		log.Error(err, "Failed to read CA bundle", "path", caBundlePath)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Path == CABundleDERPath {
+		block, _ := pem.Decode(bundle)
+		if block == nil || block.Type != "CERTIFICATE" {
+			log.Error(fmt.Errorf("no PEM encoded certificate found"), "Invalid CA bundle", "path", caBundlePath)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pkix-cert")
+		bundle = block.Bytes
+	} else {
+		w.Header().Set("Content-Type", "application/x-pem-file")
+	}
+	if _, err := w.Write(bundle); err != nil {
+		log.Info("Failed to write CA bundle", "error", err)
+	}
+}
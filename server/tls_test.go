@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLS", func() {
+	var (
+		certificate tls.Certificate
+		certPEM     []byte
+	)
+
+	freeAddress := func() string {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = l.Close() }()
+		return l.Addr().String()
+	}
+
+	BeforeEach(func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).NotTo(HaveOccurred())
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		Expect(err).NotTo(HaveOccurred())
+		certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+		certificate, err = tls.X509KeyPair(certPEM, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("serves over HTTP and HTTPS", func() {
+		httpAddr, httpsAddr := freeAddress(), freeAddress()
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "hello")
+		})
+		go func() {
+			_ = listenAndServe(httpAddr, handler, &TLSOptions{
+				Address: httpsAddr,
+				TLSOpts: []func(*tls.Config){func(c *tls.Config) {
+					c.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return &certificate, nil }
+				}},
+			}, logr.Discard())
+		}()
+
+		roots := x509.NewCertPool()
+		Expect(roots.AppendCertsFromPEM(certPEM)).To(BeTrue())
+		httpsClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+		for client, url := range map[*http.Client]string{http.DefaultClient: "http://" + httpAddr, httpsClient: "https://" + httpsAddr} {
+			Eventually(func(g Gomega) {
+				resp, err := client.Get(url)
+				g.Expect(err).NotTo(HaveOccurred())
+				defer func() { _ = resp.Body.Close() }()
+				body, err := io.ReadAll(resp.Body)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(string(body)).To(Equal("hello"))
+			}, "5s", "100ms").Should(Succeed())
+		}
+	})
+
+	It("stops the HTTP listener if the HTTPS listener fails", func() {
+		httpAddr := freeAddress()
+		err := listenAndServe(httpAddr, http.NotFoundHandler(), &TLSOptions{Address: "invalid:address"}, logr.Discard())
+		Expect(err).To(HaveOccurred())
+
+		l, err := net.Listen("tcp", httpAddr)
+		Expect(err).NotTo(HaveOccurred(), "the HTTP listener should have been closed")
+		Expect(l.Close()).To(Succeed())
+	})
+
+	Context("CA bundle", func() {
+		var caBundlePath string
+
+		BeforeEach(func() {
+			caBundlePath = filepath.Join(GinkgoT().TempDir(), "ca.crt")
+			Expect(os.WriteFile(caBundlePath, certPEM, 0o600)).To(Succeed())
+		})
+
+		It("serves the bundle in PEM format", func() {
+			rec := httptest.NewRecorder()
+			handleCABundle(rec, httptest.NewRequest(http.MethodGet, CABundlePEMPath, nil), caBundlePath, logr.Discard())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.Bytes()).To(Equal(certPEM))
+		})
+
+		It("serves the first certificate in DER format", func() {
+			rec := httptest.NewRecorder()
+			handleCABundle(rec, httptest.NewRequest(http.MethodGet, CABundleDERPath, nil), caBundlePath, logr.Discard())
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Header().Get("Content-Type")).To(Equal("application/pkix-cert"))
+			Expect(rec.Body.Bytes()).To(Equal(certificate.Certificate[0]))
+		})
+
+		It("fails if the bundle cannot be read", func() {
+			Expect(os.Remove(caBundlePath)).To(Succeed())
+			rec := httptest.NewRecorder()
+			handleCABundle(rec, httptest.NewRequest(http.MethodGet, CABundlePEMPath, nil), caBundlePath, logr.Discard())
+			Expect(rec.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+})
@@ -3,4 +3,12 @@
 set ipxe-svc {{.IPXEServerURL}}
 
 set base-url ${ipxe-svc}/ipxe
-chain --replace --autofree ${base-url}/${uuid}
\ No newline at end of file
+set system-id ${uuid}
+isset ${uuid} || set system-id mac/${netX/mac}
+iseq ${uuid} 00000000-0000-0000-0000-000000000000 && set system-id mac/${netX/mac} ||
+{{if .Signed}}imgtrust
+imgfetch --name script ${base-url}/${system-id}
+imgverify script ${base-url}/${system-id}.sig
+chain --replace --autofree script
+{{else}}chain --replace --autofree ${base-url}/${system-id}
+{{end}}
\ No newline at end of file
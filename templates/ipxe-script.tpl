@@ -4,10 +4,13 @@ set ipxe-svc {{.IPXEServerURL}}
 set kernel-url {{.KernelURL}}
 set initrd-url {{.InitrdURL}}
 {{if .SquashfsURL}}set squashfs-url {{.SquashfsURL}}
+{{end}}{{if .Signed}}imgtrust
 {{end}}
 echo Loading kernel...
-kernel ${kernel-url} initrd=initrd{{if .SquashfsURL}} gl.ovl=/:tmpfs gl.url=${squashfs-url} gl.live=1{{end}} ip=any ignition.firstboot=1 ignition.config.url=${ipxe-svc}/ignition/${uuid} ignition.platform.id=metal console=ttyS0,115200 console=tty0 console=ttyAMA0 earlyprintk=ttyS0,115200 consoleblank=0
-echo Loading initrd...
-initrd ${initrd-url}
-echo Booting...
+kernel {{if .Signed}}--name kernel {{end}}${kernel-url} {{.KernelCommandLine}}
+{{if .Signed}}imgverify kernel {{.KernelSignatureURL}}
+{{end}}echo Loading initrd...
+initrd {{if .Signed}}--name initrd {{end}}${initrd-url}
+{{if .Signed}}imgverify initrd {{.InitrdSignatureURL}}
+{{end}}echo Booting...
 boot
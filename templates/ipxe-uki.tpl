@@ -0,0 +1,3 @@
+#!ipxe
+
+chain --autofree {{.UKIURL}}
@@ -0,0 +1,12 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company and IronCore contributors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package templates embeds the built-in iPXE script templates into the binary.
+package templates
+
+import "embed"
+
+// FS contains the built-in iPXE script templates.
+//
+//go:embed *.tpl
+var FS embed.FS